@@ -1,15 +1,45 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
+	"os/signal"
+	"time"
 
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/perf"
 	"github.com/SakshhamTheCoder/adbt/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	serial := flag.String("s", "", "device serial (defaults to the only connected device)")
+	record := flag.String("record", "", "record performance samples to a .csv or .jsonl file instead of starting the UI")
+	interval := flag.Duration("interval", time.Second, "sampling interval for -record")
+	duration := flag.Duration("duration", 0, "stop -record after this long (0 records until interrupted)")
+	watch := flag.String("watch", "", "reinstall and relaunch an APK, or the newest APK under a build output directory, whenever it changes")
+	user := flag.Int("user", 0, "user to install and launch for with -watch")
+	flag.Parse()
+
+	if *watch != "" {
+		if err := runWatch(*serial, *watch, *user); err != nil {
+			log.Printf("Error: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *record != "" {
+		if err := runRecord(*serial, *record, *interval, *duration); err != nil {
+			log.Printf("Error: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	p := tea.NewProgram(
 		ui.NewApp(),
 		tea.WithAltScreen(),
@@ -21,3 +51,27 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func runRecord(serial, path string, interval, duration time.Duration) error {
+	serial, err := adb.ResolveSerial(serial)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return perf.RecordSession(ctx, serial, path, interval, duration, os.Stdout)
+}
+
+func runWatch(serial, path string, user int) error {
+	serial, err := adb.ResolveSerial(serial)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return adb.WatchSession(ctx, serial, path, user, os.Stdout)
+}
@@ -0,0 +1,100 @@
+package adb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StartResult is the parsed output of `am start -W`. Times are in
+// milliseconds and zero when the device did not report them.
+//
+// `am` exits 0 even when it refuses an intent, so Error is how a failure
+// shows up. Warning is set when the intent was delivered but did not
+// start a new activity, e.g. to an instance already on top.
+type StartResult struct {
+	Status      string
+	LaunchState string
+	Activity    string
+	ThisTime    int
+	TotalTime   int
+	WaitTime    int
+	Error       string
+	Warning     string
+}
+
+// Failed reports whether `am` rejected the intent.
+func (r StartResult) Failed() bool {
+	return r.Error != ""
+}
+
+// ParseStartResult parses `am start -W` output:
+//
+//	Status: ok
+//	LaunchState: COLD
+//	Activity: com.example/.MainActivity
+//	TotalTime: 512
+//	WaitTime: 520
+//	Complete
+//
+// Error and warning lines from the other `am` commands, and exceptions
+// thrown by `am` itself, are picked up as well.
+func ParseStartResult(output string) StartResult {
+	var r StartResult
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "Error type"):
+			continue
+		case strings.HasPrefix(line, "Error:"):
+			if r.Error == "" {
+				r.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
+			}
+			continue
+		case strings.HasPrefix(line, "Warning:"):
+			if r.Warning == "" {
+				r.Warning = strings.TrimSpace(strings.TrimPrefix(line, "Warning:"))
+			}
+			continue
+		case strings.HasPrefix(line, "Security exception:"), isExceptionLine(line):
+			if r.Error == "" {
+				r.Error = line
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "Status":
+			r.Status = value
+		case "LaunchState":
+			r.LaunchState = value
+		case "Activity":
+			r.Activity = value
+		case "ThisTime":
+			r.ThisTime, _ = strconv.Atoi(value)
+		case "TotalTime":
+			r.TotalTime, _ = strconv.Atoi(value)
+		case "WaitTime":
+			r.WaitTime, _ = strconv.Atoi(value)
+		}
+	}
+
+	if r.Status == "timeout" && r.Warning == "" {
+		r.Warning = "timed out waiting for the launch to complete"
+	}
+	return r
+}
+
+// isExceptionLine matches a Java exception message such as
+// "java.lang.SecurityException: Permission Denial: ...".
+func isExceptionLine(line string) bool {
+	class, _, ok := strings.Cut(line, ": ")
+	return ok && strings.HasSuffix(class, "Exception") && !strings.ContainsAny(class, " \t")
+}
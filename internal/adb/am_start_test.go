@@ -0,0 +1,128 @@
+package adb
+
+import "testing"
+
+const startColdOutput = `Starting: Intent { act=android.intent.action.MAIN cat=[android.intent.category.LAUNCHER] cmp=com.example.app/.MainActivity }
+Status: ok
+LaunchState: COLD
+Activity: com.example.app/.MainActivity
+TotalTime: 512
+WaitTime: 520
+Complete
+`
+
+const startOnTopOutput = `Starting: Intent { act=android.intent.action.VIEW dat=myapp://orders/1 cmp=com.example.app/.MainActivity }
+Warning: Activity not started, intent has been delivered to currently running top-most instance.
+Status: ok
+LaunchState: UNKNOWN (0)
+Activity: com.example.app/.MainActivity
+WaitTime: 14
+Complete
+`
+
+const startMissingOutput = `Starting: Intent { cmp=com.example.app/.Missing }
+Error type 3
+Error: Activity class {com.example.app/com.example.app.Missing} does not exist.
+`
+
+const startSecurityOutput = `Starting: Intent { cmp=com.android.settings/.SubSettings }
+Exception occurred while executing 'start':
+java.lang.SecurityException: Permission Denial: starting Intent { flg=0x10000000 cmp=com.android.settings/.SubSettings } from null (pid=4242, uid=2000) not exported from uid 1000
+	at com.android.server.wm.ActivityTaskSupervisor.checkStartAnyActivityPermission(ActivityTaskSupervisor.java:1187)
+`
+
+const startTimeoutOutput = `Starting: Intent { cmp=com.example.app/.SlowActivity }
+Status: timeout
+LaunchState: UNKNOWN (0)
+Activity: com.example.app/.SlowActivity
+WaitTime: 10021
+Complete
+`
+
+const broadcastOutput = `Broadcasting: Intent { act=com.example.SYNC flg=0x400000 cmp=com.example.app/.SyncReceiver }
+Broadcast completed: result=0
+`
+
+func TestParseStartResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		want       StartResult
+		wantFailed bool
+	}{
+		{
+			name:   "cold start",
+			output: startColdOutput,
+			want:   StartResult{Status: "ok", LaunchState: "COLD", Activity: "com.example.app/.MainActivity", TotalTime: 512, WaitTime: 520},
+		},
+		{
+			name:   "delivered to top",
+			output: startOnTopOutput,
+			want: StartResult{
+				Status:      "ok",
+				LaunchState: "UNKNOWN (0)",
+				Activity:    "com.example.app/.MainActivity",
+				WaitTime:    14,
+				Warning:     "Activity not started, intent has been delivered to currently running top-most instance.",
+			},
+		},
+		{
+			name:       "missing activity",
+			output:     startMissingOutput,
+			want:       StartResult{Error: "Activity class {com.example.app/com.example.app.Missing} does not exist."},
+			wantFailed: true,
+		},
+		{
+			name:       "security exception",
+			output:     startSecurityOutput,
+			want:       StartResult{Error: "java.lang.SecurityException: Permission Denial: starting Intent { flg=0x10000000 cmp=com.android.settings/.SubSettings } from null (pid=4242, uid=2000) not exported from uid 1000"},
+			wantFailed: true,
+		},
+		{
+			name:   "timeout",
+			output: startTimeoutOutput,
+			want: StartResult{
+				Status:      "timeout",
+				LaunchState: "UNKNOWN (0)",
+				Activity:    "com.example.app/.SlowActivity",
+				WaitTime:    10021,
+				Warning:     "timed out waiting for the launch to complete",
+			},
+		},
+		{
+			name:   "broadcast",
+			output: broadcastOutput,
+		},
+		{
+			name:   "empty",
+			output: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseStartResult(tt.output)
+			if got != tt.want {
+				t.Errorf("ParseStartResult() = %+v, want %+v", got, tt.want)
+			}
+			if got.Failed() != tt.wantFailed {
+				t.Errorf("Failed() = %v, want %v", got.Failed(), tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestIsExceptionLine(t *testing.T) {
+	tests := map[string]bool{
+		"java.lang.SecurityException: Permission Denial":         true,
+		"android.content.ActivityNotFoundException: No Activity": true,
+		"Exception occurred while executing 'start':":            false,
+		"Broadcast completed: result=0":                          false,
+		"Some Exception: not a class":                            false,
+	}
+	for line, want := range tests {
+		if got := isExceptionLine(line); got != want {
+			t.Errorf("isExceptionLine(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
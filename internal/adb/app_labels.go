@@ -0,0 +1,100 @@
+package adb
+
+import (
+	"fmt"
+
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	appLabelsFile = "app_labels.json"
+	// appLabelBatch is how many labels one GetAppLabelsCmd reads from the
+	// device, so that the list fills in while the rest load.
+	appLabelBatch = 20
+)
+
+type AppLabelsLoadedMsg struct {
+	Serial string
+	Labels map[string]string
+	// Pending are the apps whose labels are still to be read.
+	Pending []App
+}
+
+// cachedLabel is a label read from one version of an APK. Updates move the
+// APK to a new path, so the path and version tell when to read it again.
+type cachedLabel struct {
+	APK     string `json:"apk"`
+	Version string `json:"version"`
+	Label   string `json:"label"`
+}
+
+// GetAppLabelsCmd returns the cached labels of apps and reads up to
+// appLabelBatch others from their APKs. Labels that fail to read are left
+// out and tried again on the next load.
+func GetAppLabelsCmd(serial string, apps []App) tea.Cmd {
+	return func() tea.Msg {
+		var cache map[string]map[string]cachedLabel
+		config.LoadJSON(appLabelsFile, &cache)
+		if cache == nil {
+			cache = map[string]map[string]cachedLabel{}
+		}
+		device := cache[serial]
+		if device == nil {
+			device = map[string]cachedLabel{}
+			cache[serial] = device
+		}
+
+		msg := AppLabelsLoadedMsg{Serial: serial, Labels: map[string]string{}}
+		read := 0
+		for _, app := range apps {
+			if c, ok := device[app.PackageName]; ok && c.APK == app.APKPath && c.Version == app.VersionCode {
+				msg.Labels[app.PackageName] = c.Label
+				continue
+			}
+			if app.APKPath == "" {
+				continue
+			}
+			if read == appLabelBatch {
+				msg.Pending = append(msg.Pending, app)
+				continue
+			}
+			read++
+
+			label, err := AppLabel(serial, app.APKPath)
+			if err != nil {
+				continue
+			}
+			msg.Labels[app.PackageName] = label
+			device[app.PackageName] = cachedLabel{APK: app.APKPath, Version: app.VersionCode, Label: label}
+		}
+
+		if read > 0 {
+			config.SaveJSON(appLabelsFile, cache)
+		}
+		return msg
+	}
+}
+
+// ApplyAppLabels fills in each app's label.
+func ApplyAppLabels(apps []App, labels map[string]string) {
+	for i := range apps {
+		apps[i].Label = labels[apps[i].PackageName]
+	}
+}
+
+// AppLabel reads the label of the APK at path on the device. Only the zip
+// directory, the manifest and resources.arsc are read, not the whole APK.
+func AppLabel(serial, path string) (string, error) {
+	f, err := openDeviceFile(serial, path)
+	if err != nil {
+		return "", err
+	}
+	label, err := apk.ReadLabel(f, f.size)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return label, nil
+}
@@ -0,0 +1,165 @@
+package adb
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// AppLinkDomain is a domain of a package's app links with its
+// verification state and whether the user selected the app for it.
+type AppLinkDomain struct {
+	Domain string
+	// State is "verified", "approved", "denied", "none" or a numeric
+	// verifier error code such as 1024.
+	State    string
+	Selected bool
+}
+
+func (d AppLinkDomain) Verified() bool {
+	return d.State == "verified" || d.State == "approved"
+}
+
+// AppLinks is a package's `pm get-app-links` report for one user.
+type AppLinks struct {
+	Package string
+	// Allowed is the user's "Open supported links" setting.
+	Allowed bool
+	Domains []AppLinkDomain
+	// LegacyStatus is set by Android 11 and lower, which report one
+	// status, such as "always" or "ask", for all of the package's domains.
+	LegacyStatus string
+}
+
+type AppLinksMsg struct {
+	Package string
+	Links   AppLinks
+	Error   error
+}
+
+func GetAppLinksCmd(serial, pkg string, user int) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, "shell", "pm", "get-app-links", pkg)
+		if err == nil {
+			err = commandError(out)
+		}
+		if err != nil {
+			return AppLinksMsg{Package: pkg, Error: err}
+		}
+		return AppLinksMsg{Package: pkg, Links: ParseAppLinks(string(out), user)}
+	}
+}
+
+// ParseAppLinks parses `pm get-app-links <pkg>` for user. Android 12 and
+// later print:
+//
+//	com.example:
+//	  ID: 01234567-89ab-cdef-0123-456789abcdef
+//	  Signatures: [...]
+//	  Domain verification state:
+//	    example.com: verified
+//	    www.example.com: 1024
+//	  User 0:
+//	    Verification link handling allowed: true
+//	    Selection state:
+//	      Disabled:
+//	        www.example.com
+//
+// Older releases print "Package:", "Domains:" and "Status:" lines.
+func ParseAppLinks(output string, user int) AppLinks {
+	links := AppLinks{Allowed: true}
+	index := map[string]int{}
+	section := ""
+	currentUser := -1
+	selected := false
+
+	for _, line := range ParseLines([]byte(output)) {
+		key, value, hasValue := strings.Cut(line, ": ")
+		value = strings.TrimSpace(value)
+
+		switch {
+		case line == "Domain verification state:":
+			section = "domains"
+		case strings.HasPrefix(line, "User ") && strings.HasSuffix(line, ":"):
+			currentUser, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "User "), ":"))
+			section = "user"
+		case line == "Selection state:":
+			section = "selection"
+		case section == "selection" && (line == "Enabled:" || line == "Disabled:"):
+			selected = line == "Enabled:"
+
+		case hasValue && key == "Package":
+			links.Package = value
+		case hasValue && key == "Domains":
+			for _, domain := range strings.Fields(value) {
+				links.Domains = append(links.Domains, AppLinkDomain{Domain: domain})
+			}
+		case hasValue && key == "Status":
+			status, _, _ := strings.Cut(value, " ")
+			links.LegacyStatus = status
+			links.Allowed = status != "never"
+			for i := range links.Domains {
+				links.Domains[i].Selected = status == "always"
+			}
+		case hasValue && key == "Verification link handling allowed":
+			if currentUser == user {
+				links.Allowed = value == "true"
+			}
+
+		case section == "" && strings.HasSuffix(line, ":") && !strings.Contains(line, " "):
+			links.Package = strings.TrimSuffix(line, ":")
+		case section == "domains" && hasValue:
+			index[key] = len(links.Domains)
+			links.Domains = append(links.Domains, AppLinkDomain{Domain: key, State: value})
+		case section == "selection" && currentUser == user:
+			if i, ok := index[line]; ok {
+				links.Domains[i].Selected = selected
+			}
+		}
+	}
+
+	return links
+}
+
+// ReverifyAppLinksCmd asks the domain verifier to check the package's
+// autoVerify domains again. It needs Android 12 or later.
+func ReverifyAppLinksCmd(serial, pkg string) tea.Cmd {
+	return appLinksActionCmd(serial, "re-verify", "pm", "verify-app-links", "--re-verify", pkg)
+}
+
+// SetAppLinkSelectionCmd selects or deselects the package for domain, or
+// for all of its domains when domain is "all".
+func SetAppLinkSelectionCmd(serial, pkg string, user int, domain string, enabled bool) tea.Cmd {
+	return appLinksActionCmd(serial, "set user selection",
+		"pm", "set-app-links-user-selection",
+		"--user", userArg(user),
+		"--package", pkg,
+		strconv.FormatBool(enabled),
+		domain,
+	)
+}
+
+// SetAppLinksAllowedCmd changes the user's "Open supported links" setting
+// for the package.
+func SetAppLinksAllowedCmd(serial, pkg string, user int, allowed bool) tea.Cmd {
+	return appLinksActionCmd(serial, "set link handling",
+		"pm", "set-app-links-allowed",
+		"--user", userArg(user),
+		"--package", pkg,
+		strconv.FormatBool(allowed),
+	)
+}
+
+func appLinksActionCmd(serial, action string, args ...string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, append([]string{"shell"}, args...)...)
+		if err == nil {
+			err = commandError(out)
+		}
+		if err != nil {
+			return AppActionErrorMsg{Action: action, Error: err}
+		}
+		return AppActionResultMsg{Action: action}
+	}
+}
@@ -10,8 +10,28 @@ import (
 
 type App struct {
 	PackageName string
+	// Label is the name shown in the launcher, read from the APK after the
+	// list has loaded. It is empty until then and for apps without one.
+	Label       string
 	APKPath     string
+	VersionCode string
+	UID         string
 	IsSystem    bool
+	Disabled    bool
+	// Uninstalled apps were removed for the current user but are still on
+	// the device, e.g. after `pm uninstall -k --user 0`.
+	Uninstalled bool
+
+	// AppStats load after the list and are zero until then.
+	AppStats
+}
+
+// Name is the app's label, or its package name when it has none.
+func (a App) Name() string {
+	if a.Label != "" {
+		return a.Label
+	}
+	return a.PackageName
 }
 
 type AppsLoadedMsg struct {
@@ -40,21 +60,36 @@ func ParseApps(output []byte) []App {
 			continue
 		}
 
-		line = strings.TrimPrefix(line, "package:")
+		// "package:<path>=<pkg> versionCode:<code> uid:<uid>" with -U and
+		// --show-versioncode. The path itself can contain "=".
+		fields := strings.Fields(strings.TrimPrefix(line, "package:"))
+		if len(fields) == 0 {
+			continue
+		}
 
-		parts := strings.LastIndex(line, "=")
+		parts := strings.LastIndex(fields[0], "=")
 		if parts == -1 {
 			continue
 		}
 
-		apkPath := line[:parts]
-		pkgName := line[parts+1:]
-
-		apps = append(apps, App{
-			PackageName: pkgName,
+		apkPath := fields[0][:parts]
+		app := App{
+			PackageName: fields[0][parts+1:],
 			APKPath:     apkPath,
 			IsSystem:    isSystemApp(apkPath),
-		})
+		}
+
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, ":")
+			switch key {
+			case "versionCode":
+				app.VersionCode = value
+			case "uid":
+				app.UID = value
+			}
+		}
+
+		apps = append(apps, app)
 	}
 
 	sort.Slice(apps, func(i, j int) bool {
@@ -64,116 +99,182 @@ func ParseApps(output []byte) []App {
 	return apps
 }
 
-func ListAppsCmd(serial string) tea.Cmd {
+func ListAppsCmd(serial string, user int) tea.Cmd {
 	return func() tea.Msg {
-		out, err := ExecuteCommand(
-			serial,
-			"shell",
-			"pm",
-			"list",
-			"packages",
-			"-f",
-		)
+		apps, err := ListApps(serial, user)
 		if err != nil {
 			return AppsLoadErrorMsg{Error: err}
 		}
 
 		return AppsLoadedMsg{
-			Apps: ParseApps(out),
+			Apps: apps,
 		}
 	}
 }
 
-func LaunchAppCmd(serial, pkg string) tea.Cmd {
-	return func() tea.Msg {
-		out, err := ExecuteCommand(serial, "shell", "cmd", "package", "resolve-activity", "--brief", "-a", "android.intent.action.MAIN", "-c", "android.intent.category.LAUNCHER", pkg)
+// ListApps lists the packages installed for user, including those
+// uninstalled for that user only, marking disabled and uninstalled ones.
+func ListApps(serial string, user int) ([]App, error) {
+	out, err := ExecuteCommand(serial, "shell", "pm", "list", "packages", "-f", "-U", "--show-versioncode", "--user", userArg(user))
+	apps := ParseApps(out)
+	if err != nil || len(apps) == 0 {
+		// --show-versioncode needs Android 9; older pm prints an error.
+		out, err = ExecuteCommand(serial, "shell", "pm", "list", "packages", "-f", "--user", userArg(user))
 		if err != nil {
-			out, err = ExecuteCommand(serial, "shell", "cmd", "package", "resolve-activity", "--brief", pkg)
-			if err != nil {
-				return AppActionErrorMsg{Action: "launch", Error: fmt.Errorf("failed to find activity: %w", err)}
-			}
+			return nil, err
 		}
+		apps = ParseApps(out)
+	}
+
+	installed := map[string]bool{}
+	for _, app := range apps {
+		installed[app.PackageName] = true
+	}
 
-		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-		var component string
-		for i := len(lines) - 1; i >= 0; i-- {
-			line := strings.TrimSpace(lines[i])
-			if strings.Contains(line, "/") {
-				component = line
-				break
+	// The extra lists only add state, so failures are not fatal.
+	if out, err := ExecuteCommand(serial, "shell", "pm", "list", "packages", "-f", "-u", "--user", userArg(user)); err == nil {
+		for _, app := range ParseApps(out) {
+			if !installed[app.PackageName] {
+				app.Uninstalled = true
+				apps = append(apps, app)
 			}
 		}
+	}
 
-		if component == "" {
-			return AppActionErrorMsg{Action: "launch", Error: fmt.Errorf("no launchable activity found for %s", pkg)}
+	if out, err := ExecuteCommand(serial, "shell", "pm", "list", "packages", "-d", "--user", userArg(user)); err == nil {
+		disabled := map[string]bool{}
+		for _, line := range ParseLines(out) {
+			disabled[strings.TrimPrefix(line, "package:")] = true
 		}
+		for i := range apps {
+			apps[i].Disabled = disabled[apps[i].PackageName]
+		}
+	}
 
-		_, err = ExecuteCommand(serial, "shell", "am", "start", "-n", component)
-		if err != nil {
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].PackageName < apps[j].PackageName
+	})
+	return apps, nil
+}
+
+func LaunchAppCmd(serial, pkg string, user int) tea.Cmd {
+	return func() tea.Msg {
+		if err := LaunchApp(serial, pkg, user); err != nil {
 			return AppActionErrorMsg{Action: "launch", Error: err}
 		}
 		return AppActionResultMsg{Action: "launch"}
 	}
 }
 
-func ForceStopAppCmd(serial, pkg string) tea.Cmd {
-	return func() tea.Msg {
-		_, err := ExecuteCommand(
-			serial,
-			"shell",
-			"am",
-			"force-stop",
-			pkg,
-		)
+// LaunchApp starts the package's launcher activity for user. `am` exits
+// 0 when it refuses the start, so its output decides the result.
+func LaunchApp(serial, pkg string, user int) error {
+	component, err := ResolveLaunchActivity(serial, pkg, user)
+	if err != nil {
+		return err
+	}
+
+	out, err := ExecuteCommand(serial, "shell", "am", "start", "--user", userArg(user), "-n", ShellQuote(component))
+	if err != nil {
+		return err
+	}
+	if result := ParseStartResult(string(out)); result.Failed() {
+		return fmt.Errorf("%s", result.Error)
+	}
+	return nil
+}
+
+// ResolveLaunchActivity returns the package's launcher activity as a
+// component name (pkg/.Activity).
+func ResolveLaunchActivity(serial, pkg string, user int) (string, error) {
+	out, err := ExecuteCommand(serial, "shell", "cmd", "package", "resolve-activity", "--brief", "--user", userArg(user), "-a", "android.intent.action.MAIN", "-c", "android.intent.category.LAUNCHER", pkg)
+	if err != nil {
+		out, err = ExecuteCommand(serial, "shell", "cmd", "package", "resolve-activity", "--brief", "--user", userArg(user), pkg)
 		if err != nil {
+			return "", fmt.Errorf("failed to find activity: %w", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.Contains(line, "/") {
+			return line, nil
+		}
+	}
+
+	return "", fmt.Errorf("no launchable activity found for %s", pkg)
+}
+
+func ForceStopAppCmd(serial, pkg string, user int) tea.Cmd {
+	return func() tea.Msg {
+		if err := ForceStopApp(serial, pkg, user); err != nil {
 			return AppActionErrorMsg{Action: "force stop", Error: err}
 		}
 		return AppActionResultMsg{Action: "force stop"}
 	}
 }
 
-func UninstallAppCmd(serial, pkg string) tea.Cmd {
+func ForceStopApp(serial, pkg string, user int) error {
+	_, err := ExecuteCommand(
+		serial,
+		"shell",
+		"am",
+		"force-stop",
+		"--user",
+		userArg(user),
+		pkg,
+	)
+	return err
+}
+
+// UninstallAppCmd removes pkg for user. The APK is deleted once no user
+// has the package installed.
+func UninstallAppCmd(serial, pkg string, user int) tea.Cmd {
 	return func() tea.Msg {
-		_, err := ExecuteCommand(
-			serial,
-			"uninstall",
-			pkg,
-		)
-		if err != nil {
+		if err := UninstallApp(serial, pkg, user); err != nil {
 			return AppActionErrorMsg{Action: "uninstall", Error: err}
 		}
 		return AppActionResultMsg{Action: "uninstall"}
 	}
 }
 
-func ClearAppDataCmd(serial, pkg string) tea.Cmd {
+func UninstallApp(serial, pkg string, user int) error {
+	out, err := ExecuteCommand(
+		serial,
+		"shell",
+		"pm",
+		"uninstall",
+		"--user",
+		userArg(user),
+		pkg,
+	)
+	if err == nil {
+		err = commandError(out)
+	}
+	return err
+}
+
+func ClearAppDataCmd(serial, pkg string, user int) tea.Cmd {
 	return func() tea.Msg {
-		_, err := ExecuteCommand(
-			serial,
-			"shell",
-			"pm",
-			"clear",
-			pkg,
-		)
-		if err != nil {
+		if err := ClearAppData(serial, pkg, user); err != nil {
 			return AppActionErrorMsg{Action: "clear data", Error: err}
 		}
 		return AppActionResultMsg{Action: "clear data"}
 	}
 }
 
-func InstallAppCmd(serial, localApkPath string) tea.Cmd {
-	return func() tea.Msg {
-		_, err := ExecuteCommand(
-			serial,
-			"install",
-			localApkPath,
-		)
-		if err != nil {
-			return AppActionErrorMsg{Action: "install", Error: err}
-		}
-		return AppActionResultMsg{Action: "install"}
-	}
+func ClearAppData(serial, pkg string, user int) error {
+	_, err := ExecuteCommand(
+		serial,
+		"shell",
+		"pm",
+		"clear",
+		"--user",
+		userArg(user),
+		pkg,
+	)
+	return err
 }
 
 func isSystemApp(apkPath string) bool {
@@ -0,0 +1,157 @@
+package adb
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dumpsysTimeLayout is how dumpsys prints local times.
+const dumpsysTimeLayout = "2006-01-02 15:04:05"
+
+// AppStats are app list details that take a few full dumps to collect.
+// Every field stays zero when its source is unavailable.
+type AppStats struct {
+	Debuggable bool
+	Installed  time.Time
+	Updated    time.Time
+	LastUsed   time.Time
+	// Size is the code, data and cache size from `dumpsys diskstats`,
+	// which the system refreshes about once a day.
+	Size int64
+}
+
+type AppStatsLoadedMsg struct {
+	Serial string
+	User   int
+	Stats  map[string]AppStats
+}
+
+func GetAppStatsCmd(serial string, user int) tea.Cmd {
+	return func() tea.Msg {
+		return AppStatsLoadedMsg{Serial: serial, User: user, Stats: GetAppStats(serial, user)}
+	}
+}
+
+// GetAppStats merges `dumpsys package packages`, `dumpsys diskstats` and
+// `dumpsys usagestats`. A dump that fails only leaves its fields empty.
+func GetAppStats(serial string, user int) map[string]AppStats {
+	stats := map[string]AppStats{}
+
+	if out, err := ExecuteCommand(serial, "shell", "dumpsys", "package", "packages"); err == nil {
+		for pkg, d := range ParseAllPackageDetails(string(out)) {
+			s := stats[pkg]
+			s.Debuggable = d.Debuggable()
+			s.Installed = parseDumpsysTime(d.FirstInstallTime)
+			s.Updated = parseDumpsysTime(d.LastUpdateTime)
+			stats[pkg] = s
+		}
+	}
+
+	if out, err := ExecuteCommand(serial, "shell", "dumpsys", "diskstats"); err == nil {
+		for pkg, size := range ParseDiskStats(string(out)) {
+			s := stats[pkg]
+			s.Size = size
+			stats[pkg] = s
+		}
+	}
+
+	if out, err := ExecuteCommand(serial, "shell", "dumpsys", "usagestats"); err == nil {
+		for pkg, used := range ParseUsageStats(string(out), user) {
+			s := stats[pkg]
+			s.LastUsed = used
+			stats[pkg] = s
+		}
+	}
+
+	return stats
+}
+
+// ApplyAppStats fills in each app's stats.
+func ApplyAppStats(apps []App, stats map[string]AppStats) {
+	for i := range apps {
+		apps[i].AppStats = stats[apps[i].PackageName]
+	}
+}
+
+// ParseDiskStats sums the app, data and cache sizes per package from the
+// parallel JSON arrays at the end of `dumpsys diskstats`:
+//
+//	Package Names: ["com.a","com.b"]
+//	App Sizes: [1024,2048]
+func ParseDiskStats(output string) map[string]int64 {
+	var names []string
+	var sizes [][]int64
+
+	for _, line := range ParseLines([]byte(output)) {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Package Names":
+			_ = json.Unmarshal([]byte(value), &names)
+		case "App Sizes", "App Data Sizes", "Cache Sizes":
+			var column []int64
+			if json.Unmarshal([]byte(value), &column) == nil {
+				sizes = append(sizes, column)
+			}
+		}
+	}
+
+	result := map[string]int64{}
+	for i, name := range names {
+		for _, column := range sizes {
+			if i < len(column) {
+				result[name] += column[i]
+			}
+		}
+	}
+	return result
+}
+
+var usageLineRe = regexp.MustCompile(`\bpackage=(\S+)\s.*?\blastTime(?:Used)?="([^"]+)"`)
+
+// ParseUsageStats returns the latest lastTimeUsed of each package across
+// the daily, weekly, monthly and yearly stats of user. Dumps without
+// "user=" headers are taken as the user's own.
+func ParseUsageStats(output string, user int) map[string]time.Time {
+	result := map[string]time.Time{}
+	currentUser := user
+
+	for _, line := range ParseLines([]byte(output)) {
+		if rest, ok := strings.CutPrefix(line, "user="); ok {
+			idField, _, _ := strings.Cut(rest, " ")
+			if id, err := strconv.Atoi(idField); err == nil {
+				currentUser = id
+			}
+			continue
+		}
+		if currentUser != user {
+			continue
+		}
+
+		m := usageLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		used := parseDumpsysTime(m[2])
+		if used.After(result[m[1]]) {
+			result[m[1]] = used
+		}
+	}
+	return result
+}
+
+func parseDumpsysTime(value string) time.Time {
+	t, err := time.ParseInLocation(dumpsysTimeLayout, strings.TrimSpace(value), time.Local)
+	if err != nil || t.Year() < 2000 {
+		// Unused apps report the epoch.
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,39 @@
+package adb
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BatchResult is the outcome of a batch action for one package.
+type BatchResult struct {
+	Package string
+	Error   error
+}
+
+type BatchDoneMsg struct {
+	Action  string
+	Results []BatchResult
+}
+
+// Failed counts the packages the action failed for.
+func (m BatchDoneMsg) Failed() int {
+	n := 0
+	for _, r := range m.Results {
+		if r.Error != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// BatchCmd runs action for each package in turn, so that one failure does
+// not stop the rest, and reports every result at the end.
+func BatchCmd(action string, pkgs []string, run func(pkg string) error) tea.Cmd {
+	return func() tea.Msg {
+		msg := BatchDoneMsg{Action: action, Results: make([]BatchResult, 0, len(pkgs))}
+		for _, pkg := range pkgs {
+			msg.Results = append(msg.Results, BatchResult{Package: pkg, Error: run(pkg)})
+		}
+		return msg
+	}
+}
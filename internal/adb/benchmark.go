@@ -0,0 +1,97 @@
+package adb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type BenchmarkMode string
+
+const (
+	BenchmarkCold BenchmarkMode = "cold"
+	BenchmarkWarm BenchmarkMode = "warm"
+)
+
+// benchmarkSettle is how long the device is left alone between stopping or
+// backgrounding the app and the next launch.
+const benchmarkSettle = 2 * time.Second
+
+type BenchmarkPreparedMsg struct {
+	Mode        BenchmarkMode
+	Component   string
+	VersionName string
+	VersionCode string
+	Error       error
+}
+
+type BenchmarkRunMsg struct {
+	Mode   BenchmarkMode
+	Run    int
+	Result StartResult
+	Error  error
+}
+
+// PrepareBenchmarkCmd resolves user's launcher activity and, for warm
+// starts, launches the app once so later runs find its process alive.
+func PrepareBenchmarkCmd(serial, pkg string, user int, mode BenchmarkMode) tea.Cmd {
+	return func() tea.Msg {
+		msg := BenchmarkPreparedMsg{Mode: mode}
+
+		component, err := ResolveLaunchActivity(serial, pkg, user)
+		if err != nil {
+			msg.Error = err
+			return msg
+		}
+		msg.Component = component
+		msg.VersionName, msg.VersionCode, _ = GetPackageVersion(serial, pkg)
+
+		if mode == BenchmarkWarm {
+			if _, err := ExecuteCommand(serial, "shell", "am", "start", "-W", "--user", userArg(user), "-n", ShellQuote(component)); err != nil {
+				msg.Error = fmt.Errorf("warm-up launch failed: %w", err)
+				return msg
+			}
+			time.Sleep(benchmarkSettle)
+		}
+
+		return msg
+	}
+}
+
+// BenchmarkRunCmd performs a single measured launch. Cold runs force-stop
+// the app first; warm runs only send it to the background.
+func BenchmarkRunCmd(serial, component string, user int, mode BenchmarkMode, run int) tea.Cmd {
+	return func() tea.Msg {
+		msg := BenchmarkRunMsg{Mode: mode, Run: run}
+		pkg, _, _ := strings.Cut(component, "/")
+
+		var err error
+		if mode == BenchmarkCold {
+			_, err = ExecuteCommand(serial, "shell", "am", "force-stop", "--user", userArg(user), pkg)
+		} else {
+			_, err = ExecuteCommand(serial, "shell", "input", "keyevent", "KEYCODE_HOME")
+		}
+		if err != nil {
+			msg.Error = err
+			return msg
+		}
+		time.Sleep(benchmarkSettle)
+
+		out, err := ExecuteCommand(serial, "shell", "am", "start", "-W", "--user", userArg(user), "-n", ShellQuote(component))
+		if err != nil {
+			msg.Error = err
+			return msg
+		}
+
+		msg.Result = ParseStartResult(string(out))
+		switch {
+		case msg.Result.Error != "":
+			msg.Error = fmt.Errorf("%s", msg.Result.Error)
+		case msg.Result.TotalTime == 0 && msg.Result.WaitTime == 0:
+			msg.Error = fmt.Errorf("no timing reported: %s", strings.TrimSpace(string(out)))
+		}
+		return msg
+	}
+}
@@ -23,6 +23,56 @@ func ExecuteCommand(serial string, args ...string) ([]byte, error) {
 	return out, nil
 }
 
+// ExecuteCommandWithInput is ExecuteCommand with input fed to the
+// command's stdin, e.g. for `adb shell perfetto -c -`.
+func ExecuteCommandWithInput(serial string, input []byte, args ...string) ([]byte, error) {
+	var cmdArgs []string
+	if serial != "" {
+		cmdArgs = append(cmdArgs, "-s", serial)
+	}
+	cmdArgs = append(cmdArgs, args...)
+
+	cmd := exec.Command("adb", cmdArgs...)
+	cmd.Stdin = bytes.NewReader(input)
+	out, err := cmd.CombinedOutput()
+
+	if err != nil {
+		return out, fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+	}
+	return out, nil
+}
+
+// ShellQuote quotes s for the device shell, which runs the arguments of
+// `adb shell` joined by spaces. Words without special characters are left
+// as they are.
+func ShellQuote(s string) string {
+	safe := func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("@%+=:,./-_", r)
+	}
+	if s != "" && strings.IndexFunc(s, func(r rune) bool { return !safe(r) }) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// commandError finds the failure in pm, am, cmd and appops output, which
+// exit 0 even when they fail. Only whole failure lines count, since am
+// echoes the intent back and a component may be named ErrorActivity:
+//
+//	Failure [DELETE_FAILED_INTERNAL_ERROR]
+//	Error: Activity class {com.example/com.example.Missing} does not exist.
+//	java.lang.SecurityException: Permission Denial: ...
+func commandError(out []byte) error {
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Error:") || strings.HasPrefix(line, "Failure") || isExceptionLine(line) {
+			return fmt.Errorf("%s", line)
+		}
+	}
+	return nil
+}
+
 func GetProperty(serial, prop string) (string, error) {
 	out, err := ExecuteCommand(serial, "shell", "getprop", prop)
 	if err != nil {
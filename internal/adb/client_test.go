@@ -0,0 +1,54 @@
+package adb
+
+import "testing"
+
+func TestCommandError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"pm success", "Success\n", ""},
+		{"am start", "Starting: Intent { cmp=com.example.app/.MainActivity }\n", ""},
+		{"component named like a failure", "Starting: Intent { cmp=com.example.app/.ErrorActivity }\n", ""},
+		{"broadcast to exception receiver", "Broadcasting: Intent { act=com.example.CRASH cmp=com.example.app/.ExceptionReceiver }\nBroadcast completed: result=0\n", ""},
+		{"service named failure", "Starting service: Intent { cmp=com.example.app/.FailureService }\n", ""},
+		{"empty", "", ""},
+		{"pm failure", "Failure [DELETE_FAILED_INTERNAL_ERROR]\n", "Failure [DELETE_FAILED_INTERNAL_ERROR]"},
+		{"am error", "Starting: Intent { cmp=com.example.app/.Missing }\nError type 3\nError: Activity class {com.example.app/com.example.app.Missing} does not exist.\n", "Error: Activity class {com.example.app/com.example.app.Missing} does not exist."},
+		{"exception", "Exception occurred while executing 'grant':\njava.lang.SecurityException: Package com.example.app has not requested permission android.permission.CAMERA\n\tat com.android.server.pm.permission.PermissionManagerServiceImpl.grantRuntimePermissionInternal(PermissionManagerServiceImpl.java:1370)\n", "java.lang.SecurityException: Package com.example.app has not requested permission android.permission.CAMERA"},
+		{"unknown package", "Error: package com.example.missing doesn't exist\n", "Error: package com.example.missing doesn't exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := commandError([]byte(tt.output))
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("commandError(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"com.example.app/.MainActivity": "com.example.app/.MainActivity",
+		"key=value,a:b@c%d+e":           "key=value,a:b@c%d+e",
+		"":                              "''",
+		"Hello world":                   "'Hello world'",
+		"it's":                          `'it'\''s'`,
+		"$(reboot)":                     "'$(reboot)'",
+		"a;b&c|d":                       "'a;b&c|d'",
+		"content://settings/system":     "content://settings/system",
+		"/sdcard/My Files/a.txt":        "'/sdcard/My Files/a.txt'",
+	}
+	for s, want := range tests {
+		if got := ShellQuote(s); got != want {
+			t.Errorf("ShellQuote(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package adb
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ComponentResultMsg carries the output of an action run against a single
+// app component, so the screen can show what `am` or `content` printed.
+type ComponentResultMsg struct {
+	Action    string
+	Component string
+	Output    string
+	Error     error
+}
+
+func componentCmd(serial, action, component string, args ...string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, args...)
+		if err == nil {
+			err = commandError(out)
+		}
+		return ComponentResultMsg{
+			Action:    action,
+			Component: component,
+			Output:    strings.TrimSpace(string(out)),
+			Error:     err,
+		}
+	}
+}
+
+// StartActivityCmd starts an activity by its "pkg/.Class" component name.
+func StartActivityCmd(serial string, user int, component string) tea.Cmd {
+	return componentCmd(serial, "start activity", component, "shell", "am", "start", "--user", userArg(user), "-n", ShellQuote(component))
+}
+
+func StartServiceCmd(serial string, user int, component string) tea.Cmd {
+	return componentCmd(serial, "start service", component, "shell", "am", "start-service", "--user", userArg(user), "-n", ShellQuote(component))
+}
+
+func StopServiceCmd(serial string, user int, component string) tea.Cmd {
+	return componentCmd(serial, "stop service", component, "shell", "am", "stopservice", "--user", userArg(user), "-n", ShellQuote(component))
+}
+
+// BroadcastCmd sends an explicit broadcast to a receiver. action may be
+// empty, but most receivers ignore intents without one.
+func BroadcastCmd(serial string, user int, component, action string) tea.Cmd {
+	args := []string{"shell", "am", "broadcast", "--user", userArg(user), "-n", ShellQuote(component)}
+	if action != "" {
+		args = append(args, "-a", ShellQuote(action))
+	}
+	return componentCmd(serial, "broadcast", component, args...)
+}
+
+// QueryProviderCmd runs `content query` against a provider authority.
+// Rows may contain "Error" or "Exception", so only the failure lines
+// `content` prints are treated as errors.
+func QueryProviderCmd(serial string, user int, authority string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, "shell", "content", "query", "--user", userArg(user), "--uri", ShellQuote("content://"+authority))
+		if err == nil {
+			err = contentError(string(out))
+		}
+		return ComponentResultMsg{
+			Action:    "query",
+			Component: authority,
+			Output:    strings.TrimSpace(string(out)),
+			Error:     err,
+		}
+	}
+}
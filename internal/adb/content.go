@@ -0,0 +1,396 @@
+package adb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ContentQuery is a `content query` against a provider URI.
+type ContentQuery struct {
+	URI string
+	// Projection lists columns, separated by commas or colons.
+	Projection string
+	Where      string
+	Sort       string
+	User       int
+}
+
+// ContentResult is a parsed `content query` output. Columns are in the
+// order they first appear, and rows without a column have an empty cell.
+type ContentResult struct {
+	Columns []string
+	Rows    [][]string
+}
+
+type ContentQueryMsg struct {
+	Query  ContentQuery
+	Result ContentResult
+	Error  error
+}
+
+// ContentWrite is a `content insert`, `update` or `delete`.
+type ContentWrite struct {
+	// Action is "insert", "update" or "delete".
+	Action string
+	URI    string
+	Binds  []Bind
+	Where  string
+	User   int
+}
+
+type ContentWriteMsg struct {
+	Write  ContentWrite
+	Output string
+	Error  error
+}
+
+// BindType is the type letter of a `content --bind` value.
+type BindType string
+
+const (
+	BindString  BindType = "s"
+	BindInt     BindType = "i"
+	BindLong    BindType = "l"
+	BindFloat   BindType = "f"
+	BindDouble  BindType = "d"
+	BindBoolean BindType = "b"
+	BindNull    BindType = "n"
+)
+
+// BindsSyntax is a one-line reminder of the ParseBinds syntax.
+const BindsSyntax = "name:s:value; count:i:3; enabled:b:true; note:n  (s i l f d b n)"
+
+// Bind is a column value for `content insert` and `update`.
+type Bind struct {
+	Column string
+	Type   BindType
+	Value  string
+}
+
+// ParseBinds parses binds separated by ";", each "column:type:value" as
+// `content` takes them, or "column:n" for NULL. A literal ";" is written
+// as "\;".
+//
+//	name:s:Jane Doe; age:i:42; vip:b:true; note:n
+func ParseBinds(spec string) ([]Bind, error) {
+	var binds []Bind
+	for _, part := range splitEscaped(spec, ';') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.SplitN(part, ":", 3)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("bind %q is not column:type:value", part)
+		}
+		b := Bind{Column: strings.TrimSpace(fields[0]), Type: BindType(strings.TrimSpace(fields[1]))}
+		if len(fields) == 3 {
+			b.Value = fields[2]
+		}
+		if b.Column == "" {
+			return nil, fmt.Errorf("bind %q has no column", part)
+		}
+		if len(fields) == 2 && b.Type != BindNull {
+			return nil, fmt.Errorf("bind %q has no value, use %s:n for NULL", part, b.Column)
+		}
+		if err := b.Validate(); err != nil {
+			return nil, err
+		}
+		binds = append(binds, b)
+	}
+	return binds, nil
+}
+
+// Validate checks that the value parses as the bind's type.
+func (b Bind) Validate() error {
+	var err error
+	switch b.Type {
+	case BindString:
+	case BindInt:
+		_, err = strconv.ParseInt(b.Value, 10, 32)
+	case BindLong:
+		_, err = strconv.ParseInt(b.Value, 10, 64)
+	case BindFloat:
+		_, err = strconv.ParseFloat(b.Value, 32)
+	case BindDouble:
+		_, err = strconv.ParseFloat(b.Value, 64)
+	case BindBoolean:
+		if b.Value != "true" && b.Value != "false" {
+			return fmt.Errorf("bind %q: %q is not true or false", b.Column, b.Value)
+		}
+	case BindNull:
+		if b.Value != "" {
+			return fmt.Errorf("bind %q: NULL takes no value", b.Column)
+		}
+	default:
+		return fmt.Errorf("bind %q has unknown type %q, use one of s i l f d b n", b.Column, b.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("bind %q: %q is not a valid %s", b.Column, b.Value, b.Type.Name())
+	}
+	return nil
+}
+
+// Name is the type's name in messages, e.g. "int".
+func (t BindType) Name() string {
+	switch t {
+	case BindString:
+		return "string"
+	case BindInt:
+		return "int"
+	case BindLong:
+		return "long"
+	case BindFloat:
+		return "float"
+	case BindDouble:
+		return "double"
+	case BindBoolean:
+		return "boolean"
+	case BindNull:
+		return "null"
+	}
+	return string(t)
+}
+
+// Arg is the --bind value, e.g. "name:s:Jane".
+func (b Bind) Arg() string {
+	if b.Type == BindNull {
+		return b.Column + ":n:"
+	}
+	return b.Column + ":" + string(b.Type) + ":" + b.Value
+}
+
+// Args returns the `adb shell` arguments for the query, quoted for the
+// device shell.
+func (q ContentQuery) Args() []string {
+	args := []string{"content", "query", "--uri", ShellQuote(q.URI)}
+	if q.User != 0 {
+		args = append(args, "--user", strconv.Itoa(q.User))
+	}
+	if projection := strings.Join(splitColumns(q.Projection), ":"); projection != "" {
+		args = append(args, "--projection", ShellQuote(projection))
+	}
+	if q.Where != "" {
+		args = append(args, "--where", ShellQuote(q.Where))
+	}
+	if q.Sort != "" {
+		args = append(args, "--sort", ShellQuote(q.Sort))
+	}
+	return args
+}
+
+func (q ContentQuery) Validate() error {
+	return validateContentURI(q.URI)
+}
+
+// Command is the query as one `adb shell` command line.
+func (q ContentQuery) Command() string {
+	return "adb shell " + strings.Join(q.Args(), " ")
+}
+
+// Validate catches writes that `content` would reject.
+func (w ContentWrite) Validate() error {
+	if err := validateContentURI(w.URI); err != nil {
+		return err
+	}
+	switch w.Action {
+	case "insert":
+		if len(w.Binds) == 0 {
+			return fmt.Errorf("insert needs at least one bind value")
+		}
+	case "update":
+		if len(w.Binds) == 0 {
+			return fmt.Errorf("update needs at least one bind value")
+		}
+	case "delete":
+	default:
+		return fmt.Errorf("unknown content action %q", w.Action)
+	}
+	return nil
+}
+
+// Args returns the `adb shell` arguments for the write, quoted for the
+// device shell.
+func (w ContentWrite) Args() []string {
+	args := []string{"content", w.Action, "--uri", ShellQuote(w.URI)}
+	if w.User != 0 {
+		args = append(args, "--user", strconv.Itoa(w.User))
+	}
+	if w.Action != "delete" {
+		for _, b := range w.Binds {
+			args = append(args, "--bind", ShellQuote(b.Arg()))
+		}
+	}
+	if w.Where != "" && w.Action != "insert" {
+		args = append(args, "--where", ShellQuote(w.Where))
+	}
+	return args
+}
+
+// Command is the write as one `adb shell` command line.
+func (w ContentWrite) Command() string {
+	return "adb shell " + strings.Join(w.Args(), " ")
+}
+
+func validateContentURI(uri string) error {
+	if !strings.HasPrefix(uri, "content://") || len(uri) == len("content://") {
+		return fmt.Errorf("%q is not a content:// URI", uri)
+	}
+	return nil
+}
+
+func splitColumns(spec string) []string {
+	return strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ':' || r == ' ' })
+}
+
+func QueryContentCmd(serial string, q ContentQuery) tea.Cmd {
+	return func() tea.Msg {
+		msg := ContentQueryMsg{Query: q}
+		if msg.Error = q.Validate(); msg.Error != nil {
+			return msg
+		}
+
+		out, err := ExecuteCommand(serial, append([]string{"shell"}, q.Args()...)...)
+		if err != nil {
+			msg.Error = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+			return msg
+		}
+		if msg.Error = contentError(string(out)); msg.Error != nil {
+			return msg
+		}
+		msg.Result = ParseContentRows(string(out), splitColumns(q.Projection))
+		return msg
+	}
+}
+
+func WriteContentCmd(serial string, w ContentWrite) tea.Cmd {
+	return func() tea.Msg {
+		msg := ContentWriteMsg{Write: w}
+		if msg.Error = w.Validate(); msg.Error != nil {
+			return msg
+		}
+
+		out, err := ExecuteCommand(serial, append([]string{"shell"}, w.Args()...)...)
+		msg.Output = strings.TrimSpace(string(out))
+		if err != nil {
+			msg.Error = fmt.Errorf("%w: %s", err, msg.Output)
+			return msg
+		}
+		msg.Error = contentError(msg.Output)
+		return msg
+	}
+}
+
+// contentError finds the failure in `content` output, which exits 0
+// even when the provider throws:
+//
+//	Error while accessing provider:settings
+//	java.lang.IllegalArgumentException: Invalid URI: content://settings/foo
+func contentError(output string) error {
+	var first string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if isExceptionLine(line) {
+			return fmt.Errorf("%s", line)
+		}
+		if first == "" && (strings.HasPrefix(line, "Error") || strings.HasPrefix(line, "Exception occurred")) {
+			first = line
+		}
+	}
+	if first != "" {
+		return fmt.Errorf("%s", first)
+	}
+	return nil
+}
+
+var (
+	contentRowRe = regexp.MustCompile(`^Row: \d+ ?`)
+	// contentColumnRe matches the ", column=" between two values. Values
+	// may contain ", " themselves, so a column name is only recognised
+	// when it looks like one.
+	contentColumnRe = regexp.MustCompile(`(?:^|, )([A-Za-z_][A-Za-z0-9_.]*)=`)
+)
+
+// ParseContentRows parses `content query` output:
+//
+//	Row: 0 _id=1, name=adb_enabled, value=1
+//	Row: 1 _id=2, name=airplane_mode_on, value=0
+//
+// Lines that do not start a row continue the previous value, which is how
+// multi-line values are printed. "No result found." yields no rows.
+//
+// `content` does not escape values, so "a, b=c" would read as a second
+// column b. When the projection is known, only its columns are split on.
+func ParseContentRows(output string, projection []string) ContentResult {
+	var res ContentResult
+	index := map[string]int{}
+	known := map[string]bool{}
+	for _, col := range projection {
+		known[col] = true
+	}
+	var row map[string]string
+	var lastColumn string
+
+	flush := func() {
+		if row == nil {
+			return
+		}
+		cells := make([]string, len(res.Columns))
+		for col, value := range row {
+			cells[index[col]] = value
+		}
+		res.Rows = append(res.Rows, cells)
+		row = nil
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		loc := contentRowRe.FindStringIndex(line)
+		if loc == nil {
+			if row != nil && lastColumn != "" {
+				row[lastColumn] += "\n" + line
+			}
+			continue
+		}
+
+		flush()
+		row = map[string]string{}
+		lastColumn = ""
+
+		body := line[loc[1]:]
+		var matches [][]int
+		for _, m := range contentColumnRe.FindAllStringSubmatchIndex(body, -1) {
+			if len(known) == 0 || known[body[m[2]:m[3]]] {
+				matches = append(matches, m)
+			}
+		}
+		for i, m := range matches {
+			col := body[m[2]:m[3]]
+			end := len(body)
+			if i+1 < len(matches) {
+				end = matches[i+1][0]
+			}
+			if _, ok := index[col]; !ok {
+				index[col] = len(res.Columns)
+				res.Columns = append(res.Columns, col)
+			}
+			row[col] = body[m[1]:end]
+			lastColumn = col
+		}
+	}
+	flush()
+
+	// Rows parsed before a later column first appeared are short.
+	for i, cells := range res.Rows {
+		for len(cells) < len(res.Columns) {
+			cells = append(cells, "")
+		}
+		res.Rows[i] = cells
+	}
+	return res
+}
@@ -0,0 +1,21 @@
+package adb
+
+// ContentPreset is a bundled query against a common system provider.
+type ContentPreset struct {
+	Name  string
+	Query ContentQuery
+}
+
+var ContentPresets = []ContentPreset{
+	{Name: "Settings: global", Query: ContentQuery{URI: "content://settings/global", Projection: "name,value", Sort: "name"}},
+	{Name: "Settings: secure", Query: ContentQuery{URI: "content://settings/secure", Projection: "name,value", Sort: "name"}},
+	{Name: "Settings: system", Query: ContentQuery{URI: "content://settings/system", Projection: "name,value", Sort: "name"}},
+	{Name: "Contacts", Query: ContentQuery{URI: "content://com.android.contacts/contacts", Projection: "_id,display_name,has_phone_number"}},
+	{Name: "Phone numbers", Query: ContentQuery{URI: "content://com.android.contacts/data/phones", Projection: "contact_id,display_name,data1"}},
+	{Name: "Call log", Query: ContentQuery{URI: "content://call_log/calls", Projection: "number,type,date,duration", Sort: "date DESC"}},
+	{Name: "SMS", Query: ContentQuery{URI: "content://sms", Projection: "_id,address,date,body", Sort: "date DESC"}},
+	{Name: "Calendar events", Query: ContentQuery{URI: "content://com.android.calendar/events", Projection: "_id,title,dtstart,dtend"}},
+	{Name: "Images", Query: ContentQuery{URI: "content://media/external/images/media", Projection: "_id,_display_name,_size,date_added", Sort: "date_added DESC"}},
+	{Name: "Downloads", Query: ContentQuery{URI: "content://media/external/downloads", Projection: "_id,_display_name,_size,date_added", Sort: "date_added DESC"}},
+	{Name: "User dictionary", Query: ContentQuery{URI: "content://user_dictionary/words", Projection: "_id,word,frequency,locale"}},
+}
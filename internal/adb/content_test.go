@@ -0,0 +1,269 @@
+package adb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const settingsQueryOutput = `Row: 0 _id=1, name=adb_enabled, value=1
+Row: 1 _id=7, name=device_name, value=Pixel 8, Jane's
+Row: 2 _id=9, name=bluetooth_name, value=NULL
+`
+
+const multiLineQueryOutput = `Row: 0 _id=12, address=+15551234, body=Meet at 5
+bring the keys
+Row: 1 _id=13, address=+15555678, body=ok
+`
+
+const lateColumnQueryOutput = `Row: 0 _id=1, name=a
+Row: 1 _id=2, name=b, extra=c
+`
+
+func TestParseContentRows(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		projection []string
+		want       ContentResult
+	}{
+		{
+			name:   "settings",
+			output: settingsQueryOutput,
+			want: ContentResult{
+				Columns: []string{"_id", "name", "value"},
+				Rows: [][]string{
+					{"1", "adb_enabled", "1"},
+					{"7", "device_name", "Pixel 8, Jane's"},
+					{"9", "bluetooth_name", "NULL"},
+				},
+			},
+		},
+		{
+			name:   "multi-line value",
+			output: multiLineQueryOutput,
+			want: ContentResult{
+				Columns: []string{"_id", "address", "body"},
+				Rows: [][]string{
+					{"12", "+15551234", "Meet at 5\nbring the keys"},
+					{"13", "+15555678", "ok"},
+				},
+			},
+		},
+		{
+			name:   "value that looks like a column",
+			output: "Row: 0 _id=5, name=greeting, value=Hello, world=1\n",
+			want: ContentResult{
+				Columns: []string{"_id", "name", "value", "world"},
+				Rows:    [][]string{{"5", "greeting", "Hello", "1"}},
+			},
+		},
+		{
+			name:       "projection keeps the value whole",
+			output:     "Row: 0 _id=5, name=greeting, value=Hello, world=1\n",
+			projection: []string{"_id", "name", "value"},
+			want: ContentResult{
+				Columns: []string{"_id", "name", "value"},
+				Rows:    [][]string{{"5", "greeting", "Hello, world=1"}},
+			},
+		},
+		{
+			name:   "late column pads earlier rows",
+			output: lateColumnQueryOutput,
+			want: ContentResult{
+				Columns: []string{"_id", "name", "extra"},
+				Rows:    [][]string{{"1", "a", ""}, {"2", "b", "c"}},
+			},
+		},
+		{
+			name:   "empty value and CRLF",
+			output: "Row: 0 _id=3, name=, value=x\r\n",
+			want: ContentResult{
+				Columns: []string{"_id", "name", "value"},
+				Rows:    [][]string{{"3", "", "x"}},
+			},
+		},
+		{
+			name:   "no result",
+			output: "No result found.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseContentRows(tt.output, tt.projection)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseContentRows() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Bind
+		wantErr string
+	}{
+		{
+			name: "every type",
+			spec: "name:s:Jane Doe; age:i:42; big:l:9000000000; ratio:f:0.5; score:d:1e300; vip:b:true; note:n",
+			want: []Bind{
+				{Column: "name", Type: BindString, Value: "Jane Doe"},
+				{Column: "age", Type: BindInt, Value: "42"},
+				{Column: "big", Type: BindLong, Value: "9000000000"},
+				{Column: "ratio", Type: BindFloat, Value: "0.5"},
+				{Column: "score", Type: BindDouble, Value: "1e300"},
+				{Column: "vip", Type: BindBoolean, Value: "true"},
+				{Column: "note", Type: BindNull},
+			},
+		},
+		{
+			name: "escaped separator and colons in value",
+			spec: `url:s:https://example.com/a\;b;; empty:s:`,
+			want: []Bind{
+				{Column: "url", Type: BindString, Value: "https://example.com/a;b"},
+				{Column: "empty", Type: BindString},
+			},
+		},
+		{name: "empty", spec: " ; "},
+		{name: "no type", spec: "name", wantErr: "is not column:type:value"},
+		{name: "no column", spec: ":s:x", wantErr: "has no column"},
+		{name: "no value", spec: "age:i", wantErr: "use age:n for NULL"},
+		{name: "bad int", spec: "age:i:forty", wantErr: `"forty" is not a valid int`},
+		{name: "int overflow", spec: "age:i:3000000000", wantErr: "is not a valid int"},
+		{name: "bad boolean", spec: "vip:b:1", wantErr: "is not true or false"},
+		{name: "null with value", spec: "note:n:x", wantErr: "NULL takes no value"},
+		{name: "unknown type", spec: "x:q:1", wantErr: `unknown type "q"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBinds(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseBinds(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBinds(%q): %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBinds(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentQueryArgs(t *testing.T) {
+	q := ContentQuery{
+		URI:        "content://settings/secure",
+		Projection: "name, value",
+		Where:      "name='android_id'",
+		Sort:       "name ASC",
+		User:       10,
+	}
+	want := []string{
+		"content", "query", "--uri", "content://settings/secure", "--user", "10",
+		"--projection", "name:value", "--where", `'name='\''android_id'\'''`, "--sort", "'name ASC'",
+	}
+	if got := q.Args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+	if got := (ContentQuery{URI: "content://sms"}).Args(); !reflect.DeepEqual(got, []string{"content", "query", "--uri", "content://sms"}) {
+		t.Errorf("Args() of a bare query = %q", got)
+	}
+}
+
+func TestContentWriteArgs(t *testing.T) {
+	binds := []Bind{{Column: "name", Type: BindString, Value: "Jane Doe"}, {Column: "note", Type: BindNull}}
+	tests := []struct {
+		name  string
+		write ContentWrite
+		want  []string
+	}{
+		{
+			name:  "insert ignores where",
+			write: ContentWrite{Action: "insert", URI: "content://com.example.provider/users", Binds: binds, Where: "_id=1"},
+			want:  []string{"content", "insert", "--uri", "content://com.example.provider/users", "--bind", "'name:s:Jane Doe'", "--bind", "note:n:"},
+		},
+		{
+			name:  "update",
+			write: ContentWrite{Action: "update", URI: "content://com.example.provider/users", Binds: binds[:1], Where: "_id = 1", User: 10},
+			want:  []string{"content", "update", "--uri", "content://com.example.provider/users", "--user", "10", "--bind", "'name:s:Jane Doe'", "--where", "'_id = 1'"},
+		},
+		{
+			name:  "delete ignores binds",
+			write: ContentWrite{Action: "delete", URI: "content://com.example.provider/users", Binds: binds, Where: "_id=1"},
+			want:  []string{"content", "delete", "--uri", "content://com.example.provider/users", "--where", "_id=1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.write.Args(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Args() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentWriteValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   ContentWrite
+		wantErr bool
+	}{
+		{"insert", ContentWrite{Action: "insert", URI: "content://a/b", Binds: []Bind{{Column: "x", Type: BindNull}}}, false},
+		{"delete without where", ContentWrite{Action: "delete", URI: "content://a/b"}, false},
+		{"insert without binds", ContentWrite{Action: "insert", URI: "content://a/b"}, true},
+		{"update without binds", ContentWrite{Action: "update", URI: "content://a/b", Where: "x=1"}, true},
+		{"not a content URI", ContentWrite{Action: "delete", URI: "file:///sdcard/a"}, true},
+		{"bare scheme", ContentWrite{Action: "delete", URI: "content://"}, true},
+		{"unknown action", ContentWrite{Action: "upsert", URI: "content://a/b"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.write.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContentError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"rows", settingsQueryOutput, ""},
+		{"no result", "No result found.\n", ""},
+		{
+			name:   "provider exception",
+			output: "Error while accessing provider:settings\njava.lang.IllegalArgumentException: Invalid URI: content://settings/foo\n\tat com.android.providers.settings.SettingsProvider.query(SettingsProvider.java:412)\n",
+			want:   "java.lang.IllegalArgumentException: Invalid URI: content://settings/foo",
+		},
+		{
+			name:   "unknown provider",
+			output: "Error while accessing provider:com.example.missing\n",
+			want:   "Error while accessing provider:com.example.missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := contentError(tt.output)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("contentError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
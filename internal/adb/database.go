@@ -0,0 +1,45 @@
+package adb
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type DatabasePulledMsg struct {
+	Remote string
+	Local  string
+	Error  error
+}
+
+// PullDatabaseCmd copies a database into localDir along with its -wal and
+// -shm files, which hold recent writes until SQLite checkpoints them.
+// Without a sandbox the file is read with `adb pull`.
+func PullDatabaseCmd(serial string, sandbox *Sandbox, remote, localDir string) tea.Cmd {
+	return func() tea.Msg {
+		local := filepath.Join(localDir, path.Base(remote))
+		msg := DatabasePulledMsg{Remote: remote, Local: local}
+
+		pull := func(remote, local string) error {
+			if sandbox != nil {
+				return PullSandboxFile(serial, *sandbox, remote, local)
+			}
+			_, err := ExecuteCommand(serial, "pull", remote, local)
+			return err
+		}
+
+		if msg.Error = pull(remote, local); msg.Error != nil {
+			return msg
+		}
+
+		// Either file may be missing, e.g. in rollback journal mode.
+		for _, suffix := range []string{"-wal", "-shm"} {
+			os.Remove(local + suffix)
+			_ = pull(remote+suffix, local+suffix)
+		}
+
+		return msg
+	}
+}
@@ -0,0 +1,101 @@
+package adb
+
+import (
+	"fmt"
+
+	"github.com/SakshhamTheCoder/adbt/internal/debloat"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DebloatMsg reports one package state change. Profile is set when the
+// change was part of applying or rolling back a debloat profile.
+type DebloatMsg struct {
+	Serial  string
+	User    int
+	Package string
+	Action  debloat.Action
+	Profile string
+	Error   error
+	// JournalError is set when the change could not be recorded.
+	JournalError error
+}
+
+func debloatArgs(pkg string, user int, action debloat.Action) []string {
+	switch action {
+	case debloat.Disable:
+		return []string{"shell", "pm", "disable-user", "--user", userArg(user), pkg}
+	case debloat.Enable:
+		return []string{"shell", "pm", "enable", "--user", userArg(user), pkg}
+	case debloat.Uninstall:
+		// -k keeps the data so install-existing can bring the app back.
+		return []string{"shell", "pm", "uninstall", "-k", "--user", userArg(user), pkg}
+	case debloat.Restore:
+		return []string{"shell", "cmd", "package", "install-existing", "--user", userArg(user), pkg}
+	}
+	return nil
+}
+
+// DebloatCmd runs RunDebloat, so the journal entry is kept even if no
+// screen handles the result.
+func DebloatCmd(serial string, user int, pkg string, action debloat.Action, profile string) tea.Cmd {
+	return func() tea.Msg {
+		return RunDebloat(serial, user, pkg, action, profile)
+	}
+}
+
+// RunDebloat runs Debloat and records the change in the journal.
+func RunDebloat(serial string, user int, pkg string, action debloat.Action, profile string) DebloatMsg {
+	msg := DebloatMsg{
+		Serial:  serial,
+		User:    user,
+		Package: pkg,
+		Action:  action,
+		Profile: profile,
+		Error:   Debloat(serial, user, pkg, action),
+	}
+	msg.JournalError = debloat.Record(msg.Entry())
+	return msg
+}
+
+// Err is the change's error, or the journal's when only recording it
+// failed.
+func (m DebloatMsg) Err() error {
+	if m.Error != nil {
+		return m.Error
+	}
+	if m.JournalError != nil {
+		return fmt.Errorf("%s successful, but the journal could not be written: %w", m.Action, m.JournalError)
+	}
+	return nil
+}
+
+// Entry is the journal entry of the change.
+func (m DebloatMsg) Entry() debloat.Entry {
+	entry := debloat.Entry{
+		Serial:  m.Serial,
+		User:    m.User,
+		Package: m.Package,
+		Action:  m.Action,
+		Profile: m.Profile,
+	}
+	if m.Error != nil {
+		entry.Error = m.Error.Error()
+	}
+	return entry
+}
+
+// Debloat changes pkg's state for user. Unlike DebloatCmd, it leaves
+// recording the change to the caller.
+func Debloat(serial string, user int, pkg string, action debloat.Action) error {
+	args := debloatArgs(pkg, user, action)
+	if args == nil {
+		return fmt.Errorf("unknown action %q", action)
+	}
+
+	out, err := ExecuteCommand(serial, args...)
+	if err == nil {
+		err = commandError(out)
+	}
+	return err
+}
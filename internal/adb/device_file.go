@@ -0,0 +1,86 @@
+package adb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// apkBlockSize is how much of a file one dd call reads.
+const apkBlockSize = 256 << 10
+
+// deviceFile reads a file on the device in apkBlockSize blocks with dd,
+// keeping the blocks it has read. Reading an APK through archive/zip then
+// fetches only its zip directory and the entries opened, not the whole
+// file.
+type deviceFile struct {
+	serial string
+	path   string
+	size   int64
+	blocks map[int64][]byte
+}
+
+func openDeviceFile(serial, path string) (*deviceFile, error) {
+	out, err := ExecuteCommand(serial, "shell", "stat", "-c", "%s", ShellQuote(path))
+	if err != nil {
+		return nil, err
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("size of %s: %q", path, bytes.TrimSpace(out))
+	}
+	return &deviceFile{serial: serial, path: path, size: size}, nil
+}
+
+func (f *deviceFile) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= f.size {
+			return n, io.EOF
+		}
+		index := pos / apkBlockSize
+		block, err := f.block(index)
+		if err != nil {
+			return n, err
+		}
+		start := pos - index*apkBlockSize
+		if start >= int64(len(block)) {
+			return n, io.ErrUnexpectedEOF
+		}
+		n += copy(p[n:], block[start:])
+	}
+	return n, nil
+}
+
+func (f *deviceFile) block(index int64) ([]byte, error) {
+	if b, ok := f.blocks[index]; ok {
+		return b, nil
+	}
+
+	args := []string{}
+	if f.serial != "" {
+		args = append(args, "-s", f.serial)
+	}
+	// exec-out mixes stderr into the data, so dd's record counts are
+	// dropped on the device.
+	args = append(args, "exec-out", "dd", ShellQuote("if="+f.path),
+		"bs="+strconv.Itoa(apkBlockSize), "skip="+strconv.FormatInt(index, 10), "count=1", "2>/dev/null")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("adb", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	if f.blocks == nil {
+		f.blocks = map[int64][]byte{}
+	}
+	f.blocks[index] = stdout.Bytes()
+	return stdout.Bytes(), nil
+}
@@ -3,7 +3,9 @@ package adb
 import (
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -96,6 +98,57 @@ func parseBattery(output string) (level, status string) {
 	return
 }
 
+// BatteryStats is a numeric battery sample, used for threshold alerts.
+type BatteryStats struct {
+	SampledAt    time.Time
+	Level        int
+	TemperatureC float64
+	Status       string
+}
+
+type BatteryStatsMsg struct {
+	Serial string
+	Stats  BatteryStats
+	Error  error
+}
+
+func GetBatteryStatsCmd(serial string) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := GetBatteryStats(serial)
+		return BatteryStatsMsg{Serial: serial, Stats: stats, Error: err}
+	}
+}
+
+func GetBatteryStats(serial string) (BatteryStats, error) {
+	out, err := ExecuteCommand(serial, "shell", "dumpsys", "battery")
+	if err != nil {
+		return BatteryStats{}, err
+	}
+
+	stats := BatteryStats{SampledAt: time.Now()}
+	_, stats.Status = parseBattery(string(out))
+
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "level":
+			stats.Level, _ = strconv.Atoi(value)
+		case "temperature":
+			// Reported in tenths of a degree Celsius.
+			if tenths, err := strconv.Atoi(value); err == nil {
+				stats.TemperatureC = float64(tenths) / 10
+			}
+		}
+	}
+
+	return stats, nil
+}
+
 func parseStorage(output string) (used, total string) {
 	lines := strings.Split(output, "\n")
 	if len(lines) < 2 {
@@ -25,44 +25,77 @@ type PairWirelessResultMsg struct {
 
 func ListDevicesCmd() tea.Cmd {
 	return func() tea.Msg {
-		out, err := ExecuteCommand("", "devices")
-		if err != nil {
-			return DevicesLoadedMsg{
-				Error: fmt.Errorf("failed to list devices: %w", err),
-			}
+		devices, err := ListDevices()
+		return DevicesLoadedMsg{Devices: devices, Error: err}
+	}
+}
+
+func ListDevices() ([]Device, error) {
+	out, err := ExecuteCommand("", "devices")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	lines := ParseLines(out)
+
+	if len(lines) > 0 && strings.Contains(
+		strings.ToLower(lines[0]),
+		"list of devices",
+	) {
+		lines = lines[1:]
+	}
+
+	devices := make([]Device, 0, len(lines))
+
+	for _, line := range lines {
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
 		}
 
-		lines := ParseLines(out)
+		device := Device{
+			Serial: parts[0],
+			State:  parts[1],
+		}
 
-		if len(lines) > 0 && strings.Contains(
-			strings.ToLower(lines[0]),
-			"list of devices",
-		) {
-			lines = lines[1:]
+		if device.State == "device" {
+			device.Model, _ = GetProperty(device.Serial, "ro.product.model")
+			device.Android, _ = GetProperty(device.Serial, "ro.build.version.release")
 		}
 
-		devices := make([]Device, 0, len(lines))
+		devices = append(devices, device)
+	}
 
-		for _, line := range lines {
-			parts := strings.Fields(line)
-			if len(parts) < 2 {
-				continue
-			}
+	return devices, nil
+}
 
-			device := Device{
-				Serial: parts[0],
-				State:  parts[1],
-			}
+// ResolveSerial returns serial unchanged when set, otherwise the serial of
+// the only connected device. Used by the command line modes, which have no
+// device picker.
+func ResolveSerial(serial string) (string, error) {
+	if serial != "" {
+		return serial, nil
+	}
 
-			if device.State == "device" {
-				device.Model, _ = GetProperty(device.Serial, "ro.product.model")
-				device.Android, _ = GetProperty(device.Serial, "ro.build.version.release")
-			}
+	devices, err := ListDevices()
+	if err != nil {
+		return "", err
+	}
 
-			devices = append(devices, device)
+	var connected []string
+	for _, d := range devices {
+		if d.IsConnected() {
+			connected = append(connected, d.Serial)
 		}
+	}
 
-		return DevicesLoadedMsg{Devices: devices}
+	switch len(connected) {
+	case 0:
+		return "", fmt.Errorf("no connected devices")
+	case 1:
+		return connected[0], nil
+	default:
+		return "", fmt.Errorf("multiple devices connected, pass -s <serial>")
 	}
 }
 
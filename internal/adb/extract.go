@@ -0,0 +1,125 @@
+package adb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// APKManifestFile is written next to extracted APKs.
+const APKManifestFile = "adbt-manifest.json"
+
+// APKManifest records where an extracted set of APKs came from.
+type APKManifest struct {
+	Package     string    `json:"package"`
+	VersionName string    `json:"version_name,omitempty"`
+	VersionCode string    `json:"version_code"`
+	Serial      string    `json:"device_serial"`
+	Model       string    `json:"device_model,omitempty"`
+	ExtractedAt time.Time `json:"extracted_at"`
+	Files       []string  `json:"files"`
+}
+
+type ExtractAPKMsg struct {
+	Package  string
+	Dir      string
+	Manifest APKManifest
+	Error    error
+}
+
+// ExtractAPKCmd pulls the base and split APKs of pkg into a new folder
+// under parentDir named after the package, versionCode and device.
+func ExtractAPKCmd(serial, model, pkg, parentDir string) tea.Cmd {
+	return func() tea.Msg {
+		dir, manifest, err := ExtractAPK(serial, model, pkg, parentDir)
+		return ExtractAPKMsg{Package: pkg, Dir: dir, Manifest: manifest, Error: err}
+	}
+}
+
+func ExtractAPK(serial, model, pkg, parentDir string) (string, APKManifest, error) {
+	paths, err := GetPackagePaths(serial, pkg)
+	if err != nil {
+		return "", APKManifest{}, err
+	}
+
+	manifest := APKManifest{
+		Package:     pkg,
+		Serial:      serial,
+		Model:       model,
+		ExtractedAt: time.Now(),
+	}
+	manifest.VersionName, manifest.VersionCode, _ = GetPackageVersion(serial, pkg)
+
+	device := model
+	if device == "" {
+		device = serial
+	}
+	name := config.SafeFileName(pkg)
+	if manifest.VersionCode != "" {
+		name += "-" + config.SafeFileName(manifest.VersionCode)
+	}
+	name += "-" + config.SafeFileName(device)
+
+	dir := filepath.Join(parentDir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", APKManifest{}, err
+	}
+
+	for _, remote := range paths {
+		file := path.Base(remote)
+		if _, err := ExecuteCommand(serial, "pull", remote, filepath.Join(dir, file)); err != nil {
+			return "", APKManifest{}, fmt.Errorf("failed to pull %s: %w", file, err)
+		}
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", APKManifest{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, APKManifestFile), append(data, '\n'), 0o644); err != nil {
+		return "", APKManifest{}, err
+	}
+
+	return dir, manifest, nil
+}
+
+// ReadAPKManifest reads the manifest of a folder created by ExtractAPKCmd.
+func ReadAPKManifest(dir string) (APKManifest, error) {
+	var manifest APKManifest
+
+	data, err := os.ReadFile(filepath.Join(dir, APKManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, fmt.Errorf("%s has no %s, not an extracted app", dir, APKManifestFile)
+		}
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("invalid %s: %w", APKManifestFile, err)
+	}
+	return manifest, nil
+}
+
+// RestorePaths returns the APKs listed in an extracted folder's manifest,
+// base APK first.
+func (m APKManifest) RestorePaths(dir string) []string {
+	paths := make([]string, 0, len(m.Files))
+	for _, f := range m.Files {
+		p := filepath.Join(dir, filepath.Base(f))
+		if strings.HasSuffix(f, "base.apk") {
+			paths = append([]string{p}, paths...)
+		} else {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
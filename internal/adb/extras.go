@@ -0,0 +1,198 @@
+package adb
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ExtraType selects the `am` option an intent extra is passed with.
+type ExtraType string
+
+const (
+	ExtraString      ExtraType = "s"
+	ExtraInt         ExtraType = "i"
+	ExtraBool        ExtraType = "z"
+	ExtraLong        ExtraType = "l"
+	ExtraFloat       ExtraType = "f"
+	ExtraURI         ExtraType = "u"
+	ExtraComponent   ExtraType = "cn"
+	ExtraStringArray ExtraType = "sa"
+	ExtraIntArray    ExtraType = "ia"
+	ExtraNull        ExtraType = "null"
+)
+
+// ExtrasSyntax is a one-line reminder of the ParseExtras syntax.
+const ExtrasSyntax = "key=v; i:n=1; z:b=true; l:, f:, u:, cn:, sa:a,b; ia:1,2; null:key"
+
+// Extra is an intent extra passed to `am start` or `am broadcast`.
+type Extra struct {
+	Type  ExtraType
+	Key   string
+	Value string
+}
+
+// ParseExtras parses extras separated by ";", each "type:key=value" with
+// the type optional for strings, or "null:key". A literal ";" is written
+// as "\;". Array values are comma separated, and "\," keeps a comma in a
+// string array element, as `am` does.
+//
+//	title=Hello world; i:count=3; z:debug=true; sa:tags=a,b; null:token
+func ParseExtras(spec string) ([]Extra, error) {
+	var extras []Extra
+	for _, part := range splitEscaped(spec, ';') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		extra, err := parseExtra(part)
+		if err != nil {
+			return nil, err
+		}
+		extras = append(extras, extra)
+	}
+	return extras, nil
+}
+
+func parseExtra(part string) (Extra, error) {
+	keyPart, value, hasValue := strings.Cut(part, "=")
+	extra := Extra{Type: ExtraString, Key: strings.TrimSpace(keyPart), Value: strings.TrimSpace(value)}
+
+	if typ, key, ok := strings.Cut(extra.Key, ":"); ok {
+		extra.Type = ExtraType(strings.TrimSpace(typ))
+		extra.Key = strings.TrimSpace(key)
+	}
+	if extra.Key == "" {
+		return Extra{}, fmt.Errorf("extra %q has no key", part)
+	}
+	if extra.Type == ExtraNull {
+		if hasValue {
+			return Extra{}, fmt.Errorf("null extra %q takes no value", extra.Key)
+		}
+		return extra, nil
+	}
+	if !hasValue {
+		return Extra{}, fmt.Errorf("extra %q has no value, use null:%s for a null extra", extra.Key, extra.Key)
+	}
+	return extra, extra.Validate()
+}
+
+// Validate checks that the value parses as the extra's type, so that `am`
+// does not reject or misread it on the device.
+func (e Extra) Validate() error {
+	invalid := func(hint string) error {
+		err := fmt.Errorf("extra %q: %q is not a valid %s", e.Key, e.Value, e.Type.Name())
+		if hint != "" {
+			err = fmt.Errorf("%w, %s", err, hint)
+		}
+		return err
+	}
+
+	switch e.Type {
+	case ExtraString, ExtraStringArray, ExtraNull:
+	case ExtraInt:
+		if _, err := strconv.ParseInt(e.Value, 10, 32); err != nil {
+			return invalid("")
+		}
+	case ExtraLong:
+		if _, err := strconv.ParseInt(e.Value, 10, 64); err != nil {
+			return invalid("")
+		}
+	case ExtraFloat:
+		if _, err := strconv.ParseFloat(e.Value, 32); err != nil {
+			return invalid("")
+		}
+	case ExtraBool:
+		if e.Value != "true" && e.Value != "false" {
+			return invalid("use true or false")
+		}
+	case ExtraURI:
+		if _, err := url.Parse(e.Value); err != nil {
+			return invalid("")
+		}
+	case ExtraComponent:
+		if pkg, class, ok := strings.Cut(e.Value, "/"); !ok || pkg == "" || class == "" {
+			return invalid("use pkg/.Class")
+		}
+	case ExtraIntArray:
+		for _, n := range strings.Split(e.Value, ",") {
+			if _, err := strconv.ParseInt(strings.TrimSpace(n), 10, 32); err != nil {
+				return invalid("")
+			}
+		}
+	default:
+		return fmt.Errorf("extra %q has unknown type %q", e.Key, e.Type)
+	}
+	return nil
+}
+
+// Name is the type's name in messages, e.g. "int".
+func (t ExtraType) Name() string {
+	switch t {
+	case ExtraString:
+		return "string"
+	case ExtraInt:
+		return "int"
+	case ExtraBool:
+		return "boolean"
+	case ExtraLong:
+		return "long"
+	case ExtraFloat:
+		return "float"
+	case ExtraURI:
+		return "URI"
+	case ExtraComponent:
+		return "component name"
+	case ExtraStringArray:
+		return "string array"
+	case ExtraIntArray:
+		return "int array"
+	case ExtraNull:
+		return "null"
+	}
+	return string(t)
+}
+
+// Args returns the `am` options for the extra, quoted for the device
+// shell.
+func (e Extra) Args() []string {
+	if e.Type == ExtraNull {
+		return []string{"--esn", ShellQuote(e.Key)}
+	}
+	value := e.Value
+	if e.Type == ExtraIntArray {
+		value = strings.ReplaceAll(value, " ", "")
+	}
+	return []string{"--e" + string(e.Type), ShellQuote(e.Key), ShellQuote(value)}
+}
+
+// ExtrasArgs returns the `am` options for all extras.
+func ExtrasArgs(extras []Extra) []string {
+	var args []string
+	for _, e := range extras {
+		args = append(args, e.Args()...)
+	}
+	return args
+}
+
+// splitEscaped splits s on sep, except where sep is preceded by a
+// backslash, and drops that backslash.
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && s[i+1] == sep {
+			current.WriteByte(sep)
+			i++
+			continue
+		}
+		if s[i] == sep {
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteByte(s[i])
+	}
+	return append(parts, current.String())
+}
@@ -0,0 +1,105 @@
+package adb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExtras(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Extra
+		wantErr string
+	}{
+		{
+			name: "every type",
+			spec: "title=Hello world; i:count=3; z:debug=true; l:id=9000000000; f:ratio=0.5; u:link=https://example.com/a?b=c; cn:target=com.example.app/.Main; sa:tags=a,b; ia:ids=1, 2; null:token",
+			want: []Extra{
+				{Type: ExtraString, Key: "title", Value: "Hello world"},
+				{Type: ExtraInt, Key: "count", Value: "3"},
+				{Type: ExtraBool, Key: "debug", Value: "true"},
+				{Type: ExtraLong, Key: "id", Value: "9000000000"},
+				{Type: ExtraFloat, Key: "ratio", Value: "0.5"},
+				{Type: ExtraURI, Key: "link", Value: "https://example.com/a?b=c"},
+				{Type: ExtraComponent, Key: "target", Value: "com.example.app/.Main"},
+				{Type: ExtraStringArray, Key: "tags", Value: "a,b"},
+				{Type: ExtraIntArray, Key: "ids", Value: "1, 2"},
+				{Type: ExtraNull, Key: "token"},
+			},
+		},
+		{
+			name: "escaped separator and empty parts",
+			spec: `query=a\;b;; note=x=y ;`,
+			want: []Extra{
+				{Type: ExtraString, Key: "query", Value: "a;b"},
+				{Type: ExtraString, Key: "note", Value: "x=y"},
+			},
+		},
+		{
+			name: "escaped comma kept for am",
+			spec: `sa:names=Doe\, Jane,Roe`,
+			want: []Extra{{Type: ExtraStringArray, Key: "names", Value: `Doe\, Jane,Roe`}},
+		},
+		{name: "empty", spec: "  "},
+		{name: "int out of range", spec: "i:count=3000000000", wantErr: `"3000000000" is not a valid int`},
+		{name: "bad boolean", spec: "z:debug=yes", wantErr: "use true or false"},
+		{name: "bad component", spec: "cn:target=com.example.app", wantErr: "use pkg/.Class"},
+		{name: "bad int array", spec: "ia:ids=1,x", wantErr: "not a valid int array"},
+		{name: "unknown type", spec: "q:x=1", wantErr: `unknown type "q"`},
+		{name: "no key", spec: "=value", wantErr: "has no key"},
+		{name: "no value", spec: "title", wantErr: "use null:title"},
+		{name: "null with value", spec: "null:token=x", wantErr: "takes no value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExtras(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseExtras(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseExtras(%q): %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExtras(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitEscaped(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"a;b;c", []string{"a", "b", "c"}},
+		{`a\;b;c`, []string{"a;b", "c"}},
+		{`a\,b;c`, []string{`a\,b`, "c"}},
+		{`trailing\`, []string{`trailing\`}},
+		{";", []string{"", ""}},
+		{"", []string{""}},
+	}
+
+	for _, tt := range tests {
+		if got := splitEscaped(tt.s, ';'); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitEscaped(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestExtrasArgs(t *testing.T) {
+	extras := []Extra{
+		{Type: ExtraString, Key: "title", Value: "It's here"},
+		{Type: ExtraIntArray, Key: "ids", Value: "1, 2"},
+		{Type: ExtraNull, Key: "token"},
+	}
+	want := []string{"--es", "title", `'It'\''s here'`, "--eia", "ids", "1,2", "--esn", "token"}
+	if got := ExtrasArgs(extras); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtrasArgs() = %q, want %q", got, want)
+	}
+}
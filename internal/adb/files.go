@@ -28,46 +28,50 @@ type FileActionResultMsg struct {
 
 func ListFilesCmd(serial, path string) tea.Cmd {
 	return func() tea.Msg {
-		listPath := path
-		if !strings.HasSuffix(listPath, "/") {
-			listPath += "/"
-		}
+		return listFiles(serial, path, "shell", "ls", "-la", dirArg(path))
+	}
+}
 
-		out, err := ExecuteCommand(
-			serial,
-			"shell",
-			"ls",
-			"-la",
-			listPath,
-		)
-		if err != nil {
-			return FilesLoadedMsg{
-				Path:  path,
-				Error: err,
-			}
+// listFiles runs an `ls -la` command and parses its entries as children
+// of path.
+func listFiles(serial, path string, args ...string) FilesLoadedMsg {
+	out, err := ExecuteCommand(serial, args...)
+	if err != nil {
+		return FilesLoadedMsg{
+			Path:  path,
+			Error: err,
 		}
+	}
 
-		lines := ParseLines(out)
-		files := make([]FileEntry, 0, len(lines))
-
-		for _, line := range lines {
-			if line == "" || strings.HasPrefix(line, "total") {
-				continue
-			}
-
-			entry := parseLsLine(line, path)
-			if entry.Name == "" || entry.Name == "." || entry.Name == ".." {
-				continue
-			}
+	lines := ParseLines(out)
+	files := make([]FileEntry, 0, len(lines))
 
-			files = append(files, entry)
+	for _, line := range lines {
+		if line == "" || strings.HasPrefix(line, "total") {
+			continue
 		}
 
-		return FilesLoadedMsg{
-			Path:  path,
-			Files: files,
+		entry := parseLsLine(line, path)
+		if entry.Name == "" || entry.Name == "." || entry.Name == ".." {
+			continue
 		}
+
+		files = append(files, entry)
+	}
+
+	return FilesLoadedMsg{
+		Path:  path,
+		Files: files,
+	}
+}
+
+// dirArg adds the trailing slash that makes ls follow a symlinked
+// directory such as /sdcard.
+func dirArg(path string) string {
+	if !strings.HasSuffix(path, "/") {
+		return path + "/"
 	}
+	return path
 }
 
 func parseLsLine(line, parentPath string) FileEntry {
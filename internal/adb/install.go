@@ -0,0 +1,293 @@
+package adb
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InstallOptions map to `adb install` flags.
+type InstallOptions struct {
+	Replace          bool // -r
+	Downgrade        bool // -d
+	GrantPermissions bool // -g
+	AllowTest        bool // -t
+	User             string
+}
+
+func (o InstallOptions) Args() []string {
+	var args []string
+	if o.Replace {
+		args = append(args, "-r")
+	}
+	if o.Downgrade {
+		args = append(args, "-d")
+	}
+	if o.GrantPermissions {
+		args = append(args, "-g")
+	}
+	if o.AllowTest {
+		args = append(args, "-t")
+	}
+	if o.User != "" {
+		args = append(args, "--user", o.User)
+	}
+	return args
+}
+
+// InstallPlan is the resolved set of files for one install.
+type InstallPlan struct {
+	APKs       []string
+	OBBs       []apk.OBB
+	Skipped    []string
+	TotalBytes int64
+	Options    InstallOptions
+
+	// TempDir holds extracted bundle contents and is removed after the
+	// install.
+	TempDir string
+}
+
+type InstallPreparedMsg struct {
+	Plan  InstallPlan
+	Error error
+}
+
+type InstallResultMsg struct {
+	Plan  InstallPlan
+	Error error
+}
+
+// InstallError is a parsed "Failure [CODE: message]" from the package
+// manager.
+type InstallError struct {
+	Code    string
+	Message string
+}
+
+func (e *InstallError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Message
+}
+
+var installHints = map[string]string{
+	"INSTALL_FAILED_ALREADY_EXISTS":           "already installed, enable Replace (-r)",
+	"INSTALL_FAILED_VERSION_DOWNGRADE":        "installed version is newer, enable Downgrade (-d)",
+	"INSTALL_FAILED_UPDATE_INCOMPATIBLE":      "signature differs from the installed app, uninstall it first",
+	"INSTALL_FAILED_TEST_ONLY":                "test-only APK, enable Allow Test (-t)",
+	"INSTALL_FAILED_NO_MATCHING_ABIS":         "no native libraries for the device's ABI",
+	"INSTALL_FAILED_INSUFFICIENT_STORAGE":     "not enough storage on the device",
+	"INSTALL_FAILED_OLDER_SDK":                "device API level is below the app's minSdk",
+	"INSTALL_FAILED_MISSING_SPLIT":            "a required split APK is missing",
+	"INSTALL_FAILED_INVALID_APK":              "APK is invalid or splits do not belong together",
+	"INSTALL_FAILED_DUPLICATE_PERMISSION":     "another app already defines one of its permissions",
+	"INSTALL_FAILED_USER_RESTRICTED":          "install blocked on the device, check USB install settings",
+	"INSTALL_FAILED_VERIFICATION_FAILURE":     "package verification failed on the device",
+	"INSTALL_PARSE_FAILED_NO_CERTIFICATES":    "APK is not signed",
+	"INSTALL_PARSE_FAILED_NOT_APK":            "file is not an APK",
+	"INSTALL_FAILED_CONFLICTING_PROVIDER":     "another app already uses one of its content provider authorities",
+	"INSTALL_FAILED_SHARED_USER_INCOMPATIBLE": "sharedUserId signature does not match",
+}
+
+// Hint suggests a fix for well-known failure codes.
+func (e *InstallError) Hint() string {
+	return installHints[e.Code]
+}
+
+var installFailureRe = regexp.MustCompile(`Failure \[([A-Z0-9_]+)(?::\s*([^\]]*))?\]`)
+
+// ParseInstallFailure extracts the failure reason from `adb install`
+// output. It returns nil when the output reports no failure.
+func ParseInstallFailure(output []byte) *InstallError {
+	if m := installFailureRe.FindSubmatch(output); m != nil {
+		return &InstallError{Code: string(m[1]), Message: strings.TrimSpace(string(m[2]))}
+	}
+
+	for _, line := range ParseLines(output) {
+		if rest, ok := strings.CutPrefix(line, "Failure"); ok {
+			return &InstallError{Code: "INSTALL_FAILED", Message: strings.Trim(rest, " []:")}
+		}
+	}
+	return nil
+}
+
+// GetDeviceSpec returns the ABIs and screen density used to pick splits.
+func GetDeviceSpec(serial string) apk.DeviceSpec {
+	var spec apk.DeviceSpec
+
+	abis, _ := GetProperty(serial, "ro.product.cpu.abilist")
+	if abis == "" {
+		abis, _ = GetProperty(serial, "ro.product.cpu.abi")
+	}
+	for _, abi := range strings.Split(abis, ",") {
+		if abi = strings.TrimSpace(abi); abi != "" {
+			spec.ABIs = append(spec.ABIs, abi)
+		}
+	}
+
+	// "Physical density: 440" optionally followed by "Override density: 400"
+	if out, err := ExecuteCommand(serial, "shell", "wm", "density"); err == nil {
+		for _, line := range ParseLines(out) {
+			_, value, ok := strings.Cut(line, "density:")
+			if !ok {
+				continue
+			}
+			if d, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				spec.Density = d
+			}
+		}
+	}
+	if spec.Density == 0 {
+		if value, err := GetProperty(serial, "ro.sf.lcd_density"); err == nil {
+			spec.Density, _ = strconv.Atoi(value)
+		}
+	}
+
+	return spec
+}
+
+// PrepareInstallCmd resolves install paths: single APKs, directories of
+// split APKs, or one .apks/.xapk bundle whose splits are chosen for the
+// device.
+func PrepareInstallCmd(serial string, paths []string, opts InstallOptions) tea.Cmd {
+	return func() tea.Msg {
+		plan, err := prepareInstall(serial, paths, opts)
+		return InstallPreparedMsg{Plan: plan, Error: err}
+	}
+}
+
+func prepareInstall(serial string, paths []string, opts InstallOptions) (InstallPlan, error) {
+	plan := InstallPlan{Options: opts}
+
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return plan, err
+		}
+
+		switch {
+		case info.IsDir():
+			apks, err := splitAPKsInDir(p)
+			if err != nil {
+				return plan, err
+			}
+			plan.APKs = append(plan.APKs, apks...)
+
+		case apk.IsBundle(p):
+			if len(paths) > 1 {
+				return plan, fmt.Errorf("install %s on its own", filepath.Base(p))
+			}
+			tmpDir, err := os.MkdirTemp("", "adbt-install-")
+			if err != nil {
+				return plan, err
+			}
+			plan.TempDir = tmpDir
+
+			bundle, err := apk.ExtractBundle(p, tmpDir, GetDeviceSpec(serial))
+			if err != nil {
+				os.RemoveAll(tmpDir)
+				return InstallPlan{}, err
+			}
+			plan.APKs = bundle.APKs
+			plan.OBBs = bundle.OBBs
+			plan.Skipped = bundle.Skipped
+
+		default:
+			plan.APKs = append(plan.APKs, p)
+		}
+	}
+
+	if len(plan.APKs) == 0 {
+		plan.Cleanup()
+		return InstallPlan{}, fmt.Errorf("no APKs to install")
+	}
+
+	for _, p := range plan.APKs {
+		if info, err := os.Stat(p); err == nil {
+			plan.TotalBytes += info.Size()
+		}
+	}
+
+	return plan, nil
+}
+
+// splitAPKsInDir returns the APKs in dir, base.apk first.
+func splitAPKsInDir(dir string) ([]string, error) {
+	apks, err := filepath.Glob(filepath.Join(dir, "*.apk"))
+	if err != nil {
+		return nil, err
+	}
+	if len(apks) == 0 {
+		return nil, fmt.Errorf("no APKs in %s", dir)
+	}
+
+	sort.SliceStable(apks, func(i, j int) bool {
+		return filepath.Base(apks[i]) == "base.apk" && filepath.Base(apks[j]) != "base.apk"
+	})
+	return apks, nil
+}
+
+func (p InstallPlan) Cleanup() {
+	if p.TempDir != "" {
+		os.RemoveAll(p.TempDir)
+	}
+}
+
+// InstallCmd installs a prepared plan with `install` or `install-multiple`
+// and pushes any OBB files.
+func InstallCmd(serial string, plan InstallPlan) tea.Cmd {
+	return func() tea.Msg {
+		defer plan.Cleanup()
+		return InstallResultMsg{Plan: plan, Error: install(serial, plan)}
+	}
+}
+
+func install(serial string, plan InstallPlan) error {
+	args := []string{"install"}
+	if len(plan.APKs) > 1 {
+		args = []string{"install-multiple"}
+	}
+	args = append(args, plan.Options.Args()...)
+	args = append(args, plan.APKs...)
+
+	out, err := ExecuteCommand(serial, args...)
+	// Older adb versions exit 0 on failure, so always check the output.
+	if failure := ParseInstallFailure(out); failure != nil {
+		return failure
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, obb := range plan.OBBs {
+		remote := path.Join(obbRoot(plan.Options.User), obb.Remote)
+		if _, err := ExecuteCommand(serial, "shell", "mkdir", "-p", path.Dir(remote)); err != nil {
+			return fmt.Errorf("failed to create %s: %w", path.Dir(remote), err)
+		}
+		if _, err := ExecuteCommand(serial, "push", obb.Local, remote); err != nil {
+			return fmt.Errorf("failed to push %s: %w", filepath.Base(obb.Local), err)
+		}
+	}
+
+	return nil
+}
+
+// obbRoot is the shared storage of the install's target user. /sdcard only
+// points at the shell user's own storage.
+func obbRoot(user string) string {
+	if user == "" || user == "0" {
+		return "/sdcard"
+	}
+	return path.Join("/storage/emulated", user)
+}
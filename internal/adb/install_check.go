@@ -0,0 +1,141 @@
+package adb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InstallCheck compares an install plan's base APK with the device and
+// the installed version of the same package.
+type InstallCheck struct {
+	APK apk.Info
+	// ABIs are the native ABIs across every APK in the plan, as splits
+	// usually carry the libraries.
+	ABIs []string
+
+	DeviceABIs []string
+	DeviceSDK  int
+
+	// Installed is false when the package is not on the device.
+	Installed            bool
+	InstalledVersionName string
+	InstalledVersionCode string
+
+	Warnings []string
+}
+
+type InstallCheckedMsg struct {
+	Plan  InstallPlan
+	Check InstallCheck
+}
+
+// CheckInstallCmd inspects the plan's APKs locally and warns about
+// downgrades, signature mismatches, unsupported ABIs and SDK levels. The
+// check never fails; problems reading the APK become warnings.
+func CheckInstallCmd(serial string, plan InstallPlan) tea.Cmd {
+	return func() tea.Msg {
+		return InstallCheckedMsg{Plan: plan, Check: checkInstall(serial, plan)}
+	}
+}
+
+func checkInstall(serial string, plan InstallPlan) InstallCheck {
+	var check InstallCheck
+	if len(plan.APKs) == 0 {
+		return check
+	}
+
+	info, err := apk.Inspect(plan.APKs[0])
+	check.APK = info
+	if err != nil {
+		check.Warnings = append(check.Warnings, "Could not read the APK: "+err.Error())
+		return check
+	}
+
+	seen := map[string]bool{}
+	for _, p := range plan.APKs {
+		abis, _ := apk.NativeABIs(p)
+		for _, abi := range abis {
+			if !seen[abi] {
+				seen[abi] = true
+				check.ABIs = append(check.ABIs, abi)
+			}
+		}
+	}
+
+	check.DeviceABIs = GetDeviceSpec(serial).ABIs
+	if sdk, err := GetProperty(serial, "ro.build.version.sdk"); err == nil {
+		check.DeviceSDK, _ = strconv.Atoi(sdk)
+	}
+
+	if len(check.ABIs) > 0 && len(check.DeviceABIs) > 0 && !anyIn(check.ABIs, check.DeviceABIs) {
+		check.Warnings = append(check.Warnings, fmt.Sprintf(
+			"No native libraries for the device's ABIs (%s); the APK has %s",
+			strings.Join(check.DeviceABIs, ", "),
+			strings.Join(check.ABIs, ", "),
+		))
+	}
+
+	if minSDK, err := strconv.Atoi(info.Manifest.MinSDK); err == nil && check.DeviceSDK > 0 && minSDK > check.DeviceSDK {
+		check.Warnings = append(check.Warnings, fmt.Sprintf(
+			"Requires API %d, the device runs API %d", minSDK, check.DeviceSDK,
+		))
+	}
+
+	pkg := info.Manifest.Package
+	details, err := GetPackageDetails(serial, pkg)
+	if err != nil {
+		return check
+	}
+	check.Installed = true
+	check.InstalledVersionName = details.VersionName
+	check.InstalledVersionCode = details.VersionCode
+
+	installedCode, err1 := strconv.ParseInt(details.VersionCode, 10, 64)
+	newCode, err2 := strconv.ParseInt(info.Manifest.VersionCode, 10, 64)
+	if err1 == nil && err2 == nil && newCode < installedCode {
+		warning := fmt.Sprintf("Downgrade from versionCode %d to %d", installedCode, newCode)
+		if !plan.Options.Downgrade {
+			warning += "; enable Downgrade (-d) or the install fails"
+		}
+		check.Warnings = append(check.Warnings, warning)
+	}
+
+	if len(info.Certificates) > 0 {
+		installed, err := packageCertificates(serial, pkg)
+		if err == nil && len(installed) > 0 && !sameSigner(info.Certificates, installed) {
+			check.Warnings = append(check.Warnings,
+				"Signed with a different certificate than the installed app; uninstall it first (this deletes its data)")
+		}
+	}
+
+	return check
+}
+
+// sameSigner reports whether two certificate lists share a SHA-256
+// digest. Key rotation (v3) can list more than one certificate.
+func sameSigner(a, b []apk.Certificate) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.SHA256 == y.SHA256 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyIn(values, set []string) bool {
+	for _, v := range values {
+		for _, s := range set {
+			if v == s {
+				return true
+			}
+		}
+	}
+	return false
+}
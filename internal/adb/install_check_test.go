@@ -0,0 +1,42 @@
+package adb
+
+import (
+	"testing"
+
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+)
+
+func TestSameSigner(t *testing.T) {
+	release := apk.Certificate{Scheme: "v2", SHA256: "AA:01"}
+	rotated := apk.Certificate{Scheme: "v3", SHA256: "BB:02"}
+	debug := apk.Certificate{Scheme: "v1", SHA256: "CC:03"}
+
+	tests := []struct {
+		name string
+		a, b []apk.Certificate
+		want bool
+	}{
+		{"same certificate, other scheme", []apk.Certificate{release}, []apk.Certificate{{Scheme: "v1", SHA256: "AA:01"}}, true},
+		{"rotated key lists the old one", []apk.Certificate{rotated, release}, []apk.Certificate{release}, true},
+		{"debug build over release", []apk.Certificate{debug}, []apk.Certificate{release}, false},
+		{"nothing installed", []apk.Certificate{release}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameSigner(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameSigner() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnyIn(t *testing.T) {
+	device := []string{"arm64-v8a", "armeabi-v7a", "armeabi"}
+	if !anyIn([]string{"x86_64", "armeabi-v7a"}, device) {
+		t.Error("anyIn() = false for a shared ABI")
+	}
+	if anyIn([]string{"x86", "x86_64"}, device) {
+		t.Error("anyIn() = true for an emulator-only APK on an arm device")
+	}
+}
@@ -0,0 +1,77 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInstallFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *InstallError
+	}{
+		{
+			name:   "success",
+			output: "Performing Streamed Install\nSuccess\n",
+		},
+		{
+			name:   "code and message",
+			output: "Performing Streamed Install\nadb: failed to install app.apk: Failure [INSTALL_FAILED_UPDATE_INCOMPATIBLE: Package com.example.app signatures do not match previously installed version; ignoring!]\n",
+			want: &InstallError{
+				Code:    "INSTALL_FAILED_UPDATE_INCOMPATIBLE",
+				Message: "Package com.example.app signatures do not match previously installed version; ignoring!",
+			},
+		},
+		{
+			name:   "code only",
+			output: "Performing Streamed Install\nadb: failed to install app.apk: Failure [INSTALL_FAILED_ALREADY_EXISTS]\n",
+			want:   &InstallError{Code: "INSTALL_FAILED_ALREADY_EXISTS"},
+		},
+		{
+			name:   "parse failure from install-multiple",
+			output: "adb: failed to finalize session\nFailure [INSTALL_PARSE_FAILED_NO_CERTIFICATES: Failed to collect certificates from /data/app/vmdl1.tmp/base.apk: Attempt to get length of null array]\n",
+			want: &InstallError{
+				Code:    "INSTALL_PARSE_FAILED_NO_CERTIFICATES",
+				Message: "Failed to collect certificates from /data/app/vmdl1.tmp/base.apk: Attempt to get length of null array",
+			},
+		},
+		{
+			name:   "failure without a code",
+			output: "Failure [not installed for 0]\n",
+			want:   &InstallError{Code: "INSTALL_FAILED", Message: "not installed for 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseInstallFailure([]byte(tt.output))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseInstallFailure() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallErrorHint(t *testing.T) {
+	err := &InstallError{Code: "INSTALL_FAILED_VERSION_DOWNGRADE", Message: "Downgrade detected"}
+	if got, want := err.Error(), "INSTALL_FAILED_VERSION_DOWNGRADE: Downgrade detected"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if err.Hint() == "" {
+		t.Error("Hint() is empty for INSTALL_FAILED_VERSION_DOWNGRADE")
+	}
+	if hint := (&InstallError{Code: "INSTALL_FAILED_INTERNAL_ERROR"}).Hint(); hint != "" {
+		t.Errorf("Hint() = %q for an unknown code, want none", hint)
+	}
+}
+
+func TestInstallOptionsArgs(t *testing.T) {
+	opts := InstallOptions{Replace: true, GrantPermissions: true, User: "10"}
+	if got, want := opts.Args(), []string{"-r", "-g", "--user", "10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+	if got := (InstallOptions{}).Args(); got != nil {
+		t.Errorf("Args() = %q, want none", got)
+	}
+}
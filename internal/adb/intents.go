@@ -2,76 +2,193 @@ package adb
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// IntentResultMsg is sent when `am` ran. Result holds the parsed output,
+// whose Error is set when `am` refused the intent despite exiting 0.
 type IntentResultMsg struct {
 	Output string
+	Result StartResult
 }
 
 type IntentErrorMsg struct {
-	Error error
+	Error  error
+	Result StartResult
 }
 
-func SendIntentCmd(serial, action, dataURI, extras string) tea.Cmd {
-	return func() tea.Msg {
-		args := []string{
-			"shell", "am", "start",
-			"-W",
-			"-a", action,
-		}
+// IntentMode is the `am` command an intent is sent with.
+type IntentMode string
+
+const (
+	IntentStart                  IntentMode = "start"
+	IntentBroadcast              IntentMode = "broadcast"
+	IntentStartService           IntentMode = "start-service"
+	IntentStartForegroundService IntentMode = "start-foreground-service"
+)
+
+// IntentModes lists the modes in the order the Intents screen cycles them.
+var IntentModes = []IntentMode{IntentStart, IntentBroadcast, IntentStartService, IntentStartForegroundService}
+
+// namedIntentFlags are the `am` options that set Intent flags by name,
+// without their "--activity-" or "--receiver-" prefix.
+var namedIntentFlags = map[string][]string{
+	"--activity-": {
+		"brought-to-front", "clear-task", "clear-top", "clear-when-task-reset",
+		"exclude-from-recents", "launch-adjacent", "launched-from-history",
+		"match-external", "multiple-task", "no-animation", "no-history",
+		"no-user-action", "previous-is-top", "reorder-to-front",
+		"reset-task-if-needed", "single-top", "task-on-home",
+	},
+	"--receiver-": {
+		"foreground", "include-background", "no-abort", "registered-only",
+		"replace-pending",
+	},
+}
+
+// Intent is everything the Intents screen can pass to `am`.
+type Intent struct {
+	Mode       IntentMode
+	Action     string
+	Data       string
+	MimeType   string
+	Component  string
+	Package    string
+	Categories []string
+	// Flags is the numeric -f value, zero when unset.
+	Flags int64
+	// NamedFlags are options such as "--activity-clear-top".
+	NamedFlags []string
+	Extras     []Extra
+	// User is a user id, "current" or "all". Empty leaves it to `am`.
+	User string
+}
+
+// ParseIntentFlags splits comma or space separated flags into a numeric
+// -f value, where numbers are ORed together, and named flags. Names may
+// omit their "--activity-" or "--receiver-" prefix:
+//
+//	0x10000000, clear-top, --receiver-foreground
+func ParseIntentFlags(spec string) (int64, []string, error) {
+	var flags int64
+	var named []string
 
-		if dataURI != "" {
-			args = append(args, "-d", dataURI)
+	for _, token := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if n, err := strconv.ParseInt(token, 0, 64); err == nil {
+			flags |= n
+			continue
 		}
+		name, err := namedIntentFlag(token)
+		if err != nil {
+			return 0, nil, err
+		}
+		named = append(named, name)
+	}
+	return flags, named, nil
+}
 
-		if extras != "" {
-			for _, extra := range strings.Split(extras, ";") {
-				extra = strings.TrimSpace(extra)
-				if extra == "" {
-					continue
-				}
-				parts := strings.SplitN(extra, "=", 2)
-				if len(parts) == 2 {
-					args = append(args, "--es", parts[0], parts[1])
-				}
+func namedIntentFlag(token string) (string, error) {
+	for prefix, names := range namedIntentFlags {
+		short := strings.TrimPrefix(token, prefix)
+		for _, name := range names {
+			if short == name {
+				return prefix + name, nil
 			}
 		}
+	}
+	return "", fmt.Errorf("unknown intent flag %q", token)
+}
 
-		out, err := ExecuteCommand(serial, args...)
-		if err != nil {
-			return IntentErrorMsg{Error: fmt.Errorf("%w: %s", err, string(out))}
+// Validate catches intents that `am` would reject or send somewhere other
+// than intended.
+func (i Intent) Validate() error {
+	if i.Action == "" && i.Data == "" && i.Component == "" && i.Package == "" {
+		return fmt.Errorf("set an action, data URI, component or package")
+	}
+	if i.Component != "" {
+		if pkg, class, ok := strings.Cut(i.Component, "/"); !ok || pkg == "" || class == "" {
+			return fmt.Errorf("component %q is not pkg/.Class", i.Component)
+		}
+	}
+	if i.User != "" && i.User != "current" && i.User != "all" {
+		if _, err := strconv.Atoi(i.User); err != nil {
+			return fmt.Errorf("user %q is not a user id, current or all", i.User)
 		}
-		return IntentResultMsg{Output: strings.TrimSpace(string(out))}
 	}
+	if i.User == "all" && i.Mode != IntentBroadcast {
+		return fmt.Errorf("--user all only works for broadcasts")
+	}
+	for _, name := range i.NamedFlags {
+		if i.Mode != IntentBroadcast && strings.HasPrefix(name, "--receiver-") {
+			return fmt.Errorf("%s only applies to broadcasts", name)
+		}
+	}
+	for _, e := range i.Extras {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func SendBroadcastCmd(serial, action, extras string) tea.Cmd {
-	return func() tea.Msg {
-		args := []string{
-			"shell", "am", "broadcast",
-			"-a", action,
-		}
+// Args returns the `adb shell` arguments for the intent, quoted for the
+// device shell. Activities are started with -W so that `am` reports the
+// launch result.
+func (i Intent) Args() []string {
+	mode := i.Mode
+	if mode == "" {
+		mode = IntentStart
+	}
+	args := []string{"am", string(mode)}
+	if mode == IntentStart {
+		args = append(args, "-W")
+	}
+	if i.User != "" {
+		args = append(args, "--user", i.User)
+	}
 
-		if extras != "" {
-			for _, extra := range strings.Split(extras, ";") {
-				extra = strings.TrimSpace(extra)
-				if extra == "" {
-					continue
-				}
-				parts := strings.SplitN(extra, "=", 2)
-				if len(parts) == 2 {
-					args = append(args, "--es", parts[0], parts[1])
-				}
-			}
+	for _, opt := range []struct{ flag, value string }{
+		{"-a", i.Action},
+		{"-d", i.Data},
+		{"-t", i.MimeType},
+		{"-n", i.Component},
+		{"-p", i.Package},
+	} {
+		if opt.value != "" {
+			args = append(args, opt.flag, ShellQuote(opt.value))
 		}
+	}
+	for _, c := range i.Categories {
+		args = append(args, "-c", ShellQuote(c))
+	}
+	if i.Flags != 0 {
+		args = append(args, "-f", fmt.Sprintf("0x%x", i.Flags))
+	}
+	args = append(args, i.NamedFlags...)
+	return append(args, ExtrasArgs(i.Extras)...)
+}
 
-		out, err := ExecuteCommand(serial, args...)
+// Command is the intent as one `adb shell` command line, for previews.
+func (i Intent) Command() string {
+	return "adb shell " + strings.Join(i.Args(), " ")
+}
+
+func SendCmd(serial string, intent Intent) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, append([]string{"shell"}, intent.Args()...)...)
+		result := ParseStartResult(string(out))
 		if err != nil {
-			return IntentErrorMsg{Error: fmt.Errorf("%w: %s", err, string(out))}
+			return IntentErrorMsg{Error: fmt.Errorf("%w: %s", err, string(out)), Result: result}
 		}
-		return IntentResultMsg{Output: strings.TrimSpace(string(out))}
+		return IntentResultMsg{Output: strings.TrimSpace(string(out)), Result: result}
 	}
 }
+
+// SendIntentCmd starts an activity with `am start -W`. extras come from
+// ParseExtras.
+func SendIntentCmd(serial, action, dataURI string, extras []Extra) tea.Cmd {
+	return SendCmd(serial, Intent{Mode: IntentStart, Action: action, Data: dataURI, Extras: extras})
+}
@@ -3,6 +3,7 @@ package adb
 import (
 	"bufio"
 	"os/exec"
+	"strings"
 	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -91,3 +92,29 @@ func (s *LogcatSession) Stop() error {
 
 	return s.cmd.Wait()
 }
+
+// PackagePIDs lists the running processes of pkg, including extra
+// processes declared with android:process such as "pkg:remote".
+func PackagePIDs(serial, pkg string) []string {
+	out, err := ExecuteCommand(serial, "shell", "ps", "-A", "-o", "PID,NAME")
+	if err != nil {
+		// Toolbox ps before Android 8 has no -A or -o.
+		out, err = ExecuteCommand(serial, "shell", "pidof", pkg)
+		if err != nil {
+			return nil
+		}
+		return strings.Fields(string(out))
+	}
+
+	var pids []string
+	for _, line := range ParseLines(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if name := fields[1]; name == pkg || strings.HasPrefix(name, pkg+":") {
+			pids = append(pids, fields[0])
+		}
+	}
+	return pids
+}
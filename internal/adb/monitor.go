@@ -3,6 +3,7 @@ package adb
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -10,6 +11,9 @@ import (
 // SystemStats holds raw counters from /proc files.
 // The UI layer is responsible for calculating rates/percentages by comparing with previous samples.
 type SystemStats struct {
+	// When the sample was taken, used to turn counter deltas into rates
+	SampledAt time.Time
+
 	// CPU counters (jiffies)
 	CPUTotal uint64
 	CPUIdle  uint64
@@ -19,9 +23,38 @@ type SystemStats struct {
 	MemUsed      uint64 // Total - Available
 	MemAvailable uint64 // MemFree + Buffers + Cached (approx)
 
-	// Network counters (bytes) - usually for wlan0 or total
+	// Network counters (bytes) - sum of all non-loopback interfaces
 	NetRxBytes uint64
 	NetTxBytes uint64
+
+	// Per-interface counters, in /proc/net/dev order
+	Interfaces []InterfaceStats
+
+	// Whether each source was read. A missing source leaves its fields
+	// zeroed, which must not be mistaken for a counter reset.
+	HasCPU bool
+	HasMem bool
+	HasNet bool
+}
+
+// Complete reports whether every source was read.
+func (s SystemStats) Complete() bool {
+	return s.HasCPU && s.HasMem && s.HasNet
+}
+
+// InterfaceStats holds the raw /proc/net/dev counters for a single interface.
+type InterfaceStats struct {
+	Name string
+
+	RxBytes   uint64
+	RxPackets uint64
+	RxErrors  uint64
+	RxDropped uint64
+
+	TxBytes   uint64
+	TxPackets uint64
+	TxErrors  uint64
+	TxDropped uint64
 }
 
 type SystemStatsMsg struct {
@@ -31,38 +64,48 @@ type SystemStatsMsg struct {
 
 func GetSystemStatsCmd(serial string) tea.Cmd {
 	return func() tea.Msg {
-		var stats SystemStats
-		var err error
-
-		// 1. CPU
-		out, err := ExecuteCommand(serial, "shell", "cat", "/proc/stat")
-		if err == nil {
-			localTotal, localIdle := parseCPUStats(string(out))
-			stats.CPUTotal = localTotal
-			stats.CPUIdle = localIdle
-		}
+		return SystemStatsMsg{Stats: GetSystemStats(serial)}
+	}
+}
 
-		// 2. Memory
-		out, err = ExecuteCommand(serial, "shell", "cat", "/proc/meminfo")
-		if err == nil {
-			t, a := parseMemInfo(string(out))
-			stats.MemTotal = t
-			stats.MemAvailable = a
-			if t > a {
-				stats.MemUsed = t - a
-			}
-		}
+// GetSystemStats samples /proc on the device. Sources that fail to read are
+// marked missing, so a single flaky cat does not drop the whole sample.
+func GetSystemStats(serial string) SystemStats {
+	stats := SystemStats{SampledAt: time.Now()}
+
+	// 1. CPU
+	out, err := ExecuteCommand(serial, "shell", "cat", "/proc/stat")
+	if err == nil {
+		localTotal, localIdle := parseCPUStats(string(out))
+		stats.CPUTotal = localTotal
+		stats.CPUIdle = localIdle
+		stats.HasCPU = localTotal > 0
+	}
 
-		// 3. Network
-		out, err = ExecuteCommand(serial, "shell", "cat", "/proc/net/dev")
-		if err == nil {
-			rx, tx := parseNetDev(string(out))
-			stats.NetRxBytes = rx
-			stats.NetTxBytes = tx
+	// 2. Memory
+	out, err = ExecuteCommand(serial, "shell", "cat", "/proc/meminfo")
+	if err == nil {
+		t, a := parseMemInfo(string(out))
+		stats.MemTotal = t
+		stats.MemAvailable = a
+		if t > a {
+			stats.MemUsed = t - a
 		}
+		stats.HasMem = t > 0
+	}
 
-		return SystemStatsMsg{Stats: stats}
+	// 3. Network
+	out, err = ExecuteCommand(serial, "shell", "cat", "/proc/net/dev")
+	if err == nil {
+		stats.Interfaces = parseNetDev(string(out))
+		for _, iface := range stats.Interfaces {
+			stats.NetRxBytes += iface.RxBytes
+			stats.NetTxBytes += iface.TxBytes
+		}
+		stats.HasNet = true
 	}
+
+	return stats
 }
 
 func parseCPUStats(output string) (total, idle uint64) {
@@ -136,12 +179,13 @@ func parseMemInfo(output string) (total, available uint64) {
 	return
 }
 
-func parseNetDev(output string) (rx, tx uint64) {
+func parseNetDev(output string) []InterfaceStats {
 	lines := strings.Split(output, "\n")
 	// Inter-face   |   Receive ... | Transmit ...
 	// wlan0: 123 456 ... or
 	//  wlan0:123 ... (no space after colon)
 
+	var ifaces []InterfaceStats
 	for _, line := range lines {
 		if !strings.Contains(line, ":") {
 			continue
@@ -157,15 +201,26 @@ func parseNetDev(output string) (rx, tx uint64) {
 		}
 
 		fields := strings.Fields(parts[1])
-		if len(fields) < 9 { // Receive: bytes(0), packets(1), errs(2), drop(3), fifo(4), frame(5), compressed(6), multicast(7) | Transmit: bytes(8)
+		if len(fields) < 12 { // Receive: bytes(0), packets(1), errs(2), drop(3), fifo(4), frame(5), compressed(6), multicast(7) | Transmit: bytes(8), packets(9), errs(10), drop(11)
 			continue
 		}
 
-		r, _ := strconv.ParseUint(fields[0], 10, 64)
-		t, _ := strconv.ParseUint(fields[8], 10, 64)
+		values := make([]uint64, 12)
+		for i := range values {
+			values[i], _ = strconv.ParseUint(fields[i], 10, 64)
+		}
 
-		rx += r
-		tx += t
+		ifaces = append(ifaces, InterfaceStats{
+			Name:      iface,
+			RxBytes:   values[0],
+			RxPackets: values[1],
+			RxErrors:  values[2],
+			RxDropped: values[3],
+			TxBytes:   values[8],
+			TxPackets: values[9],
+			TxErrors:  values[10],
+			TxDropped: values[11],
+		})
 	}
-	return
+	return ifaces
 }
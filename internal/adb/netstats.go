@@ -0,0 +1,237 @@
+package adb
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// UIDTraffic holds cumulative network counters for a single Linux UID.
+// Apps sharing a UID (e.g. android.uid.system) are reported together.
+type UIDTraffic struct {
+	UID      int
+	Packages []string
+
+	RxBytes uint64
+	TxBytes uint64
+}
+
+type UIDTrafficMsg struct {
+	Traffic []UIDTraffic
+	Source  string
+	Error   error
+}
+
+// GetUIDTrafficCmd reads per-UID counters from xt_qtaguid when the kernel
+// still exposes it (Android 9 and older), falling back to dumpsys netstats.
+func GetUIDTrafficCmd(serial string) tea.Cmd {
+	return func() tea.Msg {
+		var totals map[int]*UIDTraffic
+		source := "xt_qtaguid"
+
+		out, err := ExecuteCommand(serial, "shell", "cat", "/proc/net/xt_qtaguid/stats")
+		if err == nil {
+			totals = parseQtaguidStats(string(out))
+		}
+
+		if len(totals) == 0 {
+			source = "netstats"
+			out, err = ExecuteCommand(serial, "shell", "dumpsys", "netstats", "detail")
+			if err != nil {
+				return UIDTrafficMsg{Error: err}
+			}
+			totals = parseNetstatsDetail(string(out))
+		}
+
+		packages := map[int][]string{}
+		out, err = ExecuteCommand(serial, "shell", "pm", "list", "packages", "-U")
+		if err == nil {
+			packages = parsePackageUIDs(out)
+		}
+
+		traffic := make([]UIDTraffic, 0, len(totals))
+		for uid, t := range totals {
+			t.Packages = packages[uid]
+			traffic = append(traffic, *t)
+		}
+
+		sort.Slice(traffic, func(i, j int) bool {
+			return traffic[i].RxBytes+traffic[i].TxBytes > traffic[j].RxBytes+traffic[j].TxBytes
+		})
+
+		return UIDTrafficMsg{Traffic: traffic, Source: source}
+	}
+}
+
+// DisplayName returns the package owning the UID, or a well-known name for
+// system UIDs that have no package.
+func (t UIDTraffic) DisplayName() string {
+	switch len(t.Packages) {
+	case 0:
+	case 1:
+		return t.Packages[0]
+	default:
+		return t.Packages[0] + " +" + strconv.Itoa(len(t.Packages)-1)
+	}
+
+	switch t.UID {
+	case 0:
+		return "root"
+	case 1000:
+		return "system"
+	case 1013:
+		return "media"
+	case 1051:
+		return "dns"
+	default:
+		return "uid " + strconv.Itoa(t.UID)
+	}
+}
+
+func parseQtaguidStats(output string) map[int]*UIDTraffic {
+	// idx iface acct_tag_hex uid_tag_int cnt_set rx_bytes rx_packets tx_bytes tx_packets ...
+	totals := map[int]*UIDTraffic{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 9 || fields[0] == "idx" || fields[1] == "lo" {
+			continue
+		}
+
+		// Tagged rows duplicate the untagged (tag 0x0) totals.
+		if fields[2] != "0x0" {
+			continue
+		}
+
+		uid, err := strconv.Atoi(fields[3])
+		if err != nil {
+			continue
+		}
+
+		rx, _ := strconv.ParseUint(fields[5], 10, 64)
+		tx, _ := strconv.ParseUint(fields[7], 10, 64)
+
+		t := uidEntry(totals, uid)
+		t.RxBytes += rx
+		t.TxBytes += tx
+	}
+
+	return totals
+}
+
+func parseNetstatsDetail(output string) map[int]*UIDTraffic {
+	// UID stats:
+	//   ident=[{type=WIFI, ...}] uid=10123 set=DEFAULT tag=0x0
+	//     NetworkStatsHistory: bucketDuration=7200
+	//       st=1600000000 rb=123 rp=3 tb=456 tp=4 op=0
+	//
+	// Newer releases spell the bucket fields out (rxBytes=, txBytes=).
+	totals := map[int]*UIDTraffic{}
+
+	inUIDSection := false
+	var current *UIDTraffic
+
+	for _, raw := range strings.Split(output, "\n") {
+		line := strings.TrimSpace(raw)
+
+		if strings.HasSuffix(line, "stats:") && !strings.HasPrefix(line, "ident=") {
+			inUIDSection = line == "UID stats:"
+			current = nil
+			continue
+		}
+		if !inUIDSection {
+			continue
+		}
+
+		if strings.Contains(line, "uid=") {
+			current = nil
+			values := keyValueFields(line)
+			if tag, ok := values["tag"]; ok && tag != "0x0" {
+				continue
+			}
+			uid, err := strconv.Atoi(values["uid"])
+			if err != nil {
+				continue
+			}
+			current = uidEntry(totals, uid)
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		values := keyValueFields(line)
+		rx, hasRx := firstValue(values, "rb", "rxBytes")
+		tx, hasTx := firstValue(values, "tb", "txBytes")
+		if hasRx || hasTx {
+			current.RxBytes += rx
+			current.TxBytes += tx
+		}
+	}
+
+	return totals
+}
+
+// parsePackageUIDs parses `pm list packages -U` output:
+// package:com.example.app uid:10123
+func parsePackageUIDs(output []byte) map[int][]string {
+	result := map[int][]string{}
+
+	for _, line := range ParseLines(output) {
+		if !strings.HasPrefix(line, "package:") {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line, "package:"))
+		if len(fields) < 2 {
+			continue
+		}
+
+		uidField := strings.TrimPrefix(fields[len(fields)-1], "uid:")
+		for _, part := range strings.Split(uidField, ",") {
+			uid, err := strconv.Atoi(part)
+			if err != nil {
+				continue
+			}
+			result[uid] = append(result[uid], fields[0])
+		}
+	}
+
+	for uid := range result {
+		sort.Strings(result[uid])
+	}
+
+	return result
+}
+
+func uidEntry(totals map[int]*UIDTraffic, uid int) *UIDTraffic {
+	t, ok := totals[uid]
+	if !ok {
+		t = &UIDTraffic{UID: uid}
+		totals[uid] = t
+	}
+	return t
+}
+
+func keyValueFields(line string) map[string]string {
+	values := map[string]string{}
+	for _, field := range strings.Fields(line) {
+		if k, v, ok := strings.Cut(field, "="); ok {
+			values[k] = strings.TrimRight(v, ",")
+		}
+	}
+	return values
+}
+
+func firstValue(values map[string]string, keys ...string) (uint64, bool) {
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			n, err := strconv.ParseUint(v, 10, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
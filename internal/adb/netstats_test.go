@@ -0,0 +1,135 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Trimmed `dumpsys netstats detail` from an Android 10 device.
+const netstatsDetailLegacy = `Active interfaces:
+  iface=wlan0 ident=[{type=WIFI, subType=COMBINED, networkId="Home Wifi", metered=false, defaultNetwork=true}]
+Dev stats:
+  Pending bytes: 1744
+  History since boot:
+  ident=[{type=WIFI, subType=COMBINED, networkId="Home Wifi", metered=false, defaultNetwork=true}] uid=-1 set=ALL tag=0x0
+    NetworkStatsHistory: bucketDuration=3600
+      st=1600000000 rb=90000 rp=80 tb=40000 tp=60 op=0
+UID stats:
+  Pending bytes: 0
+  Complete history:
+  ident=[{type=WIFI, subType=COMBINED, networkId="Home Wifi", metered=false, defaultNetwork=true}] uid=10123 set=DEFAULT tag=0x0
+    NetworkStatsHistory: bucketDuration=7200
+      st=1600000000 rb=100 rp=3 tb=50 tp=4 op=0
+      st=1600007200 rb=200 rp=3 tb=25 tp=4 op=0
+  ident=[{type=WIFI, subType=COMBINED, networkId="Home Wifi", metered=false, defaultNetwork=true}] uid=10123 set=FOREGROUND tag=0x0
+    NetworkStatsHistory: bucketDuration=7200
+      st=1600000000 rb=10 rp=1 tb=5 tp=1 op=0
+  ident=[{type=WIFI, subType=COMBINED, networkId="Home Wifi", metered=false, defaultNetwork=true}] uid=10123 set=DEFAULT tag=0xffffff01
+    NetworkStatsHistory: bucketDuration=7200
+      st=1600000000 rb=999 rp=9 tb=999 tp=9 op=0
+  ident=[{type=MOBILE, subType=COMBINED, subscriberId=310260...}] uid=1000 set=DEFAULT tag=0x0
+    NetworkStatsHistory: bucketDuration=7200
+      st=1600000000 rb=7 rp=1 tb=3 tp=1 op=0
+UID tag stats:
+  Pending bytes: 0
+  ident=[{type=WIFI, subType=COMBINED, networkId="Home Wifi", metered=false, defaultNetwork=true}] uid=10123 set=DEFAULT tag=0x0
+    NetworkStatsHistory: bucketDuration=7200
+      st=1600000000 rb=5000 rp=5 tb=5000 tp=5 op=0
+`
+
+// Trimmed `dumpsys netstats detail` from an Android 13 device, which
+// spells the bucket fields out.
+const netstatsDetailModern = `UID stats:
+  Pending bytes: 0
+  Complete history:
+  ident=[{type=1, ratType=COMBINED, wifiNetworkKey="Home Wifi", metered=false, defaultNetwork=true, oemManaged=OEM_NONE}] uid=10150 set=DEFAULT tag=0x0
+    NetworkStatsHistory: bucketDuration=7200
+      bucketStart=1650000000000 activeTime=7200000 rxBytes=1024 rxPackets=10 txBytes=512 txPackets=5 operations=0
+      bucketStart=1650007200000 activeTime=7200000 rxBytes=1024 rxPackets=10 txBytes=512 txPackets=5 operations=0
+UID tag stats:
+  ident=[{type=1, ratType=COMBINED}] uid=10150 set=DEFAULT tag=0x0
+      bucketStart=1650000000000 activeTime=7200000 rxBytes=99999 rxPackets=10 txBytes=99999 txPackets=5 operations=0
+`
+
+func TestParseNetstatsDetail(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   map[int]UIDTraffic
+	}{
+		{
+			name:   "legacy bucket fields",
+			output: netstatsDetailLegacy,
+			want: map[int]UIDTraffic{
+				10123: {UID: 10123, RxBytes: 310, TxBytes: 80},
+				1000:  {UID: 1000, RxBytes: 7, TxBytes: 3},
+			},
+		},
+		{
+			name:   "spelled out bucket fields",
+			output: netstatsDetailModern,
+			want: map[int]UIDTraffic{
+				10150: {UID: 10150, RxBytes: 2048, TxBytes: 1024},
+			},
+		},
+		{
+			name:   "no UID section",
+			output: "Dev stats:\n  Pending bytes: 0\n",
+			want:   map[int]UIDTraffic{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[int]UIDTraffic{}
+			for uid, traffic := range parseNetstatsDetail(tt.output) {
+				got[uid] = *traffic
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNetstatsDetail() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQtaguidStats(t *testing.T) {
+	output := `idx iface acct_tag_hex uid_tag_int cnt_set rx_bytes rx_packets tx_bytes tx_packets rx_tcp_bytes
+2 wlan0 0x0 10123 0 1000 10 500 5 0
+3 wlan0 0x0 10123 1 200 2 100 1 0
+4 wlan0 0x3e800000000 10123 0 777 7 777 7 0
+5 lo 0x0 0 0 9999 9 9999 9 0
+6 rmnet0 0x0 0 0 40 1 20 1 0
+`
+	want := map[int]UIDTraffic{
+		10123: {UID: 10123, RxBytes: 1200, TxBytes: 600},
+		0:     {UID: 0, RxBytes: 40, TxBytes: 20},
+	}
+
+	got := map[int]UIDTraffic{}
+	for uid, traffic := range parseQtaguidStats(output) {
+		got[uid] = *traffic
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseQtaguidStats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePackageUIDs(t *testing.T) {
+	output := []byte(`package:com.android.shell uid:2000
+package:com.example.app uid:10123
+package:android uid:1000
+package:com.android.settings uid:1000
+package:com.example.shared uid:10200,1010200
+`)
+	want := map[int][]string{
+		2000:    {"com.android.shell"},
+		10123:   {"com.example.app"},
+		1000:    {"android", "com.android.settings"},
+		10200:   {"com.example.shared"},
+		1010200: {"com.example.shared"},
+	}
+
+	if got := parsePackageUIDs(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePackageUIDs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,484 @@
+package adb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PackageDetails is the subset of `dumpsys package <pkg>` shown in the app
+// details screen.
+type PackageDetails struct {
+	PackageName      string
+	VersionName      string
+	VersionCode      string
+	MinSDK           string
+	TargetSDK        string
+	UID              string
+	CodePath         string
+	DataDir          string
+	PrimaryABI       string
+	Installer        string
+	FirstInstallTime string
+	LastUpdateTime   string
+	Flags            []string
+	PrivateFlags     []string
+	// InstalledUsers lists the users the package is installed for.
+	InstalledUsers []int
+
+	DeclaredPermissions  []string
+	RequestedPermissions []string
+	InstallPermissions   []PermissionState
+	// RuntimePermissions are granted per user, keyed by user id.
+	RuntimePermissions map[int][]PermissionState
+}
+
+// PermissionState is a permission line such as
+// "android.permission.CAMERA: granted=false, flags=[ USER_SET ]".
+type PermissionState struct {
+	Name    string
+	Granted bool
+	Flags   string
+}
+
+type PackageDetailsMsg struct {
+	Package string
+	Details PackageDetails
+	Error   error
+}
+
+type PackageManifestMsg struct {
+	Package  string
+	Manifest apk.Manifest
+	Error    error
+}
+
+type PackageCertificatesMsg struct {
+	Package      string
+	Certificates []apk.Certificate
+	Error        error
+}
+
+func (d PackageDetails) HasFlag(flag string) bool {
+	for _, f := range d.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func (d PackageDetails) Debuggable() bool {
+	return d.HasFlag("DEBUGGABLE")
+}
+
+func (d PackageDetails) AllowBackup() bool {
+	return d.HasFlag("ALLOW_BACKUP")
+}
+
+// PermissionGranted reports whether a requested permission is granted,
+// either at install time or as a runtime permission for user. The second
+// result is false when the permission has no recorded state.
+func (d PackageDetails) PermissionGranted(name string, user int) (granted, known bool) {
+	for _, p := range d.RuntimePermissions[user] {
+		if p.Name == name {
+			return p.Granted, true
+		}
+	}
+	for _, p := range d.InstallPermissions {
+		if p.Name == name {
+			return p.Granted, true
+		}
+	}
+	return false, false
+}
+
+func GetPackageDetails(serial, pkg string) (PackageDetails, error) {
+	out, err := ExecuteCommand(serial, "shell", "dumpsys", "package", pkg)
+	if err != nil {
+		return PackageDetails{}, err
+	}
+	return ParsePackageDetails(string(out), pkg)
+}
+
+func GetPackageDetailsCmd(serial, pkg string) tea.Cmd {
+	return func() tea.Msg {
+		details, err := GetPackageDetails(serial, pkg)
+		return PackageDetailsMsg{Package: pkg, Details: details, Error: err}
+	}
+}
+
+// ParsePackageDetails reads the "Package [pkg]" block of the "Packages:"
+// section. The rest of the dump (resolver tables, hidden system packages)
+// is ignored.
+func ParsePackageDetails(output, pkg string) (PackageDetails, error) {
+	block := packageBlock(output, pkg)
+	if block == nil {
+		return PackageDetails{}, fmt.Errorf("package %s not found", pkg)
+	}
+	return parsePackageBlock(block, pkg), nil
+}
+
+// ParseAllPackageDetails reads every entry of the "Packages:" section of
+// `dumpsys package packages`.
+func ParseAllPackageDetails(output string) map[string]PackageDetails {
+	details := map[string]PackageDetails{}
+	for pkg, block := range packageBlocks(output) {
+		details[pkg] = parsePackageBlock(block, pkg)
+	}
+	return details
+}
+
+func parsePackageBlock(block []string, pkg string) PackageDetails {
+	d := PackageDetails{
+		PackageName:        pkg,
+		RuntimePermissions: map[int][]PermissionState{},
+	}
+
+	section := ""
+	sectionIndent := 0
+	user := 0
+
+	for _, raw := range block {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+
+		if section != "" && indent <= sectionIndent {
+			section = ""
+		}
+
+		if section != "" {
+			name, rest, _ := strings.Cut(line, ":")
+			name = strings.TrimSpace(name)
+			switch section {
+			case "declared permissions":
+				d.DeclaredPermissions = append(d.DeclaredPermissions, name)
+			case "requested permissions":
+				d.RequestedPermissions = append(d.RequestedPermissions, name)
+			case "install permissions":
+				d.InstallPermissions = append(d.InstallPermissions, parsePermissionState(name, rest))
+			case "runtime permissions":
+				d.RuntimePermissions[user] = append(d.RuntimePermissions[user], parsePermissionState(name, rest))
+			}
+			continue
+		}
+
+		switch line {
+		case "declared permissions:", "requested permissions:", "install permissions:", "runtime permissions:":
+			section = strings.TrimSuffix(line, ":")
+			sectionIndent = indent
+			continue
+		}
+
+		if id, ok := userHeader(line); ok {
+			user = id
+			if strings.Contains(line, " installed=true") {
+				d.InstalledUsers = append(d.InstalledUsers, id)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "flags":
+			d.Flags = bracketList(value)
+			continue
+		case "privateFlags":
+			d.PrivateFlags = bracketList(value)
+			continue
+		case "pkgFlags":
+			// Older releases repeat flags here.
+			continue
+		case "firstInstallTime":
+			d.FirstInstallTime = value
+			continue
+		case "lastUpdateTime":
+			d.LastUpdateTime = value
+			continue
+		}
+
+		for _, field := range strings.Fields(line) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "versionCode":
+				d.VersionCode = setOnce(d.VersionCode, value)
+			case "versionName":
+				d.VersionName = setOnce(d.VersionName, value)
+			case "minSdk":
+				d.MinSDK = setOnce(d.MinSDK, value)
+			case "targetSdk":
+				d.TargetSDK = setOnce(d.TargetSDK, value)
+			case "userId", "appId":
+				d.UID = setOnce(d.UID, value)
+			case "codePath":
+				d.CodePath = setOnce(d.CodePath, value)
+			case "dataDir":
+				d.DataDir = setOnce(d.DataDir, value)
+			case "primaryCpuAbi":
+				if value != "null" {
+					d.PrimaryABI = setOnce(d.PrimaryABI, value)
+				}
+			case "installerPackageName":
+				if value != "null" {
+					d.Installer = setOnce(d.Installer, value)
+				}
+			}
+		}
+	}
+
+	return d
+}
+
+// packageBlocks splits the "Packages:" section into each package's lines,
+// header excluded.
+func packageBlocks(output string) map[string][]string {
+	blocks := map[string][]string{}
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+
+	inPackages := false
+	current := ""
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+
+		if line == "Packages:" {
+			inPackages = true
+			continue
+		}
+		if !inPackages {
+			continue
+		}
+		if indent == 0 {
+			// The next section, e.g. "Hidden system packages:".
+			break
+		}
+
+		if rest, ok := strings.CutPrefix(line, "Package ["); ok && indent <= 2 {
+			current, _, _ = strings.Cut(rest, "]")
+			blocks[current] = []string{}
+			continue
+		}
+		if current != "" {
+			blocks[current] = append(blocks[current], raw)
+		}
+	}
+
+	return blocks
+}
+
+// packageBlock returns the lines of the package's entry in the "Packages:"
+// section, header excluded.
+func packageBlock(output, pkg string) []string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	header := "Package [" + pkg + "]"
+
+	inPackages := false
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "Packages:" {
+			inPackages = true
+			continue
+		}
+		if !inPackages || !strings.HasPrefix(line, header) {
+			continue
+		}
+
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		block := []string{}
+		for _, next := range lines[i+1:] {
+			trimmed := strings.TrimSpace(next)
+			if trimmed != "" && len(next)-len(strings.TrimLeft(next, " ")) <= indent {
+				break
+			}
+			block = append(block, next)
+		}
+		return block
+	}
+
+	return nil
+}
+
+// userHeader matches per-user lines like "User 0: ceDataInode=... installed=true".
+func userHeader(line string) (int, bool) {
+	if !strings.HasPrefix(line, "User ") {
+		return 0, false
+	}
+	idField, _, ok := strings.Cut(strings.TrimPrefix(line, "User "), ":")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(idField)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+func parsePermissionState(name, rest string) PermissionState {
+	p := PermissionState{Name: name}
+	for _, part := range strings.Split(rest, ", ") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "granted":
+			p.Granted = value == "true"
+		case "flags":
+			p.Flags = strings.Join(bracketList(value), " ")
+		}
+	}
+	return p
+}
+
+// bracketList splits "[ HAS_CODE ALLOW_BACKUP ]" into its entries.
+func bracketList(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "[")
+	value = strings.TrimSuffix(value, "]")
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '|'
+	})
+}
+
+func setOnce(current, value string) string {
+	if current != "" {
+		return current
+	}
+	return value
+}
+
+// GetPackageVersion returns the installed versionName and versionCode of
+// pkg from `dumpsys package`.
+func GetPackageVersion(serial, pkg string) (name, code string, err error) {
+	details, err := GetPackageDetails(serial, pkg)
+	if err != nil {
+		return "", "", err
+	}
+	return details.VersionName, details.VersionCode, nil
+}
+
+// GetPackageCertificatesCmd pulls the package's base APK to a temporary
+// file and reads its signing certificates.
+func GetPackageCertificatesCmd(serial, pkg string) tea.Cmd {
+	return func() tea.Msg {
+		certs, err := packageCertificates(serial, pkg)
+		return PackageCertificatesMsg{Package: pkg, Certificates: certs, Error: err}
+	}
+}
+
+func packageCertificates(serial, pkg string) ([]apk.Certificate, error) {
+	local, cleanup, err := pullBaseAPK(serial, pkg)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	return apk.SigningCertificates(local)
+}
+
+// GetPackageManifestCmd decodes the package's manifest with PackageManifest.
+func GetPackageManifestCmd(serial, pkg string) tea.Cmd {
+	return func() tea.Msg {
+		manifest, err := PackageManifest(serial, pkg)
+		return PackageManifestMsg{Package: pkg, Manifest: manifest, Error: err}
+	}
+}
+
+// PackageManifest decodes the AndroidManifest.xml of the package's base
+// APK and adds the components of its feature splits. `dumpsys package`
+// only lists components with intent filters and not whether they are
+// exported, so the manifests are read instead; only they cross the link,
+// not the whole APKs.
+func PackageManifest(serial, pkg string) (apk.Manifest, error) {
+	paths, err := GetPackagePaths(serial, pkg)
+	if err != nil {
+		return apk.Manifest{}, err
+	}
+
+	var manifest apk.Manifest
+	for i, path := range paths {
+		f, err := openDeviceFile(serial, path)
+		if err != nil {
+			return apk.Manifest{}, err
+		}
+		m, err := apk.ReadManifestAt(f, f.size)
+		if err != nil {
+			return apk.Manifest{}, fmt.Errorf("%s: %w", path, err)
+		}
+		if i == 0 {
+			manifest = m
+		} else {
+			manifest.MergeSplit(m)
+		}
+	}
+	return manifest, nil
+}
+
+// pullBaseAPK copies the package's base APK into a temporary directory
+// that cleanup removes.
+func pullBaseAPK(serial, pkg string) (string, func(), error) {
+	paths, err := GetPackagePaths(serial, pkg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "adbt-apk-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	local := filepath.Join(tmpDir, "base.apk")
+	if _, err := ExecuteCommand(serial, "pull", paths[0], local); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to pull apk: %w", err)
+	}
+	return local, cleanup, nil
+}
+
+// GetPackagePaths returns the APK paths of pkg from `pm path`, base APK
+// first.
+func GetPackagePaths(serial, pkg string) ([]string, error) {
+	out, err := ExecuteCommand(serial, "shell", "pm", "path", pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, line := range ParseLines(out) {
+		path, ok := strings.CutPrefix(line, "package:")
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(path, "/base.apk") {
+			paths = append([]string{path}, paths...)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no apk found for %s", pkg)
+	}
+	return paths, nil
+}
@@ -0,0 +1,180 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Trimmed `dumpsys package com.example.app` from an Android 13 device.
+const dumpsysPackage = `Activity Resolver Table:
+  Non-Data Actions:
+      android.intent.action.MAIN:
+        5d1c0e2 com.example.app/.MainActivity filter 9a3b4c5
+          Action: "android.intent.action.MAIN"
+          Category: "android.intent.category.LAUNCHER"
+
+Key Set Manager:
+  [com.example.app]
+      Signing KeySets: 63
+
+Packages:
+  Package [com.example.app] (8a7f0c1):
+    userId=10123
+    pkg=Package{5f3a2b1 com.example.app}
+    codePath=/data/app/~~Yx2p==/com.example.app-Qm1A==
+    resourcePath=/data/app/~~Yx2p==/com.example.app-Qm1A==
+    legacyNativeLibraryDir=/data/app/~~Yx2p==/com.example.app-Qm1A==/lib
+    primaryCpuAbi=arm64-v8a
+    secondaryCpuAbi=null
+    versionCode=42 minSdk=24 targetSdk=33
+    versionName=1.4.2
+    splits=[base]
+    apkSigningVersion=2
+    applicationInfo=ApplicationInfo{5f3a2b1 com.example.app}
+    flags=[ DEBUGGABLE HAS_CODE ALLOW_CLEAR_USER_DATA ALLOW_BACKUP ]
+    privateFlags=[ PRIVATE_FLAG_ACTIVITIES_RESIZE_MODE_RESIZEABLE ALLOW_AUDIO_PLAYBACK_CAPTURE ]
+    forceQueryable=false
+    queriesPackages=[]
+    dataDir=/data/user/0/com.example.app
+    supportsScreens=[small, medium, large, xlarge, resizeable, anyDensity]
+    timeStamp=2024-05-01 12:00:00
+    firstInstallTime=2024-04-01 09:30:00
+    lastUpdateTime=2024-05-01 12:00:01
+    installerPackageName=com.android.vending
+    signatures=PackageSignatures{9b8c7d6 version:2, signatures:[a1b2c3d4], past signatures:[]}
+    installPermissionsFixed=true
+    pkgFlags=[ DEBUGGABLE HAS_CODE ALLOW_CLEAR_USER_DATA ALLOW_BACKUP ]
+    declared permissions:
+      com.example.app.permission.C2D_MESSAGE: prot=signature, INSTALLED
+    requested permissions:
+      android.permission.INTERNET
+      android.permission.CAMERA
+      android.permission.POST_NOTIFICATIONS
+    install permissions:
+      android.permission.INTERNET: granted=true
+    User 0: ceDataInode=123456 installed=true hidden=false suspended=false distractionFlags=0 stopped=false notLaunched=false enabled=0 instant=false virtual=false
+      gids=[3003]
+      runtime permissions:
+        android.permission.POST_NOTIFICATIONS: granted=false, flags=[ USER_SENSITIVE_WHEN_GRANTED|USER_SENSITIVE_WHEN_DENIED]
+        android.permission.CAMERA: granted=true, flags=[ USER_SET|USER_SENSITIVE_WHEN_GRANTED]
+    User 10: ceDataInode=0 installed=false hidden=false suspended=false distractionFlags=0 stopped=true notLaunched=true enabled=0 instant=false virtual=false
+      runtime permissions:
+        android.permission.CAMERA: granted=false, flags=[ USER_SENSITIVE_WHEN_GRANTED ]
+  Package [com.example.other] (3c2d1e0):
+    userId=10124
+    versionCode=7 minSdk=21 targetSdk=30
+    versionName=0.7
+    flags=[ HAS_CODE ]
+    installerPackageName=null
+    User 0: ceDataInode=654321 installed=true hidden=false
+
+Hidden system packages:
+  Package [com.example.app] (1f2e3d4):
+    userId=10123
+    versionCode=1 minSdk=24 targetSdk=33
+`
+
+func TestParsePackageDetails(t *testing.T) {
+	want := PackageDetails{
+		PackageName:      "com.example.app",
+		VersionName:      "1.4.2",
+		VersionCode:      "42",
+		MinSDK:           "24",
+		TargetSDK:        "33",
+		UID:              "10123",
+		CodePath:         "/data/app/~~Yx2p==/com.example.app-Qm1A==",
+		DataDir:          "/data/user/0/com.example.app",
+		PrimaryABI:       "arm64-v8a",
+		Installer:        "com.android.vending",
+		FirstInstallTime: "2024-04-01 09:30:00",
+		LastUpdateTime:   "2024-05-01 12:00:01",
+		Flags:            []string{"DEBUGGABLE", "HAS_CODE", "ALLOW_CLEAR_USER_DATA", "ALLOW_BACKUP"},
+		PrivateFlags:     []string{"PRIVATE_FLAG_ACTIVITIES_RESIZE_MODE_RESIZEABLE", "ALLOW_AUDIO_PLAYBACK_CAPTURE"},
+		InstalledUsers:   []int{0},
+		DeclaredPermissions: []string{
+			"com.example.app.permission.C2D_MESSAGE",
+		},
+		RequestedPermissions: []string{
+			"android.permission.INTERNET",
+			"android.permission.CAMERA",
+			"android.permission.POST_NOTIFICATIONS",
+		},
+		InstallPermissions: []PermissionState{
+			{Name: "android.permission.INTERNET", Granted: true},
+		},
+		RuntimePermissions: map[int][]PermissionState{
+			0: {
+				{Name: "android.permission.POST_NOTIFICATIONS", Flags: "USER_SENSITIVE_WHEN_GRANTED USER_SENSITIVE_WHEN_DENIED"},
+				{Name: "android.permission.CAMERA", Granted: true, Flags: "USER_SET USER_SENSITIVE_WHEN_GRANTED"},
+			},
+			10: {
+				{Name: "android.permission.CAMERA", Flags: "USER_SENSITIVE_WHEN_GRANTED"},
+			},
+		},
+	}
+
+	got, err := ParsePackageDetails(dumpsysPackage, "com.example.app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePackageDetails() =\n%+v\nwant\n%+v", got, want)
+	}
+	if !got.Debuggable() || !got.AllowBackup() {
+		t.Errorf("Debuggable() = %v, AllowBackup() = %v, want both true", got.Debuggable(), got.AllowBackup())
+	}
+
+	if _, err := ParsePackageDetails(dumpsysPackage, "com.example.missing"); err == nil {
+		t.Error("ParsePackageDetails() of a missing package returned no error")
+	}
+}
+
+func TestPermissionGranted(t *testing.T) {
+	details, err := ParsePackageDetails(dumpsysPackage, "com.example.app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		permission string
+		user       int
+		granted    bool
+		known      bool
+	}{
+		{"runtime granted", "android.permission.CAMERA", 0, true, true},
+		{"runtime denied for another user", "android.permission.CAMERA", 10, false, true},
+		{"runtime denied", "android.permission.POST_NOTIFICATIONS", 0, false, true},
+		{"install permission", "android.permission.INTERNET", 10, true, true},
+		{"no state", "android.permission.RECORD_AUDIO", 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			granted, known := details.PermissionGranted(tt.permission, tt.user)
+			if granted != tt.granted || known != tt.known {
+				t.Errorf("PermissionGranted(%q, %d) = %v, %v, want %v, %v", tt.permission, tt.user, granted, known, tt.granted, tt.known)
+			}
+		})
+	}
+}
+
+func TestParseAllPackageDetails(t *testing.T) {
+	all := ParseAllPackageDetails(dumpsysPackage)
+	if len(all) != 2 {
+		t.Fatalf("ParseAllPackageDetails() found %d packages, want 2", len(all))
+	}
+
+	// The hidden system copy must not replace the installed one.
+	if got := all["com.example.app"].VersionCode; got != "42" {
+		t.Errorf("com.example.app versionCode = %q, want 42", got)
+	}
+
+	other := all["com.example.other"]
+	if other.VersionName != "0.7" || other.UID != "10124" || other.Installer != "" {
+		t.Errorf("com.example.other = %+v", other)
+	}
+	if !reflect.DeepEqual(other.InstalledUsers, []int{0}) {
+		t.Errorf("com.example.other installed users = %v, want [0]", other.InstalledUsers)
+	}
+}
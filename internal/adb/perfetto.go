@@ -0,0 +1,142 @@
+package adb
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// perfettoTraceDir is the only location perfetto may write to on
+// user builds.
+const perfettoTraceDir = "/data/misc/perfetto-traces"
+
+var durationPattern = regexp.MustCompile(`(?m)^\s*duration_ms:\s*(\d+)`)
+
+type TraceStartedMsg struct {
+	PID        int
+	RemotePath string
+	Duration   time.Duration
+	Error      error
+}
+
+type TraceStatusMsg struct {
+	Running bool
+	Error   error
+}
+
+type TracePulledMsg struct {
+	LocalPath string
+	Error     error
+}
+
+// BuildTraceConfig fills a preset or user config with the duration and
+// target package. A user config that sets its own duration_ms keeps it,
+// and the returned duration reflects what perfetto will actually use.
+func BuildTraceConfig(config, pkg string, duration time.Duration) (string, time.Duration) {
+	if pkg == "" {
+		// Drop per-app options such as atrace_apps rather than pass "".
+		var kept []string
+		for _, line := range strings.Split(config, "\n") {
+			if !strings.Contains(line, "{{PACKAGE}}") {
+				kept = append(kept, line)
+			}
+		}
+		config = strings.Join(kept, "\n")
+	}
+	config = strings.ReplaceAll(config, "{{PACKAGE}}", pkg)
+	config = strings.ReplaceAll(config, "{{DURATION_MS}}", strconv.FormatInt(duration.Milliseconds(), 10))
+
+	if m := durationPattern.FindStringSubmatch(config); m != nil {
+		ms, _ := strconv.ParseInt(m[1], 10, 64)
+		return config, time.Duration(ms) * time.Millisecond
+	}
+
+	config = strings.TrimRight(config, "\n") + fmt.Sprintf("\nduration_ms: %d\n", duration.Milliseconds())
+	return config, duration
+}
+
+// TraceFileName names a trace after the device and, when set, the package.
+func TraceFileName(device, pkg string, now time.Time) string {
+	parts := []string{config.SafeFileName(device)}
+	if pkg != "" {
+		parts = append(parts, config.SafeFileName(pkg))
+	}
+	parts = append(parts, now.Format("20060102-150405"))
+	return strings.Join(parts, "_") + ".perfetto-trace"
+}
+
+// StartTraceCmd starts perfetto in the background on the device. The config
+// is streamed over stdin since older releases cannot read config files
+// from /data/local/tmp.
+func StartTraceCmd(serial, config, fileName string, duration time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		remote := path.Join(perfettoTraceDir, fileName)
+
+		out, err := ExecuteCommandWithInput(
+			serial,
+			[]byte(config),
+			"shell", "perfetto", "--background", "--txt", "-c", "-", "-o", remote,
+		)
+		if err != nil {
+			return TraceStartedMsg{Error: fmt.Errorf("failed to start perfetto: %w", err)}
+		}
+
+		// With --background perfetto prints the PID of the detached process.
+		lines := ParseLines(out)
+		pid := 0
+		if len(lines) > 0 {
+			pid, _ = strconv.Atoi(lines[len(lines)-1])
+		}
+		if pid == 0 {
+			return TraceStartedMsg{Error: fmt.Errorf("unexpected perfetto output: %s", strings.TrimSpace(string(out)))}
+		}
+
+		return TraceStartedMsg{PID: pid, RemotePath: remote, Duration: duration}
+	}
+}
+
+// CheckTraceCmd reports whether the perfetto process is still writing.
+func CheckTraceCmd(serial string, pid int) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, "shell", fmt.Sprintf("if [ -e /proc/%d ]; then echo running; fi", pid))
+		if err != nil {
+			return TraceStatusMsg{Error: err}
+		}
+		return TraceStatusMsg{Running: strings.TrimSpace(string(out)) == "running"}
+	}
+}
+
+// StopTraceCmd ends a trace early; perfetto flushes what it has on SIGTERM.
+func StopTraceCmd(serial string, pid int) tea.Cmd {
+	return func() tea.Msg {
+		_, err := ExecuteCommand(serial, "shell", "kill", "-TERM", strconv.Itoa(pid))
+		return TraceStatusMsg{Running: err == nil, Error: err}
+	}
+}
+
+// PullTraceCmd copies the finished trace into localDir and removes it from
+// the device.
+func PullTraceCmd(serial, remotePath, localDir string) tea.Cmd {
+	return func() tea.Msg {
+		if err := os.MkdirAll(localDir, 0o755); err != nil {
+			return TracePulledMsg{Error: err}
+		}
+
+		local := filepath.Join(localDir, path.Base(remotePath))
+		_, err := ExecuteCommand(serial, "pull", remotePath, local)
+		_, _ = ExecuteCommand(serial, "shell", "rm", "-f", remotePath)
+		if err != nil {
+			return TracePulledMsg{Error: err}
+		}
+		return TracePulledMsg{LocalPath: local}
+	}
+}
@@ -0,0 +1,148 @@
+package adb
+
+// PerfettoPreset is a bundled trace config in perfetto's text format.
+// {{DURATION_MS}} and {{PACKAGE}} are filled in when the trace starts.
+type PerfettoPreset struct {
+	Name   string
+	Config string
+}
+
+var PerfettoPresets = []PerfettoPreset{
+	{Name: "CPU scheduling", Config: perfettoCPUConfig},
+	{Name: "App startup", Config: perfettoStartupConfig},
+	{Name: "Memory", Config: perfettoMemoryConfig},
+}
+
+const perfettoCPUConfig = `buffers: {
+  size_kb: 63488
+  fill_policy: DISCARD
+}
+buffers: {
+  size_kb: 2048
+  fill_policy: DISCARD
+}
+data_sources: {
+  config {
+    name: "linux.ftrace"
+    ftrace_config {
+      ftrace_events: "sched/sched_switch"
+      ftrace_events: "sched/sched_wakeup"
+      ftrace_events: "sched/sched_wakeup_new"
+      ftrace_events: "sched/sched_waking"
+      ftrace_events: "sched/sched_process_exit"
+      ftrace_events: "sched/sched_process_free"
+      ftrace_events: "task/task_newtask"
+      ftrace_events: "task/task_rename"
+      ftrace_events: "power/cpu_frequency"
+      ftrace_events: "power/cpu_idle"
+      ftrace_events: "power/suspend_resume"
+    }
+  }
+}
+data_sources: {
+  config {
+    name: "linux.process_stats"
+    target_buffer: 1
+    process_stats_config {
+      scan_all_processes_on_start: true
+    }
+  }
+}
+duration_ms: {{DURATION_MS}}
+`
+
+const perfettoStartupConfig = `buffers: {
+  size_kb: 63488
+  fill_policy: DISCARD
+}
+buffers: {
+  size_kb: 2048
+  fill_policy: DISCARD
+}
+data_sources: {
+  config {
+    name: "linux.ftrace"
+    ftrace_config {
+      ftrace_events: "sched/sched_switch"
+      ftrace_events: "sched/sched_wakeup"
+      ftrace_events: "sched/sched_waking"
+      ftrace_events: "sched/sched_blocked_reason"
+      ftrace_events: "task/task_newtask"
+      ftrace_events: "task/task_rename"
+      ftrace_events: "power/cpu_frequency"
+      ftrace_events: "ftrace/print"
+      atrace_categories: "am"
+      atrace_categories: "wm"
+      atrace_categories: "view"
+      atrace_categories: "gfx"
+      atrace_categories: "dalvik"
+      atrace_categories: "input"
+      atrace_categories: "binder_driver"
+      atrace_categories: "res"
+      atrace_categories: "pm"
+      atrace_apps: "{{PACKAGE}}"
+    }
+  }
+}
+data_sources: {
+  config {
+    name: "linux.process_stats"
+    target_buffer: 1
+    process_stats_config {
+      scan_all_processes_on_start: true
+    }
+  }
+}
+data_sources: {
+  config {
+    name: "android.packages_list"
+    target_buffer: 1
+  }
+}
+duration_ms: {{DURATION_MS}}
+`
+
+const perfettoMemoryConfig = `buffers: {
+  size_kb: 63488
+  fill_policy: DISCARD
+}
+buffers: {
+  size_kb: 2048
+  fill_policy: DISCARD
+}
+data_sources: {
+  config {
+    name: "linux.ftrace"
+    ftrace_config {
+      ftrace_events: "kmem/rss_stat"
+      ftrace_events: "mm_event/mm_event_record"
+      ftrace_events: "oom/oom_score_adj_update"
+      ftrace_events: "lowmemorykiller/lowmemory_kill"
+      ftrace_events: "sched/sched_process_exit"
+      ftrace_events: "task/task_newtask"
+      ftrace_events: "task/task_rename"
+    }
+  }
+}
+data_sources: {
+  config {
+    name: "linux.process_stats"
+    target_buffer: 1
+    process_stats_config {
+      scan_all_processes_on_start: true
+      proc_stats_poll_ms: 1000
+    }
+  }
+}
+data_sources: {
+  config {
+    name: "linux.sys_stats"
+    target_buffer: 1
+    sys_stats_config {
+      meminfo_period_ms: 1000
+      vmstat_period_ms: 1000
+    }
+  }
+}
+duration_ms: {{DURATION_MS}}
+`
@@ -0,0 +1,118 @@
+package adb
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// AppOpModes are the modes accepted by `appops set`.
+var AppOpModes = []string{"allow", "ignore", "deny", "default", "foreground"}
+
+// AppOp is one line of `appops get <pkg>`.
+type AppOp struct {
+	Name string
+	Mode string
+	// UIDMode is set for ops applied to the whole uid ("Uid mode: ...").
+	UIDMode bool
+	// Detail holds the rest of the line, e.g. "time=+1h2m ago".
+	Detail string
+}
+
+type AppOpsMsg struct {
+	Package string
+	Ops     []AppOp
+	Error   error
+}
+
+func SetPermissionCmd(serial, pkg string, user int, permission string, grant bool) tea.Cmd {
+	action := "revoke"
+	if grant {
+		action = "grant"
+	}
+
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, "shell", "pm", action, "--user", userArg(user), pkg, permission)
+		if err == nil {
+			err = commandError(out)
+		}
+		if err != nil {
+			return AppActionErrorMsg{Action: action, Error: err}
+		}
+		return AppActionResultMsg{Action: action}
+	}
+}
+
+// ResetPermissionsCmd resets runtime permissions for every user, as
+// `pm reset-permissions` has no --user option.
+func ResetPermissionsCmd(serial, pkg string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, "shell", "pm", "reset-permissions", "-p", pkg)
+		if err == nil {
+			err = commandError(out)
+		}
+		if err != nil {
+			return AppActionErrorMsg{Action: "reset permissions", Error: err}
+		}
+		return AppActionResultMsg{Action: "reset permissions"}
+	}
+}
+
+func GetAppOpsCmd(serial, pkg string, user int) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, "shell", "appops", "get", "--user", userArg(user), pkg)
+		if err == nil {
+			err = commandError(out)
+		}
+		if err != nil {
+			return AppOpsMsg{Package: pkg, Error: err}
+		}
+		return AppOpsMsg{Package: pkg, Ops: ParseAppOps(out)}
+	}
+}
+
+func SetAppOpCmd(serial, pkg string, user int, op, mode string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := ExecuteCommand(serial, "shell", "appops", "set", "--user", userArg(user), pkg, op, mode)
+		if err == nil {
+			err = commandError(out)
+		}
+		if err != nil {
+			return AppActionErrorMsg{Action: "set " + op, Error: err}
+		}
+		return AppActionResultMsg{Action: "set " + op}
+	}
+}
+
+// ParseAppOps parses lines such as
+//
+//	Uid mode: COARSE_LOCATION: foreground
+//	CAMERA: allow; time=+1h2m ago
+func ParseAppOps(output []byte) []AppOp {
+	var ops []AppOp
+
+	for _, line := range ParseLines(output) {
+		op := AppOp{}
+		if rest, ok := strings.CutPrefix(line, "Uid mode:"); ok {
+			op.UIDMode = true
+			line = strings.TrimSpace(rest)
+		}
+
+		name, rest, ok := strings.Cut(line, ":")
+		if !ok || strings.ContainsAny(name, " =") {
+			continue
+		}
+
+		mode, detail, _ := strings.Cut(strings.TrimSpace(rest), ";")
+		op.Name = name
+		op.Mode = strings.TrimSpace(mode)
+		op.Detail = strings.TrimSpace(detail)
+		if op.Mode == "" {
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops
+}
@@ -0,0 +1,39 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+)
+
+const appOpsOutput = `Uid mode: COARSE_LOCATION: foreground
+CAMERA: allow; time=+2h13m4s512ms ago; duration=+1m2s
+RECORD_AUDIO: ignore; rejectTime=+5d ago
+WAKE_LOCK: allow
+READ_CLIPBOARD: default
+`
+
+const appOpsUnknownPackage = "Error: Unknown package: com.example.missing\n"
+
+func TestParseAppOps(t *testing.T) {
+	want := []AppOp{
+		{Name: "COARSE_LOCATION", Mode: "foreground", UIDMode: true},
+		{Name: "CAMERA", Mode: "allow", Detail: "time=+2h13m4s512ms ago; duration=+1m2s"},
+		{Name: "RECORD_AUDIO", Mode: "ignore", Detail: "rejectTime=+5d ago"},
+		{Name: "WAKE_LOCK", Mode: "allow"},
+		{Name: "READ_CLIPBOARD", Mode: "default"},
+	}
+	if got := ParseAppOps([]byte(appOpsOutput)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAppOps() = %+v, want %+v", got, want)
+	}
+	if err := commandError([]byte(appOpsOutput)); err != nil {
+		t.Errorf("commandError() of appops get output = %v", err)
+	}
+}
+
+func TestAppOpsFailure(t *testing.T) {
+	// appops exits 0 here; without the check the line parses as an op
+	// named "Error".
+	if err := commandError([]byte(appOpsUnknownPackage)); err == nil {
+		t.Error("commandError() missed an unknown package")
+	}
+}
@@ -0,0 +1,135 @@
+package adb
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Sandbox addresses a debuggable app's private storage through `run-as`,
+// which the shell user cannot read directly.
+type Sandbox struct {
+	Package string
+	User    int
+}
+
+// Root is the app's data directory for the sandbox's user.
+func (s Sandbox) Root() string {
+	if s.User == 0 {
+		return "/data/data/" + s.Package
+	}
+	return "/data/user/" + strconv.Itoa(s.User) + "/" + s.Package
+}
+
+// runAs prefixes cmd with `run-as`. --user is only passed for secondary
+// users since older run-as builds reject it. The device shell joins the
+// arguments, so callers quote paths with ShellQuote.
+func (s Sandbox) runAs(cmd ...string) []string {
+	args := []string{"run-as"}
+	if s.User != 0 {
+		args = append(args, "--user", userArg(s.User))
+	}
+	args = append(args, s.Package)
+	return append(args, cmd...)
+}
+
+func ListSandboxFilesCmd(serial string, sandbox Sandbox, path string) tea.Cmd {
+	return func() tea.Msg {
+		args := append([]string{"shell"}, sandbox.runAs("ls", "-la", ShellQuote(dirArg(path)))...)
+		return listFiles(serial, path, args...)
+	}
+}
+
+// PullSandboxFile streams a private file to localPath with
+// `exec-out run-as <pkg> cat`, since `adb pull` runs as the shell user.
+func PullSandboxFile(serial string, sandbox Sandbox, remotePath, localPath string) error {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+
+	args := []string{}
+	if serial != "" {
+		args = append(args, "-s", serial)
+	}
+	args = append(args, "exec-out")
+	args = append(args, sandbox.runAs("cat", ShellQuote(remotePath))...)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("adb", args...)
+	cmd.Stdout = file
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	closeErr := file.Close()
+
+	if runErr != nil {
+		os.Remove(localPath)
+		return fmt.Errorf("%w: %s", runErr, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return closeErr
+}
+
+func PullSandboxFileCmd(serial string, sandbox Sandbox, remotePath, localPath string) tea.Cmd {
+	return func() tea.Msg {
+		return FileActionResultMsg{
+			Action: "pull",
+			Error:  PullSandboxFile(serial, sandbox, remotePath, localPath),
+		}
+	}
+}
+
+// PushSandboxFileCmd pushes localPath to a world-readable temp file and
+// copies it into remoteDir as the app, so the copy is owned by the app.
+func PushSandboxFileCmd(serial string, sandbox Sandbox, localPath, remoteDir string) tea.Cmd {
+	return func() tea.Msg {
+		return FileActionResultMsg{
+			Action: "push",
+			Error:  pushSandboxFile(serial, sandbox, localPath, remoteDir),
+		}
+	}
+}
+
+func pushSandboxFile(serial string, sandbox Sandbox, localPath, remoteDir string) error {
+	name := filepath.Base(localPath)
+	tmp := "/data/local/tmp/adbt-" + config.SafeFileName(name)
+
+	if _, err := ExecuteCommand(serial, "push", localPath, tmp); err != nil {
+		return err
+	}
+	defer ExecuteCommand(serial, "shell", "rm", "-f", tmp)
+
+	if _, err := ExecuteCommand(serial, "shell", "chmod", "644", tmp); err != nil {
+		return err
+	}
+
+	args := append([]string{"shell"}, sandbox.runAs("cp", ShellQuote(tmp), ShellQuote(path.Join(remoteDir, name)))...)
+	out, err := ExecuteCommand(serial, args...)
+	if err == nil && len(bytes.TrimSpace(out)) > 0 {
+		// run-as cp reports permission errors on stdout with status 0 on
+		// some releases.
+		err = fmt.Errorf("%s", bytes.TrimSpace(out))
+	}
+	return err
+}
+
+func DeleteSandboxFileCmd(serial string, sandbox Sandbox, path string) tea.Cmd {
+	return func() tea.Msg {
+		args := append([]string{"shell"}, sandbox.runAs("rm", "-rf", ShellQuote(path))...)
+		_, err := ExecuteCommand(serial, args...)
+		return FileActionResultMsg{
+			Action: "delete",
+			Error:  err,
+		}
+	}
+}
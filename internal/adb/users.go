@@ -0,0 +1,98 @@
+package adb
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// UserInfo flag for managed (work) profiles.
+const userFlagManagedProfile = 0x20
+
+// User is an Android user or profile from `pm list users`.
+type User struct {
+	ID      int
+	Name    string
+	Flags   uint64
+	Running bool
+}
+
+// WorkProfile reports whether the user is a managed profile.
+func (u User) WorkProfile() bool {
+	return u.Flags&userFlagManagedProfile != 0
+}
+
+// Label returns "10 (Work profile)", or just the id when unnamed.
+func (u User) Label() string {
+	if u.Name == "" {
+		return strconv.Itoa(u.ID)
+	}
+	return strconv.Itoa(u.ID) + " (" + u.Name + ")"
+}
+
+type UsersLoadedMsg struct {
+	Serial string
+	Users  []User
+	Error  error
+}
+
+func ListUsers(serial string) ([]User, error) {
+	out, err := ExecuteCommand(serial, "shell", "pm", "list", "users")
+	if err != nil {
+		return nil, err
+	}
+	return ParseUsers(out), nil
+}
+
+func ListUsersCmd(serial string) tea.Cmd {
+	return func() tea.Msg {
+		users, err := ListUsers(serial)
+		return UsersLoadedMsg{Serial: serial, Users: users, Error: err}
+	}
+}
+
+// ParseUsers parses lines such as
+//
+//	UserInfo{0:Owner:c13} running
+//	UserInfo{10:Work profile:1030} running
+func ParseUsers(output []byte) []User {
+	var users []User
+
+	for _, line := range ParseLines(output) {
+		start := strings.Index(line, "UserInfo{")
+		end := strings.LastIndex(line, "}")
+		if start < 0 || end < start {
+			continue
+		}
+
+		fields := strings.Split(line[start+len("UserInfo{"):end], ":")
+		if len(fields) < 2 {
+			continue
+		}
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		u := User{
+			ID:      id,
+			Running: strings.Contains(line[end:], "running"),
+		}
+		// The name may itself contain colons; flags are always last.
+		if len(fields) >= 3 {
+			u.Name = strings.Join(fields[1:len(fields)-1], ":")
+			u.Flags, _ = strconv.ParseUint(fields[len(fields)-1], 16, 64)
+		} else {
+			u.Name = fields[1]
+		}
+		users = append(users, u)
+	}
+
+	return users
+}
+
+// userArg formats a user id for `--user`.
+func userArg(user int) string {
+	return strconv.Itoa(user)
+}
@@ -0,0 +1,77 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []User
+	}{
+		{
+			name: "owner and work profile",
+			output: `Users:
+	UserInfo{0:Owner:c13} running
+	UserInfo{10:Work profile:1030} running
+`,
+			want: []User{
+				{ID: 0, Name: "Owner", Flags: 0xc13, Running: true},
+				{ID: 10, Name: "Work profile", Flags: 0x1030, Running: true},
+			},
+		},
+		{
+			name: "stopped secondary user",
+			output: `Users:
+	UserInfo{0:Owner:4c13} running
+	UserInfo{11:Guest:414}
+`,
+			want: []User{
+				{ID: 0, Name: "Owner", Flags: 0x4c13, Running: true},
+				{ID: 11, Name: "Guest", Flags: 0x414},
+			},
+		},
+		{
+			name:   "name with colons and braces",
+			output: "\tUserInfo{12:Team: QA {lab}:0} running\r\n",
+			want:   []User{{ID: 12, Name: "Team: QA {lab}", Running: true}},
+		},
+		{
+			name:   "no flags",
+			output: "UserInfo{0:Owner}\n",
+			want:   []User{{ID: 0, Name: "Owner"}},
+		},
+		{
+			name:   "not a user list",
+			output: "Error: java.lang.SecurityException: Shell does not have permission to access user 10\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseUsers([]byte(tt.output)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUsers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserLabel(t *testing.T) {
+	work := User{ID: 10, Name: "Work profile", Flags: 0x1030}
+	if !work.WorkProfile() {
+		t.Error("WorkProfile() = false for flags 0x1030")
+	}
+	if got := work.Label(); got != "10 (Work profile)" {
+		t.Errorf("Label() = %q", got)
+	}
+
+	owner := User{ID: 0, Flags: 0xc13}
+	if owner.WorkProfile() {
+		t.Error("WorkProfile() = true for flags 0xc13")
+	}
+	if got := owner.Label(); got != "0" {
+		t.Errorf("Label() = %q, want 0", got)
+	}
+}
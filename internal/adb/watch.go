@@ -0,0 +1,224 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WatchInterval is how often a watched path is checked for new APKs.
+const WatchInterval = time.Second
+
+// Watcher decides when a watched APK path has changed. A change only
+// counts once the APKs look the same on two polls in a row, so an APK the
+// build is still writing is not installed half finished.
+type Watcher struct {
+	started   bool
+	seen      string
+	installed string
+}
+
+// Changed records a snapshot from WatchSnapshot and reports whether the
+// APKs should be reinstalled. The first snapshot is the baseline.
+func (w *Watcher) Changed(snapshot string) bool {
+	if !w.started {
+		w.started = true
+		w.seen = snapshot
+		w.installed = snapshot
+		return false
+	}
+
+	settled := snapshot == w.seen
+	w.seen = snapshot
+	if !settled || snapshot == "" || snapshot == w.installed {
+		return false
+	}
+	w.installed = snapshot
+	return true
+}
+
+type apkFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// watchedAPKs lists the APKs at path, which is an APK or a directory
+// searched recursively, such as a Gradle build output directory.
+func watchedAPKs(root string) ([]apkFile, error) {
+	var files []apkFile
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(strings.ToLower(d.Name()), ".apk") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Deleted between listing and stat, e.g. by a clean build.
+			return nil
+		}
+		files = append(files, apkFile{path: p, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// WatchSnapshot fingerprints the APKs at path by name, size and
+// modification time. It is empty when there are no APKs yet.
+func WatchSnapshot(path string) (string, error) {
+	files, err := watchedAPKs(path)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&b, "%s:%d:%d\n", f.path, f.size, f.modTime.UnixNano())
+	}
+	return b.String(), nil
+}
+
+// watchInstallPaths picks what to install from path: the newest APK, or
+// its whole directory when that holds split APKs next to a base.apk.
+func watchInstallPaths(path string) ([]string, error) {
+	files, err := watchedAPKs(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no APKs in %s", path)
+	}
+
+	newest := files[0]
+	for _, f := range files[1:] {
+		if f.modTime.After(newest.modTime) {
+			newest = f
+		}
+	}
+
+	dir := filepath.Dir(newest.path)
+	if _, err := os.Stat(filepath.Join(dir, "base.apk")); err == nil {
+		return []string{dir}, nil
+	}
+	return []string{newest.path}, nil
+}
+
+// WatchCycle is one reinstall triggered by a change.
+type WatchCycle struct {
+	Started  time.Time
+	Duration time.Duration
+	APK      string
+	Package  string
+	// Stage is the step that failed: "install", "force stop" or "launch".
+	Stage string
+	Error error
+}
+
+func (c WatchCycle) String() string {
+	took := c.Duration.Round(100 * time.Millisecond)
+	if c.Error != nil {
+		return fmt.Sprintf("%s failed after %s: %v", c.Stage, took, c.Error)
+	}
+	return fmt.Sprintf("reinstalled %s (%s) in %s", c.Package, filepath.Base(c.APK), took)
+}
+
+type WatchCycleMsg struct {
+	Cycle WatchCycle
+}
+
+func ReinstallCmd(serial, path string, user int) tea.Cmd {
+	return func() tea.Msg {
+		return WatchCycleMsg{Cycle: Reinstall(serial, path, user)}
+	}
+}
+
+// Reinstall installs the newest APK at path with -r, then force-stops and
+// relaunches the app so the new build is running.
+func Reinstall(serial, path string, user int) (cycle WatchCycle) {
+	cycle = WatchCycle{Started: time.Now(), Stage: "install"}
+	defer func() {
+		cycle.Duration = time.Since(cycle.Started)
+	}()
+
+	paths, err := watchInstallPaths(path)
+	if err != nil {
+		cycle.Error = err
+		return cycle
+	}
+	cycle.APK = paths[0]
+
+	opts := InstallOptions{Replace: true}
+	if user != 0 {
+		opts.User = strconv.Itoa(user)
+	}
+	plan, err := prepareInstall(serial, paths, opts)
+	if err != nil {
+		cycle.Error = err
+		return cycle
+	}
+	defer plan.Cleanup()
+
+	manifest, err := apk.ReadManifest(plan.APKs[0])
+	if err != nil {
+		cycle.Error = err
+		return cycle
+	}
+	cycle.Package = manifest.Package
+
+	if cycle.Error = install(serial, plan); cycle.Error != nil {
+		return cycle
+	}
+
+	cycle.Stage = "force stop"
+	if cycle.Error = ForceStopApp(serial, cycle.Package, user); cycle.Error != nil {
+		return cycle
+	}
+
+	cycle.Stage = "launch"
+	if cycle.Error = LaunchApp(serial, cycle.Package, user); cycle.Error != nil {
+		return cycle
+	}
+
+	cycle.Stage = ""
+	return cycle
+}
+
+// WatchSession reinstalls and relaunches the app every time the APKs at
+// path change, logging each cycle to out until ctx is cancelled.
+func WatchSession(ctx context.Context, serial, path string, user int, out io.Writer) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Watching %s for %s (ctrl+c to stop)\n", path, serial)
+
+	var watcher Watcher
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		snapshot, err := WatchSnapshot(path)
+		if err == nil && watcher.Changed(snapshot) {
+			cycle := Reinstall(serial, path, user)
+			fmt.Fprintf(out, "%s  %s\n", cycle.Started.Format("15:04:05"), cycle)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package alerts
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+)
+
+const logFile = "alerts.log"
+
+// maxEvents bounds the in-memory alert history; the full history stays in
+// the log file.
+const maxEvents = 200
+
+// Event is a fired alert.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Serial    string    `json:"serial"`
+	Rule      Rule      `json:"rule"`
+	Value     float64   `json:"value"`
+	HookError string    `json:"hook_error,omitempty"`
+}
+
+type ruleState struct {
+	since  time.Time
+	firing bool
+}
+
+// Monitor evaluates rules against incoming samples. It is owned by the UI
+// and only touched from the Bubble Tea update loop.
+type Monitor struct {
+	Rules  []Rule
+	Events []Event
+
+	// Unacknowledged events drive the persistent banner.
+	Unacknowledged []Event
+
+	LoadError error
+
+	states map[int]*ruleState
+}
+
+func NewMonitor() *Monitor {
+	rules, err := LoadRules()
+	return &Monitor{
+		Rules:     rules,
+		LoadError: err,
+		states:    map[int]*ruleState{},
+	}
+}
+
+func (m *Monitor) HasRules() bool {
+	return len(m.Rules) > 0
+}
+
+// Evaluate checks every rule watching one of the given metrics and returns
+// the alerts that fired. A rule fires once when its condition has held for
+// its duration, and re-arms after the condition clears.
+func (m *Monitor) Evaluate(serial string, values map[Metric]float64, now time.Time) []Event {
+	var fired []Event
+
+	for i, rule := range m.Rules {
+		value, ok := values[rule.Metric]
+		if !ok {
+			continue
+		}
+
+		st := m.states[i]
+		if st == nil {
+			st = &ruleState{}
+			m.states[i] = st
+		}
+
+		if !rule.Comparator.Holds(value, rule.Threshold) {
+			st.since = time.Time{}
+			st.firing = false
+			continue
+		}
+
+		if st.since.IsZero() {
+			st.since = now
+		}
+		if st.firing || now.Sub(st.since) < rule.Duration() {
+			continue
+		}
+
+		st.firing = true
+		event := Event{
+			Time:   now,
+			Serial: serial,
+			Rule:   rule,
+			Value:  value,
+		}
+		if rule.Hook != "" {
+			if err := runHook(event); err != nil {
+				event.HookError = err.Error()
+			}
+		}
+
+		_ = config.AppendJSONLine(logFile, event)
+		fired = append(fired, event)
+	}
+
+	if len(fired) > 0 {
+		m.Events = append(m.Events, fired...)
+		if len(m.Events) > maxEvents {
+			m.Events = m.Events[len(m.Events)-maxEvents:]
+		}
+		m.Unacknowledged = append(m.Unacknowledged, fired...)
+	}
+
+	return fired
+}
+
+func (m *Monitor) Acknowledge() {
+	m.Unacknowledged = nil
+}
+
+// Reset forgets in-progress conditions, e.g. after switching devices.
+func (m *Monitor) Reset() {
+	m.states = map[int]*ruleState{}
+}
+
+func (m *Monitor) AddRule(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	m.Rules = append(m.Rules, rule)
+	return SaveRules(m.Rules)
+}
+
+func (m *Monitor) RemoveRule(index int) error {
+	if index < 0 || index >= len(m.Rules) {
+		return nil
+	}
+	m.Rules = append(m.Rules[:index], m.Rules[index+1:]...)
+	// Rule states are keyed by index.
+	m.Reset()
+	return SaveRules(m.Rules)
+}
+
+// runHook starts the rule's hook command without waiting for it. Alert
+// details are passed through ADBT_ALERT_* environment variables.
+func runHook(e Event) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", e.Rule.Hook)
+	} else {
+		cmd = exec.Command("sh", "-c", e.Rule.Hook)
+	}
+
+	cmd.Env = append(os.Environ(),
+		"ADBT_SERIAL="+e.Serial,
+		"ADBT_ALERT_RULE="+e.Rule.String(),
+		"ADBT_ALERT_METRIC="+string(e.Rule.Metric),
+		"ADBT_ALERT_VALUE="+strconv.FormatFloat(e.Value, 'f', -1, 64),
+		"ADBT_ALERT_THRESHOLD="+strconv.FormatFloat(e.Rule.Threshold, 'f', -1, 64),
+		"ADBT_ALERT_TIME="+e.Time.Format(time.RFC3339),
+	)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+	return nil
+}
@@ -0,0 +1,132 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+)
+
+const rulesFile = "alerts.json"
+
+type Metric string
+
+const (
+	MetricCPU          Metric = "cpu_percent"
+	MetricMemUsed      Metric = "mem_used_percent"
+	MetricMemAvailable Metric = "mem_available_mb"
+	MetricNetRx        Metric = "net_rx_kbps"
+	MetricNetTx        Metric = "net_tx_kbps"
+	MetricBatteryTemp  Metric = "battery_temp_c"
+	MetricBatteryLevel Metric = "battery_level"
+)
+
+// Metrics lists every metric a rule can watch, in display order.
+var Metrics = []Metric{
+	MetricCPU,
+	MetricMemUsed,
+	MetricMemAvailable,
+	MetricNetRx,
+	MetricNetTx,
+	MetricBatteryTemp,
+	MetricBatteryLevel,
+}
+
+func (m Metric) Unit() string {
+	switch m {
+	case MetricCPU, MetricMemUsed, MetricBatteryLevel:
+		return "%"
+	case MetricMemAvailable:
+		return " MB"
+	case MetricNetRx, MetricNetTx:
+		return " KB/s"
+	case MetricBatteryTemp:
+		return "°C"
+	}
+	return ""
+}
+
+type Comparator string
+
+const (
+	Above        Comparator = ">"
+	AboveOrEqual Comparator = ">="
+	Below        Comparator = "<"
+	BelowOrEqual Comparator = "<="
+)
+
+var Comparators = []Comparator{Above, AboveOrEqual, Below, BelowOrEqual}
+
+func (c Comparator) Holds(value, threshold float64) bool {
+	switch c {
+	case Above:
+		return value > threshold
+	case AboveOrEqual:
+		return value >= threshold
+	case Below:
+		return value < threshold
+	case BelowOrEqual:
+		return value <= threshold
+	}
+	return false
+}
+
+// Rule fires once its condition has held continuously for Duration.
+// Hook, when set, is run through the system shell every time it fires.
+type Rule struct {
+	Metric          Metric     `json:"metric"`
+	Comparator      Comparator `json:"comparator"`
+	Threshold       float64    `json:"threshold"`
+	DurationSeconds int        `json:"duration_seconds"`
+	Hook            string     `json:"hook,omitempty"`
+}
+
+func (r Rule) Duration() time.Duration {
+	return time.Duration(r.DurationSeconds) * time.Second
+}
+
+func (r Rule) String() string {
+	s := fmt.Sprintf("%s %s %g%s", r.Metric, r.Comparator, r.Threshold, r.Metric.Unit())
+	if r.DurationSeconds > 0 {
+		s += fmt.Sprintf(" for %ds", r.DurationSeconds)
+	}
+	return s
+}
+
+func (r Rule) Validate() error {
+	known := false
+	for _, m := range Metrics {
+		if m == r.Metric {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown metric %q", r.Metric)
+	}
+
+	switch r.Comparator {
+	case Above, AboveOrEqual, Below, BelowOrEqual:
+	default:
+		return fmt.Errorf("unknown comparator %q", r.Comparator)
+	}
+
+	if r.DurationSeconds < 0 {
+		return fmt.Errorf("duration must not be negative")
+	}
+	return nil
+}
+
+func LoadRules() ([]Rule, error) {
+	var rules []Rule
+	if err := config.LoadJSON(rulesFile, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func SaveRules(rules []Rule) error {
+	if rules == nil {
+		rules = []Rule{}
+	}
+	return config.SaveJSON(rulesFile, rules)
+}
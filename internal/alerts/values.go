@@ -0,0 +1,25 @@
+package alerts
+
+import (
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/perf"
+)
+
+// SampleValues maps a derived performance sample onto rule metrics.
+func SampleValues(s perf.Sample) map[Metric]float64 {
+	return map[Metric]float64{
+		MetricCPU:          s.CPUPercent,
+		MetricMemUsed:      s.MemPercent,
+		MetricMemAvailable: float64(s.MemAvailableKB) / 1024,
+		MetricNetRx:        float64(s.NetRxRate) / 1024,
+		MetricNetTx:        float64(s.NetTxRate) / 1024,
+	}
+}
+
+// BatteryValues maps a battery sample onto rule metrics.
+func BatteryValues(b adb.BatteryStats) map[Metric]float64 {
+	return map[Metric]float64{
+		MetricBatteryTemp:  b.TemperatureC,
+		MetricBatteryLevel: float64(b.Level),
+	}
+}
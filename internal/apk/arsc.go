@@ -0,0 +1,262 @@
+package apk
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	chunkTable        = 0x0002
+	chunkTablePackage = 0x0200
+	chunkTableType    = 0x0201
+
+	typeFlagSparse   = 0x01
+	typeFlagOffset16 = 0x02
+
+	entryFlagComplex = 0x0001
+	entryFlagCompact = 0x0008
+
+	noEntry   = 0xffffffff
+	noEntry16 = 0xffff
+
+	// maxReferenceDepth bounds reference chains such as
+	// @string/app_name -> @string/brand_name.
+	maxReferenceDepth = 8
+)
+
+// ResourceTable holds the plain values of a compiled resources.arsc, which
+// is enough to resolve string resources such as the app label. Styles,
+// arrays and other bag resources are skipped.
+type ResourceTable struct {
+	strings []string
+	values  map[uint32][]resourceValue
+}
+
+// resourceValue is one configuration's value of a resource. Locale is ""
+// for the default configuration.
+type resourceValue struct {
+	locale   string
+	dataType byte
+	data     uint32
+}
+
+// ParseResourceTable decodes a resources.arsc file.
+func ParseResourceTable(data []byte) (*ResourceTable, error) {
+	if len(data) < 12 || binary.LittleEndian.Uint16(data) != chunkTable {
+		return nil, fmt.Errorf("not a resource table")
+	}
+
+	t := &ResourceTable{values: map[uint32][]resourceValue{}}
+	err := forEachChunk(data, int(binary.LittleEndian.Uint16(data[2:])), func(chunkType uint16, headerSize int, chunk []byte) error {
+		var err error
+		switch chunkType {
+		case chunkStringPool:
+			t.strings, err = parseStringPool(chunk)
+		case chunkTablePackage:
+			err = t.parsePackage(chunk, headerSize)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// forEachChunk calls fn for each chunk in data from offset on.
+func forEachChunk(data []byte, offset int, fn func(chunkType uint16, headerSize int, chunk []byte) error) error {
+	for offset+8 <= len(data) {
+		chunkType := binary.LittleEndian.Uint16(data[offset:])
+		headerSize := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		if size < 8 || headerSize > size || offset+size > len(data) {
+			return fmt.Errorf("invalid chunk at offset %d", offset)
+		}
+		if err := fn(chunkType, headerSize, data[offset:offset+size]); err != nil {
+			return err
+		}
+		offset += size
+	}
+	return nil
+}
+
+func (t *ResourceTable) parsePackage(chunk []byte, headerSize int) error {
+	if headerSize < 12 {
+		return fmt.Errorf("truncated package header")
+	}
+	pkg := binary.LittleEndian.Uint32(chunk[8:])
+
+	return forEachChunk(chunk, headerSize, func(chunkType uint16, headerSize int, chunk []byte) error {
+		if chunkType == chunkTableType {
+			return t.parseType(pkg, chunk, headerSize)
+		}
+		return nil
+	})
+}
+
+// parseType reads one configuration of a resource type. Entries are
+// indexed by 32-bit offsets, by 16-bit offsets in units of 4 bytes, or,
+// in sparse tables, by (index, offset/4) pairs.
+func (t *ResourceTable) parseType(pkg uint32, chunk []byte, headerSize int) error {
+	if headerSize < 20 {
+		return fmt.Errorf("truncated type header")
+	}
+	typeID := uint32(chunk[8])
+	flags := chunk[9]
+	count := int(binary.LittleEndian.Uint32(chunk[12:]))
+	entriesStart := int(binary.LittleEndian.Uint32(chunk[16:]))
+	locale := configLocale(chunk[20:headerSize])
+
+	width := 4
+	if flags&typeFlagOffset16 != 0 && flags&typeFlagSparse == 0 {
+		width = 2
+	}
+	if headerSize+count*width > len(chunk) {
+		return fmt.Errorf("truncated entry offsets")
+	}
+
+	for i := 0; i < count; i++ {
+		index, offset := uint32(i), 0
+		switch {
+		case flags&typeFlagSparse != 0:
+			pair := chunk[headerSize+i*4:]
+			index = uint32(binary.LittleEndian.Uint16(pair))
+			offset = int(binary.LittleEndian.Uint16(pair[2:])) * 4
+		case width == 2:
+			v := binary.LittleEndian.Uint16(chunk[headerSize+i*2:])
+			if v == noEntry16 {
+				continue
+			}
+			offset = int(v) * 4
+		default:
+			v := binary.LittleEndian.Uint32(chunk[headerSize+i*4:])
+			if v == noEntry {
+				continue
+			}
+			offset = int(v)
+		}
+
+		value, ok := parseEntry(chunk, entriesStart+offset)
+		if !ok {
+			continue
+		}
+		value.locale = locale
+		id := pkg<<24 | typeID<<16 | index
+		t.values[id] = append(t.values[id], value)
+	}
+	return nil
+}
+
+// parseEntry decodes a plain entry at pos. Compact entries keep the value
+// type in the high byte of their flags and the data in place of the key.
+func parseEntry(chunk []byte, pos int) (resourceValue, bool) {
+	if pos < 0 || pos+8 > len(chunk) {
+		return resourceValue{}, false
+	}
+	size := int(binary.LittleEndian.Uint16(chunk[pos:]))
+	flags := binary.LittleEndian.Uint16(chunk[pos+2:])
+
+	switch {
+	case flags&entryFlagCompact != 0:
+		return resourceValue{dataType: byte(flags >> 8), data: binary.LittleEndian.Uint32(chunk[pos+4:])}, true
+	case flags&entryFlagComplex != 0:
+		return resourceValue{}, false
+	}
+
+	// Res_value: size (2), res0 (1), dataType (1), data (4).
+	v := pos + size
+	if v+8 > len(chunk) {
+		return resourceValue{}, false
+	}
+	return resourceValue{dataType: chunk[v+3], data: binary.LittleEndian.Uint32(chunk[v+4:])}, true
+}
+
+// configLocale returns the language and region of a ResTable_config, e.g.
+// "fr" or "pt-BR", and "" when the configuration has no locale.
+func configLocale(config []byte) string {
+	if len(config) < 12 || config[8] == 0 {
+		return ""
+	}
+	locale := string(config[8:10])
+	if config[10] != 0 {
+		locale += "-" + string(config[10:12])
+	}
+	return locale
+}
+
+// String resolves a string resource, following references. It prefers
+// the default configuration, then English, then whichever comes first.
+func (t *ResourceTable) String(id uint32) (string, bool) {
+	for depth := 0; depth < maxReferenceDepth; depth++ {
+		v, ok := t.value(id)
+		if !ok {
+			return "", false
+		}
+		switch v.dataType {
+		case typeString:
+			if int(v.data) >= len(t.strings) {
+				return "", false
+			}
+			return t.strings[v.data], true
+		case typeReference:
+			id = v.data
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func (t *ResourceTable) value(id uint32) (resourceValue, bool) {
+	values := t.values[id]
+	if len(values) == 0 {
+		return resourceValue{}, false
+	}
+	for _, prefer := range []func(string) bool{
+		func(l string) bool { return l == "" },
+		func(l string) bool { return l == "en" || strings.HasPrefix(l, "en-") },
+	} {
+		for _, v := range values {
+			if prefer(v.locale) {
+				return v, true
+			}
+		}
+	}
+	return values[0], true
+}
+
+// ReadLabel returns the android:label of an APK read through r, looking
+// string resources up in its resources.arsc. It returns "" for an APK
+// without a label, which Android shows by package name.
+func ReadLabel(r io.ReaderAt, size int64) (string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := readManifest(archive)
+	if err != nil {
+		return "", err
+	}
+	var id uint32
+	if _, err := fmt.Sscanf(manifest.Label, "@0x%08x", &id); err != nil {
+		return manifest.Label, nil
+	}
+
+	data, err := readFile(archive, "resources.arsc")
+	if err != nil {
+		return "", err
+	}
+	table, err := ParseResourceTable(data)
+	if err != nil {
+		return "", fmt.Errorf("resources.arsc: %w", err)
+	}
+	label, ok := table.String(id)
+	if !ok {
+		return "", fmt.Errorf("label resource %s not found", manifest.Label)
+	}
+	return label, nil
+}
@@ -0,0 +1,276 @@
+package apk
+
+import (
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+)
+
+// arscEntry is a test resource value. A nil *arscEntry is a missing entry.
+type arscEntry struct {
+	dataType byte
+	data     uint32
+	complex  bool
+	compact  bool
+}
+
+func str(index uint32) *arscEntry { return &arscEntry{dataType: typeString, data: index} }
+func ref(id uint32) *arscEntry    { return &arscEntry{dataType: typeReference, data: id} }
+func compactStr(index uint32) *arscEntry {
+	return &arscEntry{dataType: typeString, data: index, compact: true}
+}
+
+// arscType is one configuration of a resource type.
+type arscType struct {
+	id      byte
+	locale  string
+	flags   byte
+	entries []*arscEntry
+}
+
+// encodeTable writes a resources.arsc with a single package, as aapt2
+// does for apps.
+func encodeTable(pool []string, utf8 bool, pkgID uint32, types ...arscType) []byte {
+	var pkgBody []byte
+	pkgBody = append(pkgBody, stringPoolChunk([]string{"attr", "string"}, false)...)
+	pkgBody = append(pkgBody, stringPoolChunk([]string{"app_name"}, false)...)
+	for _, t := range types {
+		pkgBody = append(pkgBody, encodeType(t)...)
+	}
+
+	const pkgHeaderSize = 288
+	pkg := chunkHeader(chunkTablePackage, pkgHeaderSize, pkgHeaderSize+len(pkgBody))
+	pkg = binary.LittleEndian.AppendUint32(pkg, pkgID)
+	pkg = append(pkg, make([]byte, 256)...) // name
+	pkg = append(pkg, make([]byte, 20)...)  // string pool offsets and typeIdOffset
+	pkg = append(pkg, pkgBody...)
+
+	body := append(stringPoolChunk(pool, utf8), pkg...)
+	table := chunkHeader(chunkTable, 12, 12+len(body))
+	table = binary.LittleEndian.AppendUint32(table, 1)
+	return append(table, body...)
+}
+
+func encodeType(t arscType) []byte {
+	var entries, offsets []byte
+	for i, e := range t.entries {
+		if t.flags&typeFlagSparse != 0 {
+			if e == nil {
+				continue
+			}
+			offsets = binary.LittleEndian.AppendUint16(offsets, uint16(i))
+			offsets = binary.LittleEndian.AppendUint16(offsets, uint16(len(entries)/4))
+		} else if t.flags&typeFlagOffset16 != 0 {
+			if e == nil {
+				offsets = binary.LittleEndian.AppendUint16(offsets, noEntry16)
+				continue
+			}
+			offsets = binary.LittleEndian.AppendUint16(offsets, uint16(len(entries)/4))
+		} else {
+			if e == nil {
+				offsets = binary.LittleEndian.AppendUint32(offsets, noEntry)
+				continue
+			}
+			offsets = binary.LittleEndian.AppendUint32(offsets, uint32(len(entries)))
+		}
+
+		switch {
+		case e.compact:
+			entries = binary.LittleEndian.AppendUint16(entries, 0) // key
+			entries = binary.LittleEndian.AppendUint16(entries, entryFlagCompact|uint16(e.dataType)<<8)
+			entries = binary.LittleEndian.AppendUint32(entries, e.data)
+		case e.complex:
+			entries = binary.LittleEndian.AppendUint16(entries, 16)
+			entries = binary.LittleEndian.AppendUint16(entries, entryFlagComplex)
+			entries = binary.LittleEndian.AppendUint32(entries, 0) // key
+			entries = binary.LittleEndian.AppendUint32(entries, 0) // parent
+			entries = binary.LittleEndian.AppendUint32(entries, 0) // count
+		default:
+			entries = binary.LittleEndian.AppendUint16(entries, 8)
+			entries = binary.LittleEndian.AppendUint16(entries, 0)
+			entries = binary.LittleEndian.AppendUint32(entries, 0) // key
+			entries = binary.LittleEndian.AppendUint16(entries, 8)
+			entries = append(entries, 0, e.dataType)
+			entries = binary.LittleEndian.AppendUint32(entries, e.data)
+		}
+	}
+	for len(offsets)%4 != 0 {
+		offsets = append(offsets, 0)
+	}
+
+	config := make([]byte, 64)
+	binary.LittleEndian.PutUint32(config, 64)
+	if t.locale != "" {
+		lang, region, _ := strings.Cut(t.locale, "-")
+		copy(config[8:10], lang)
+		copy(config[10:12], region)
+	}
+
+	count := len(offsets) / 4
+	if t.flags&typeFlagOffset16 != 0 && t.flags&typeFlagSparse == 0 {
+		count = len(t.entries)
+	}
+	headerSize := 20 + len(config)
+	chunk := chunkHeader(chunkTableType, headerSize, headerSize+len(offsets)+len(entries))
+	chunk = append(chunk, t.id, t.flags, 0, 0)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(count))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(headerSize+len(offsets)))
+	chunk = append(chunk, config...)
+	chunk = append(chunk, offsets...)
+	return append(chunk, entries...)
+}
+
+// testTable has the label of testManifest, @0x7f120001, in French and by
+// default, and a few other string resources.
+func testTable(utf8 bool) []byte {
+	pool := []string{"Exemple", "Example", "Brand", "Marke", "Brand (US)", "Compact", "Ünïcode ✓"}
+	return encodeTable(pool, utf8, 0x7f,
+		arscType{id: 0x12, locale: "fr", entries: []*arscEntry{nil, str(0)}},
+		arscType{id: 0x12, entries: []*arscEntry{
+			nil,
+			str(1),                 // 0x7f120001 label
+			ref(0x7f120003),        // 0x7f120002 -> brand
+			str(2),                 // 0x7f120003 brand
+			nil,                    // 0x7f120004 only localized
+			{complex: true},        // 0x7f120005 a plural
+			ref(0x7f120007),        // 0x7f120006 loops
+			ref(0x7f120006),        // 0x7f120007 loops
+			{dataType: typeIntDec}, // 0x7f120008 not a string
+			str(6),                 // 0x7f120009
+		}},
+		arscType{id: 0x12, locale: "de", entries: []*arscEntry{nil, nil, nil, nil, str(3)}},
+		arscType{id: 0x12, locale: "en-US", entries: []*arscEntry{nil, nil, nil, nil, str(4)}},
+		arscType{id: 0x13, flags: typeFlagSparse, entries: []*arscEntry{nil, nil, compactStr(5)}},
+		arscType{id: 0x14, flags: typeFlagOffset16, entries: []*arscEntry{str(2), nil, str(1)}},
+	)
+}
+
+func TestResourceTableString(t *testing.T) {
+	tests := []struct {
+		name string
+		id   uint32
+		want string
+		ok   bool
+	}{
+		{"default over French", 0x7f120001, "Example", true},
+		{"reference", 0x7f120002, "Brand", true},
+		{"English when there is no default", 0x7f120004, "Brand (US)", true},
+		{"sparse compact entry", 0x7f130002, "Compact", true},
+		{"16-bit offsets", 0x7f140002, "Example", true},
+		{"non-ASCII", 0x7f120009, "Ünïcode ✓", true},
+		{"16-bit offsets, missing entry", 0x7f140001, "", false},
+		{"bag resource", 0x7f120005, "", false},
+		{"reference loop", 0x7f120006, "", false},
+		{"integer", 0x7f120008, "", false},
+		{"missing entry", 0x7f120000, "", false},
+		{"other package", 0x01040001, "", false},
+	}
+
+	for _, pool := range []struct {
+		name string
+		utf8 bool
+	}{{"utf-16", false}, {"utf-8", true}} {
+		table, err := ParseResourceTable(testTable(pool.utf8))
+		if err != nil {
+			t.Fatalf("%s: %v", pool.name, err)
+		}
+		for _, tt := range tests {
+			t.Run(pool.name+"/"+tt.name, func(t *testing.T) {
+				got, ok := table.String(tt.id)
+				if got != tt.want || ok != tt.ok {
+					t.Errorf("String(0x%08x) = %q, %v, want %q, %v", tt.id, got, ok, tt.want, tt.ok)
+				}
+			})
+		}
+	}
+}
+
+func TestParseResourceTableInvalid(t *testing.T) {
+	valid := testTable(false)
+
+	for name, data := range map[string][]byte{
+		"empty":          nil,
+		"binary XML":     encodeAXML(testManifest(), false),
+		"chunk too long": append(valid[:12:12], chunkHeader(chunkTablePackage, 288, 1<<20)...),
+	} {
+		if _, err := ParseResourceTable(data); err == nil {
+			t.Errorf("ParseResourceTable(%s) returned no error", name)
+		}
+	}
+}
+
+// labelManifest is testManifest with label as its application label, or
+// no label when label is nil.
+func labelManifest(label *xmlAttr) *xmlNode {
+	m := testManifest()
+	app := m.children[2]
+	app.attrs = app.attrs[1:]
+	if label != nil {
+		app.attrs = append([]xmlAttr{*label}, app.attrs...)
+	}
+	return m
+}
+
+func readTestLabel(t *testing.T, path string) (string, error) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ReadLabel(f, info.Size())
+}
+
+func TestReadLabel(t *testing.T) {
+	literal := strAttr("label", 0x01010001, "Literal App")
+	missing := dataAttr("label", 0x01010001, typeReference, 0x7f120042)
+
+	tests := []struct {
+		name    string
+		files   []zipEntry
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "string resource",
+			files: []zipEntry{{"AndroidManifest.xml", encodeAXML(testManifest(), false)}, {"resources.arsc", testTable(true)}},
+			want:  "Example",
+		},
+		{
+			name:  "literal",
+			files: []zipEntry{{"AndroidManifest.xml", encodeAXML(labelManifest(&literal), false)}},
+			want:  "Literal App",
+		},
+		{
+			name:  "no label",
+			files: []zipEntry{{"AndroidManifest.xml", encodeAXML(labelManifest(nil), false)}},
+		},
+		{
+			name:    "no resources.arsc",
+			files:   []zipEntry{{"AndroidManifest.xml", encodeAXML(testManifest(), false)}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown resource",
+			files:   []zipEntry{{"AndroidManifest.xml", encodeAXML(labelManifest(&missing), false)}, {"resources.arsc", testTable(false)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTestLabel(t, writeAPK(t, tt.files, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadLabel() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReadLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
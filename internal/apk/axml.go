@@ -0,0 +1,313 @@
+package apk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Element is a node of a decoded binary XML document.
+type Element struct {
+	Name     string
+	Attrs    []Attr
+	Children []*Element
+}
+
+// Attr is a decoded attribute. Value holds strings as-is, booleans as
+// "true"/"false", integers in decimal and references as "@0x7f...".
+type Attr struct {
+	Name  string
+	Value string
+}
+
+// Attr returns the value of the named attribute.
+func (e *Element) Attr(name string) (string, bool) {
+	for _, a := range e.Attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// ChildrenNamed returns the direct children with the given tag.
+func (e *Element) ChildrenNamed(name string) []*Element {
+	var out []*Element
+	for _, c := range e.Children {
+		if c.Name == name {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+const (
+	chunkStringPool   = 0x0001
+	chunkXML          = 0x0003
+	chunkXMLStartElem = 0x0102
+	chunkXMLEndElem   = 0x0103
+	chunkResourceMap  = 0x0180
+
+	stringPoolUTF8 = 1 << 8
+
+	typeReference = 0x01
+	typeString    = 0x03
+	typeIntDec    = 0x10
+	typeIntHex    = 0x11
+	typeBoolean   = 0x12
+)
+
+// androidAttrs names framework attributes by resource id, for manifests
+// whose attribute name strings were stripped by obfuscators.
+var androidAttrs = map[uint32]string{
+	0x01010001: "label",
+	0x01010002: "icon",
+	0x01010003: "name",
+	0x01010006: "permission",
+	0x0101000e: "enabled",
+	0x0101000f: "debuggable",
+	0x01010010: "exported",
+	0x01010018: "authorities",
+	0x01010026: "mimeType",
+	0x01010027: "scheme",
+	0x01010028: "host",
+	0x01010029: "port",
+	0x0101002a: "path",
+	0x0101002b: "pathPrefix",
+	0x0101002c: "pathPattern",
+	0x01010202: "targetActivity",
+	0x0101020c: "minSdkVersion",
+	0x0101021b: "versionCode",
+	0x0101021c: "versionName",
+	0x01010270: "targetSdkVersion",
+	0x01010280: "allowBackup",
+	0x010103a6: "extractNativeLibs",
+	0x01010572: "compileSdkVersion",
+}
+
+// ParseAXML decodes Android's binary XML format, as used for
+// AndroidManifest.xml inside APKs, and returns the root element.
+func ParseAXML(data []byte) (*Element, error) {
+	if len(data) < 8 || binary.LittleEndian.Uint16(data) != chunkXML {
+		return nil, fmt.Errorf("not a binary XML document")
+	}
+
+	var (
+		strings     []string
+		resourceIDs []uint32
+		root        *Element
+		stack       []*Element
+	)
+
+	offset := int(binary.LittleEndian.Uint16(data[2:]))
+	for offset+8 <= len(data) {
+		chunkType := binary.LittleEndian.Uint16(data[offset:])
+		headerSize := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		if size < 8 || offset+size > len(data) {
+			return nil, fmt.Errorf("invalid chunk at offset %d", offset)
+		}
+		chunk := data[offset : offset+size]
+
+		switch chunkType {
+		case chunkStringPool:
+			var err error
+			strings, err = parseStringPool(chunk)
+			if err != nil {
+				return nil, err
+			}
+
+		case chunkResourceMap:
+			for i := headerSize; i+4 <= len(chunk); i += 4 {
+				resourceIDs = append(resourceIDs, binary.LittleEndian.Uint32(chunk[i:]))
+			}
+
+		case chunkXMLStartElem:
+			el, err := parseStartElement(chunk, headerSize, strings, resourceIDs)
+			if err != nil {
+				return nil, err
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, el)
+			} else if root == nil {
+				root = el
+			}
+			stack = append(stack, el)
+
+		case chunkXMLEndElem:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+
+		offset += size
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("document has no elements")
+	}
+	return root, nil
+}
+
+func parseStringPool(chunk []byte) ([]string, error) {
+	if len(chunk) < 28 {
+		return nil, fmt.Errorf("truncated string pool")
+	}
+
+	count := int(binary.LittleEndian.Uint32(chunk[8:]))
+	flags := binary.LittleEndian.Uint32(chunk[16:])
+	stringsStart := int(binary.LittleEndian.Uint32(chunk[20:]))
+	headerSize := int(binary.LittleEndian.Uint16(chunk[2:]))
+	utf8 := flags&stringPoolUTF8 != 0
+
+	if headerSize+count*4 > len(chunk) {
+		return nil, fmt.Errorf("truncated string pool")
+	}
+
+	out := make([]string, count)
+	for i := 0; i < count; i++ {
+		pos := stringsStart + int(binary.LittleEndian.Uint32(chunk[headerSize+i*4:]))
+		if pos >= len(chunk) {
+			return nil, fmt.Errorf("string %d out of range", i)
+		}
+
+		var err error
+		if utf8 {
+			out[i], err = decodeUTF8String(chunk[pos:])
+		} else {
+			out[i], err = decodeUTF16String(chunk[pos:])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// decodeUTF8String reads a string prefixed by its UTF-16 length and its
+// UTF-8 byte length, each one or two bytes long.
+func decodeUTF8String(b []byte) (string, error) {
+	_, n := utf8Length(b)
+	if n == 0 {
+		return "", fmt.Errorf("truncated string")
+	}
+	byteLen, m := utf8Length(b[n:])
+	if m == 0 || n+m+byteLen > len(b) {
+		return "", fmt.Errorf("truncated string")
+	}
+	return string(b[n+m : n+m+byteLen]), nil
+}
+
+func utf8Length(b []byte) (length, size int) {
+	if len(b) < 1 {
+		return 0, 0
+	}
+	if b[0]&0x80 == 0 {
+		return int(b[0]), 1
+	}
+	if len(b) < 2 {
+		return 0, 0
+	}
+	return int(b[0]&0x7f)<<8 | int(b[1]), 2
+}
+
+// decodeUTF16String reads a string prefixed by its length in UTF-16 units,
+// one or two units long.
+func decodeUTF16String(b []byte) (string, error) {
+	if len(b) < 2 {
+		return "", fmt.Errorf("truncated string")
+	}
+	length := int(binary.LittleEndian.Uint16(b))
+	start := 2
+	if length&0x8000 != 0 {
+		if len(b) < 4 {
+			return "", fmt.Errorf("truncated string")
+		}
+		length = (length&0x7fff)<<16 | int(binary.LittleEndian.Uint16(b[2:]))
+		start = 4
+	}
+	if start+length*2 > len(b) {
+		return "", fmt.Errorf("truncated string")
+	}
+
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[start+i*2:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+func parseStartElement(chunk []byte, headerSize int, strings []string, resourceIDs []uint32) (*Element, error) {
+	// Node header (16 bytes) is followed by ns, name, attributeStart,
+	// attributeSize and attributeCount.
+	ext := chunk[headerSize:]
+	if len(ext) < 20 {
+		return nil, fmt.Errorf("truncated element")
+	}
+
+	el := &Element{Name: stringAt(strings, binary.LittleEndian.Uint32(ext[4:]))}
+
+	attrStart := int(binary.LittleEndian.Uint16(ext[8:]))
+	attrSize := int(binary.LittleEndian.Uint16(ext[10:]))
+	attrCount := int(binary.LittleEndian.Uint16(ext[12:]))
+	if attrSize < 20 {
+		attrSize = 20
+	}
+
+	for i := 0; i < attrCount; i++ {
+		pos := attrStart + i*attrSize
+		if pos+20 > len(ext) {
+			return nil, fmt.Errorf("truncated attribute in <%s>", el.Name)
+		}
+		a := ext[pos:]
+
+		nameIndex := binary.LittleEndian.Uint32(a[4:])
+		name := stringAt(strings, nameIndex)
+		if int(nameIndex) < len(resourceIDs) {
+			if known, ok := androidAttrs[resourceIDs[nameIndex]]; ok {
+				name = known
+			}
+		}
+
+		raw := binary.LittleEndian.Uint32(a[8:])
+		dataType := a[15]
+		value := binary.LittleEndian.Uint32(a[16:])
+
+		el.Attrs = append(el.Attrs, Attr{
+			Name:  name,
+			Value: attrValue(strings, raw, dataType, value),
+		})
+	}
+
+	return el, nil
+}
+
+func attrValue(strings []string, raw uint32, dataType byte, data uint32) string {
+	if raw != 0xffffffff {
+		return stringAt(strings, raw)
+	}
+
+	switch dataType {
+	case typeString:
+		return stringAt(strings, data)
+	case typeBoolean:
+		return strconv.FormatBool(data != 0)
+	case typeIntDec:
+		return strconv.FormatInt(int64(int32(data)), 10)
+	case typeIntHex:
+		return "0x" + strconv.FormatUint(uint64(data), 16)
+	case typeReference:
+		return fmt.Sprintf("@0x%08x", data)
+	default:
+		return strconv.FormatUint(uint64(data), 10)
+	}
+}
+
+func stringAt(strings []string, index uint32) string {
+	if int(index) < len(strings) {
+		return strings[index]
+	}
+	return ""
+}
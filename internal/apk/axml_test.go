@@ -0,0 +1,288 @@
+package apk
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// xmlNode is a test element to encode as binary XML.
+type xmlNode struct {
+	name     string
+	attrs    []xmlAttr
+	children []*xmlNode
+}
+
+// xmlAttr is an attribute with either a string value or typed data. A
+// non-zero resID names a framework attribute.
+type xmlAttr struct {
+	name     string
+	resID    uint32
+	dataType byte
+	str      string
+	data     uint32
+}
+
+func node(name string, attrs []xmlAttr, children ...*xmlNode) *xmlNode {
+	return &xmlNode{name: name, attrs: attrs, children: children}
+}
+
+func strAttr(name string, resID uint32, value string) xmlAttr {
+	return xmlAttr{name: name, resID: resID, dataType: typeString, str: value}
+}
+
+func dataAttr(name string, resID uint32, dataType byte, data uint32) xmlAttr {
+	return xmlAttr{name: name, resID: resID, dataType: dataType, data: data}
+}
+
+// encodeAXML writes root the way aapt2 does: attribute names that have a
+// resource id come first in the string pool, in resource map order.
+func encodeAXML(root *xmlNode, utf8 bool) []byte {
+	var pool []string
+	index := map[string]uint32{}
+	intern := func(s string) {
+		if _, ok := index[s]; !ok {
+			index[s] = uint32(len(pool))
+			pool = append(pool, s)
+		}
+	}
+
+	var resIDs []uint32
+	var collect func(n *xmlNode, resource bool)
+	collect = func(n *xmlNode, resource bool) {
+		if !resource {
+			intern(n.name)
+		}
+		for _, a := range n.attrs {
+			if resource && a.resID != 0 {
+				if _, seen := index[a.name]; !seen {
+					resIDs = append(resIDs, a.resID)
+				}
+				intern(a.name)
+			}
+			if !resource {
+				intern(a.name)
+				if a.dataType == typeString {
+					intern(a.str)
+				}
+			}
+		}
+		for _, c := range n.children {
+			collect(c, resource)
+		}
+	}
+	collect(root, true)
+	collect(root, false)
+
+	body := stringPoolChunk(pool, utf8)
+
+	body = append(body, chunkHeader(chunkResourceMap, 8, 8+4*len(resIDs))...)
+	for _, id := range resIDs {
+		body = binary.LittleEndian.AppendUint32(body, id)
+	}
+
+	var element func(n *xmlNode)
+	element = func(n *xmlNode) {
+		body = append(body, chunkHeader(chunkXMLStartElem, 16, 16+20+20*len(n.attrs))...)
+		body = binary.LittleEndian.AppendUint32(body, 1)          // line
+		body = binary.LittleEndian.AppendUint32(body, 0xffffffff) // comment
+		body = binary.LittleEndian.AppendUint32(body, 0xffffffff) // namespace
+		body = binary.LittleEndian.AppendUint32(body, index[n.name])
+		body = binary.LittleEndian.AppendUint16(body, 20) // attributeStart
+		body = binary.LittleEndian.AppendUint16(body, 20) // attributeSize
+		body = binary.LittleEndian.AppendUint16(body, uint16(len(n.attrs)))
+		body = append(body, make([]byte, 6)...) // id, class and style indices
+		for _, a := range n.attrs {
+			raw, data := uint32(0xffffffff), a.data
+			if a.dataType == typeString {
+				raw, data = index[a.str], index[a.str]
+			}
+			body = binary.LittleEndian.AppendUint32(body, 0xffffffff)
+			body = binary.LittleEndian.AppendUint32(body, index[a.name])
+			body = binary.LittleEndian.AppendUint32(body, raw)
+			body = binary.LittleEndian.AppendUint16(body, 8)
+			body = append(body, 0, a.dataType)
+			body = binary.LittleEndian.AppendUint32(body, data)
+		}
+
+		for _, c := range n.children {
+			element(c)
+		}
+
+		body = append(body, chunkHeader(chunkXMLEndElem, 16, 24)...)
+		body = binary.LittleEndian.AppendUint32(body, 1)
+		body = binary.LittleEndian.AppendUint32(body, 0xffffffff)
+		body = binary.LittleEndian.AppendUint32(body, 0xffffffff)
+		body = binary.LittleEndian.AppendUint32(body, index[n.name])
+	}
+	element(root)
+
+	return append(chunkHeader(chunkXML, 8, 8+len(body)), body...)
+}
+
+func chunkHeader(chunkType uint16, headerSize, size int) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, chunkType)
+	b = binary.LittleEndian.AppendUint16(b, uint16(headerSize))
+	return binary.LittleEndian.AppendUint32(b, uint32(size))
+}
+
+func stringPoolChunk(pool []string, utf8 bool) []byte {
+	var data []byte
+	var offsets []uint32
+	for _, s := range pool {
+		offsets = append(offsets, uint32(len(data)))
+		units := utf16.Encode([]rune(s))
+		if utf8 {
+			data = append(data, byte(len(units)), byte(len(s)))
+			data = append(data, s...)
+			data = append(data, 0)
+			continue
+		}
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(units)))
+		for _, u := range units {
+			data = binary.LittleEndian.AppendUint16(data, u)
+		}
+		data = append(data, 0, 0)
+	}
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+
+	var flags uint32
+	if utf8 {
+		flags = stringPoolUTF8
+	}
+	stringsStart := 28 + 4*len(pool)
+	chunk := chunkHeader(chunkStringPool, 28, stringsStart+len(data))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(pool)))
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // styles
+	chunk = binary.LittleEndian.AppendUint32(chunk, flags)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(stringsStart))
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // stylesStart
+	for _, off := range offsets {
+		chunk = binary.LittleEndian.AppendUint32(chunk, off)
+	}
+	return append(chunk, data...)
+}
+
+// testManifest is a small manifest as aapt2 compiles it.
+func testManifest() *xmlNode {
+	return node("manifest", []xmlAttr{
+		dataAttr("versionCode", 0x0101021b, typeIntDec, 42),
+		strAttr("versionName", 0x0101021c, "1.4.2"),
+		strAttr("package", 0, "com.example.app"),
+	},
+		node("uses-sdk", []xmlAttr{
+			dataAttr("minSdkVersion", 0x0101020c, typeIntDec, 24),
+			dataAttr("targetSdkVersion", 0x01010270, typeIntDec, 34),
+		}),
+		node("uses-permission", []xmlAttr{strAttr("name", 0x01010003, "android.permission.INTERNET")}),
+		node("application", []xmlAttr{
+			dataAttr("label", 0x01010001, typeReference, 0x7f120001),
+			dataAttr("debuggable", 0x0101000f, typeBoolean, 0xffffffff),
+		},
+			node("activity", []xmlAttr{
+				strAttr("name", 0x01010003, ".MainActivity"),
+				dataAttr("exported", 0x01010010, typeBoolean, 0xffffffff),
+			},
+				node("intent-filter", nil,
+					node("action", []xmlAttr{strAttr("name", 0x01010003, "android.intent.action.MAIN")}),
+					node("category", []xmlAttr{strAttr("name", 0x01010003, "android.intent.category.LAUNCHER")}),
+				),
+			),
+		),
+	)
+}
+
+func TestParseAXML(t *testing.T) {
+	want := &Element{
+		Name: "manifest",
+		Attrs: []Attr{
+			{Name: "versionCode", Value: "42"},
+			{Name: "versionName", Value: "1.4.2"},
+			{Name: "package", Value: "com.example.app"},
+		},
+		Children: []*Element{
+			{Name: "uses-sdk", Attrs: []Attr{{Name: "minSdkVersion", Value: "24"}, {Name: "targetSdkVersion", Value: "34"}}},
+			{Name: "uses-permission", Attrs: []Attr{{Name: "name", Value: "android.permission.INTERNET"}}},
+			{
+				Name:  "application",
+				Attrs: []Attr{{Name: "label", Value: "@0x7f120001"}, {Name: "debuggable", Value: "true"}},
+				Children: []*Element{{
+					Name:  "activity",
+					Attrs: []Attr{{Name: "name", Value: ".MainActivity"}, {Name: "exported", Value: "true"}},
+					Children: []*Element{{
+						Name: "intent-filter",
+						Children: []*Element{
+							{Name: "action", Attrs: []Attr{{Name: "name", Value: "android.intent.action.MAIN"}}},
+							{Name: "category", Attrs: []Attr{{Name: "name", Value: "android.intent.category.LAUNCHER"}}},
+						},
+					}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name string
+		utf8 bool
+	}{{"utf-16 string pool", false}, {"utf-8 string pool", true}} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAXML(encodeAXML(testManifest(), tt.utf8))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseAXML() = %s, want %s", dumpElement(got), dumpElement(want))
+			}
+		})
+	}
+}
+
+func TestParseAXMLObfuscatedNames(t *testing.T) {
+	// Obfuscators blank the attribute name strings; the resource map still
+	// identifies them.
+	root := node("manifest", []xmlAttr{strAttr("", 0x0101021c, "2.0")})
+	got, err := ParseAXML(encodeAXML(root, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := got.Attr("versionName"); !ok || v != "2.0" {
+		t.Errorf("versionName = %q, %v, want 2.0", v, ok)
+	}
+}
+
+func TestParseAXMLInvalid(t *testing.T) {
+	valid := encodeAXML(testManifest(), false)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"plain text XML", []byte(`<?xml version="1.0" encoding="utf-8"?><manifest/>`)},
+		{"truncated", valid[:len(valid)/2]},
+		{"no elements", chunkHeader(chunkXML, 8, 8)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAXML(tt.data); err == nil {
+				t.Error("ParseAXML() returned no error")
+			}
+		})
+	}
+}
+
+func dumpElement(e *Element) string {
+	s := "<" + e.Name
+	for _, a := range e.Attrs {
+		s += " " + a.Name + "=" + a.Value
+	}
+	s += ">"
+	for _, c := range e.Children {
+		s += dumpElement(c)
+	}
+	return s + "</" + e.Name + ">"
+}
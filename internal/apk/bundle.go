@@ -0,0 +1,317 @@
+package apk
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DeviceSpec describes the device splits are chosen for. Empty fields keep
+// every split of that kind.
+type DeviceSpec struct {
+	// ABIs in the device's preference order (ro.product.cpu.abilist).
+	ABIs    []string
+	Density int
+}
+
+// Bundle is an extracted .apks or .xapk archive.
+type Bundle struct {
+	Package string
+	APKs    []string
+	OBBs    []OBB
+	// Skipped lists the splits left out for this device.
+	Skipped []string
+}
+
+// OBB is an expansion file shipped in an .xapk.
+type OBB struct {
+	Local  string
+	Remote string // relative to the shared storage root
+}
+
+var splitABIs = map[string]string{
+	"arm64_v8a":   "arm64-v8a",
+	"armeabi_v7a": "armeabi-v7a",
+	"armeabi":     "armeabi",
+	"x86":         "x86",
+	"x86_64":      "x86_64",
+	"mips":        "mips",
+	"mips64":      "mips64",
+}
+
+var splitDensities = map[string]int{
+	"ldpi":    120,
+	"mdpi":    160,
+	"tvdpi":   213,
+	"hdpi":    240,
+	"xhdpi":   320,
+	"xxhdpi":  480,
+	"xxxhdpi": 640,
+}
+
+// IsBundle reports whether path looks like an .apks or .xapk archive.
+func IsBundle(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".apks" || ext == ".xapk"
+}
+
+type split struct {
+	entry     string
+	module    string
+	qualifier string
+}
+
+type xapkManifest struct {
+	PackageName string `json:"package_name"`
+	SplitAPKs   []struct {
+		File string `json:"file"`
+		ID   string `json:"id"`
+	} `json:"split_apks"`
+	Expansions []struct {
+		File        string `json:"file"`
+		InstallPath string `json:"install_path"`
+	} `json:"expansions"`
+}
+
+// ExtractBundle extracts the splits of an .apks (bundletool) or .xapk
+// archive that match spec into destDir.
+func ExtractBundle(archivePath, destDir string, spec DeviceSpec) (Bundle, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return Bundle{}, err
+	}
+	defer archive.Close()
+
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var bundle Bundle
+	var splits []split
+
+	if f, ok := files["manifest.json"]; ok {
+		manifest, err := readXAPKManifest(f)
+		if err != nil {
+			return Bundle{}, err
+		}
+		bundle.Package = manifest.PackageName
+		for _, s := range manifest.SplitAPKs {
+			splits = append(splits, xapkSplit(s.File, s.ID))
+		}
+		for _, e := range manifest.Expansions {
+			remote := path.Clean(e.InstallPath)
+			if e.InstallPath == "" || path.IsAbs(remote) || strings.HasPrefix(remote, "..") {
+				remote = path.Join("Android/obb", manifest.PackageName, path.Base(e.File))
+			}
+			bundle.OBBs = append(bundle.OBBs, OBB{Local: e.File, Remote: remote})
+		}
+	}
+
+	if len(splits) == 0 {
+		splits = archiveSplits(archive.File)
+	}
+	if len(splits) == 0 {
+		return Bundle{}, fmt.Errorf("no APKs found in %s", filepath.Base(archivePath))
+	}
+
+	selected, skipped := selectSplits(splits, spec)
+	bundle.Skipped = skipped
+
+	for i, s := range selected {
+		f, ok := files[s.entry]
+		if !ok {
+			return Bundle{}, fmt.Errorf("%s: missing %s", filepath.Base(archivePath), s.entry)
+		}
+		// Prefix with the index so equally named entries from different
+		// directories cannot collide.
+		dest := filepath.Join(destDir, fmt.Sprintf("%02d-%s", i, path.Base(s.entry)))
+		if err := extractFile(f, dest); err != nil {
+			return Bundle{}, err
+		}
+		bundle.APKs = append(bundle.APKs, dest)
+	}
+
+	for i, obb := range bundle.OBBs {
+		f, ok := files[obb.Local]
+		if !ok {
+			return Bundle{}, fmt.Errorf("%s: missing %s", filepath.Base(archivePath), obb.Local)
+		}
+		dest := filepath.Join(destDir, path.Base(obb.Local))
+		if err := extractFile(f, dest); err != nil {
+			return Bundle{}, err
+		}
+		bundle.OBBs[i].Local = dest
+	}
+
+	return bundle, nil
+}
+
+func readXAPKManifest(f *zip.File) (xapkManifest, error) {
+	var manifest xapkManifest
+
+	rc, err := f.Open()
+	if err != nil {
+		return manifest, err
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("invalid manifest.json: %w", err)
+	}
+	return manifest, nil
+}
+
+// xapkSplit names splits after their id: "base", "config.arm64_v8a" or
+// "<feature>.config.xxhdpi".
+func xapkSplit(file, id string) split {
+	if id == "" {
+		id = strings.TrimSuffix(path.Base(file), ".apk")
+	}
+
+	s := split{entry: file, module: "base"}
+	switch {
+	case strings.HasPrefix(id, "config."):
+		s.qualifier = strings.TrimPrefix(id, "config.")
+	case strings.Contains(id, ".config."):
+		s.module, s.qualifier, _ = strings.Cut(id, ".config.")
+	case id != "base":
+		s.module = id
+	}
+	return s
+}
+
+// archiveSplits lists APKs in a bundletool .apks archive, where splits are
+// named splits/<module>-<qualifier>.apk. Archives without splits, e.g.
+// universal ones, install every APK they contain.
+func archiveSplits(files []*zip.File) []split {
+	var splits, others []split
+
+	for _, f := range files {
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".apk") {
+			continue
+		}
+
+		dir, name := path.Split(f.Name)
+		name = strings.TrimSuffix(name, path.Ext(name))
+
+		if dir == "splits/" {
+			module, qualifier, _ := strings.Cut(name, "-")
+			if qualifier == "master" || strings.HasPrefix(qualifier, "master_") {
+				qualifier = ""
+			}
+			splits = append(splits, split{entry: f.Name, module: module, qualifier: qualifier})
+			continue
+		}
+
+		if dir == "" || dir == "universal/" {
+			others = append(others, split{entry: f.Name, module: name})
+		}
+	}
+
+	if len(splits) > 0 {
+		return splits
+	}
+	return others
+}
+
+// selectSplits keeps master and language splits, the best matching ABI
+// split and the closest density split of each module.
+func selectSplits(splits []split, spec DeviceSpec) (selected []split, skipped []string) {
+	modules := map[string][]split{}
+	var order []string
+	for _, s := range splits {
+		if _, ok := modules[s.module]; !ok {
+			order = append(order, s.module)
+		}
+		modules[s.module] = append(modules[s.module], s)
+	}
+
+	for _, module := range order {
+		abi := bestABI(modules[module], spec.ABIs)
+		density := bestDensity(modules[module], spec.Density)
+
+		for _, s := range modules[module] {
+			keep := true
+			if _, ok := splitABIs[s.qualifier]; ok && len(spec.ABIs) > 0 {
+				keep = s.qualifier == abi
+			}
+			if _, ok := splitDensities[s.qualifier]; ok && spec.Density > 0 {
+				keep = s.qualifier == density
+			}
+
+			if keep {
+				selected = append(selected, s)
+			} else {
+				skipped = append(skipped, path.Base(s.entry))
+			}
+		}
+	}
+
+	// Masters first so the base APK leads the install session.
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].qualifier == "" && selected[j].qualifier != ""
+	})
+	return selected, skipped
+}
+
+func bestABI(splits []split, abis []string) string {
+	for _, abi := range abis {
+		for _, s := range splits {
+			if splitABIs[s.qualifier] == abi {
+				return s.qualifier
+			}
+		}
+	}
+	return ""
+}
+
+// bestDensity picks the smallest density at or above the device's, or the
+// largest available when every split is below it.
+func bestDensity(splits []split, density int) string {
+	best, bestValue := "", 0
+	largest, largestValue := "", 0
+
+	for _, s := range splits {
+		value, ok := splitDensities[s.qualifier]
+		if !ok {
+			continue
+		}
+		if value >= density && (best == "" || value < bestValue) {
+			best, bestValue = s.qualifier, value
+		}
+		if value > largestValue {
+			largest, largestValue = s.qualifier, value
+		}
+	}
+
+	if best != "" {
+		return best
+	}
+	return largest
+}
+
+func extractFile(f *zip.File, dest string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,95 @@
+package apk
+
+import (
+	"archive/zip"
+	"sort"
+	"strings"
+)
+
+// Info summarises a local APK for the install preview.
+type Info struct {
+	Manifest Manifest
+	// ABIs that the APK ships native libraries for. Empty for APKs without
+	// native code, which run on any ABI.
+	ABIs           []string
+	LaunchActivity string
+	Certificates   []Certificate
+}
+
+// Inspect reads an APK's manifest, native ABIs and signing certificates.
+// An unsigned APK is not an error; its Certificates are empty.
+func Inspect(apkPath string) (Info, error) {
+	manifest, err := ReadManifest(apkPath)
+	if err != nil {
+		return Info{}, err
+	}
+
+	info := Info{
+		Manifest:       manifest,
+		LaunchActivity: manifest.LaunchActivity(),
+	}
+
+	if info.ABIs, err = NativeABIs(apkPath); err != nil {
+		return info, err
+	}
+
+	info.Certificates, err = SigningCertificates(apkPath)
+	if err != nil && err != ErrNotSigned {
+		return info, err
+	}
+
+	return info, nil
+}
+
+// NativeABIs lists the lib/<abi>/ directories that contain .so files.
+func NativeABIs(apkPath string) ([]string, error) {
+	archive, err := zip.OpenReader(apkPath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	seen := map[string]bool{}
+	for _, f := range archive.File {
+		rest, ok := strings.CutPrefix(f.Name, "lib/")
+		if !ok || !strings.HasSuffix(rest, ".so") {
+			continue
+		}
+		if abi, _, ok := strings.Cut(rest, "/"); ok && abi != "" {
+			seen[abi] = true
+		}
+	}
+
+	abis := make([]string, 0, len(seen))
+	for abi := range seen {
+		abis = append(abis, abi)
+	}
+	sort.Strings(abis)
+	return abis, nil
+}
+
+// LaunchActivity returns the component name of the first enabled activity
+// with a MAIN/LAUNCHER intent filter, or "" when there is none.
+func (m Manifest) LaunchActivity() string {
+	for _, c := range m.ComponentsOf(Activity) {
+		if !c.Enabled {
+			continue
+		}
+		for _, f := range c.Filters {
+			if contains(f.Actions, "android.intent.action.MAIN") &&
+				contains(f.Categories, "android.intent.category.LAUNCHER") {
+				return m.ComponentName(c)
+			}
+		}
+	}
+	return ""
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package apk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	der := testCertificate(t, "Release")
+	path := writeAPK(t, []zipEntry{
+		{"AndroidManifest.xml", encodeAXML(testManifest(), true)},
+		{"classes.dex", []byte("dex\n035\x00")},
+		{"lib/x86_64/libnative.so", []byte("\x7fELF")},
+		{"lib/arm64-v8a/libnative.so", []byte("\x7fELF")},
+		{"lib/arm64-v8a/libc++_shared.so", []byte("\x7fELF")},
+		{"lib/armeabi-v7a/README.txt", []byte("not a library")},
+		{"assets/lib/x86/libfake.so", []byte("\x7fELF")},
+	}, signingBlock(blockPair{sigSchemeV2ID, schemeValue([][]byte{der})}))
+
+	info, err := Inspect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := info.Manifest
+	if m.Package != "com.example.app" || m.VersionCode != "42" || m.VersionName != "1.4.2" ||
+		m.MinSDK != "24" || m.TargetSDK != "34" || !m.Debuggable {
+		t.Errorf("Manifest = %+v", m)
+	}
+	if info.LaunchActivity != "com.example.app/.MainActivity" {
+		t.Errorf("LaunchActivity = %q", info.LaunchActivity)
+	}
+	if want := []string{"arm64-v8a", "x86_64"}; !reflect.DeepEqual(info.ABIs, want) {
+		t.Errorf("ABIs = %v, want %v", info.ABIs, want)
+	}
+	if len(info.Certificates) != 1 || info.Certificates[0] != newCertificate(der, "v2") {
+		t.Errorf("Certificates = %+v", info.Certificates)
+	}
+}
+
+func TestInspectUnsigned(t *testing.T) {
+	path := writeAPK(t, []zipEntry{{"AndroidManifest.xml", encodeAXML(testManifest(), false)}}, nil)
+
+	info, err := Inspect(path)
+	if err != nil {
+		t.Fatalf("Inspect() of an unsigned APK: %v", err)
+	}
+	if len(info.Certificates) != 0 || len(info.ABIs) != 0 {
+		t.Errorf("Inspect() = %+v, want no certificates or ABIs", info)
+	}
+}
+
+func TestInspectNoManifest(t *testing.T) {
+	path := writeAPK(t, []zipEntry{{"classes.dex", []byte("dex")}}, nil)
+	if _, err := Inspect(path); err == nil {
+		t.Error("Inspect() of an APK without a manifest returned no error")
+	}
+}
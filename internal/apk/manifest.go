@@ -0,0 +1,379 @@
+package apk
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ComponentType is the manifest tag of an app component.
+type ComponentType string
+
+const (
+	Activity ComponentType = "activity"
+	Service  ComponentType = "service"
+	Receiver ComponentType = "receiver"
+	Provider ComponentType = "provider"
+)
+
+// ComponentTypes lists component types in display order.
+var ComponentTypes = []ComponentType{Activity, Service, Receiver, Provider}
+
+// Manifest is the decoded AndroidManifest.xml of an APK.
+type Manifest struct {
+	Package     string
+	VersionCode string
+	VersionName string
+	MinSDK      string
+	TargetSDK   string
+	// Label is the application's android:label, either text or a string
+	// resource reference such as "@0x7f120001". ReadLabel resolves it.
+	Label       string
+	Debuggable  bool
+	Permissions []string
+	Components  []Component
+}
+
+type Component struct {
+	Type ComponentType
+	// Name is the fully qualified class name.
+	Name     string
+	Exported bool
+	Enabled  bool
+	// Permission required to start or bind the component.
+	Permission string
+	// Authorities of a provider.
+	Authorities []string
+	// TargetActivity is set for <activity-alias>.
+	TargetActivity string
+	Filters        []IntentFilter
+}
+
+type IntentFilter struct {
+	Actions    []string
+	Categories []string
+	Data       []string
+	// AutoVerify is android:autoVerify, set on filters for verified app
+	// links.
+	AutoVerify bool
+	// Schemes, Hosts and Paths of all <data> elements. Android matches
+	// every combination of them, not just those declared together. Hosts
+	// include the port and paths end in "*" for a pathPrefix.
+	Schemes []string
+	Hosts   []string
+	Paths   []string
+}
+
+// Has reports whether the filter lists action.
+func (f IntentFilter) Has(action string) bool {
+	for _, a := range f.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// URLPatterns combines the filter's schemes, hosts and paths, e.g.
+// "https://example.com/orders/*".
+func (f IntentFilter) URLPatterns() []string {
+	hosts := f.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{""}
+	}
+	paths := f.Paths
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	var out []string
+	for _, scheme := range f.Schemes {
+		for _, host := range hosts {
+			for _, path := range paths {
+				if host == "" {
+					out = append(out, scheme+":"+path)
+					continue
+				}
+				out = append(out, scheme+"://"+host+path)
+			}
+		}
+	}
+	return out
+}
+
+// ComponentName returns the "pkg/class" form used by `am` commands.
+func (m Manifest) ComponentName(c Component) string {
+	if rest, ok := strings.CutPrefix(c.Name, m.Package+"."); ok {
+		return m.Package + "/." + rest
+	}
+	return m.Package + "/" + c.Name
+}
+
+// ComponentsOf returns the components of one type in manifest order.
+func (m Manifest) ComponentsOf(t ComponentType) []Component {
+	var out []Component
+	for _, c := range m.Components {
+		if c.Type == t {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ReadManifest decodes the AndroidManifest.xml of an APK.
+func ReadManifest(apkPath string) (Manifest, error) {
+	archive, err := zip.OpenReader(apkPath)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer archive.Close()
+
+	return readManifest(&archive.Reader)
+}
+
+// ReadManifestAt decodes the AndroidManifest.xml of an APK read through r.
+func ReadManifestAt(r io.ReaderAt, size int64) (Manifest, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return readManifest(archive)
+}
+
+// MergeSplit adds the components and permissions declared by the manifest
+// of one of the app's split APKs. Configuration splits declare none.
+func (m *Manifest) MergeSplit(split Manifest) {
+	m.Components = append(m.Components, split.Components...)
+	for _, p := range split.Permissions {
+		m.Permissions = appendUnique(m.Permissions, p)
+	}
+}
+
+func readManifest(archive *zip.Reader) (Manifest, error) {
+	data, err := readFile(archive, "AndroidManifest.xml")
+	if err != nil {
+		return Manifest{}, err
+	}
+	root, err := ParseAXML(data)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("AndroidManifest.xml: %w", err)
+	}
+	return parseManifest(root), nil
+}
+
+// readFile reads one file of an archive.
+func readFile(archive *zip.Reader, name string) ([]byte, error) {
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("no %s in the APK", name)
+}
+
+func parseManifest(root *Element) Manifest {
+	m := Manifest{}
+	m.Package, _ = root.Attr("package")
+	m.VersionCode, _ = root.Attr("versionCode")
+	m.VersionName, _ = root.Attr("versionName")
+
+	for _, sdk := range root.ChildrenNamed("uses-sdk") {
+		m.MinSDK, _ = sdk.Attr("minSdkVersion")
+		m.TargetSDK, _ = sdk.Attr("targetSdkVersion")
+	}
+	if m.MinSDK == "" {
+		m.MinSDK = "1"
+	}
+	if m.TargetSDK == "" {
+		m.TargetSDK = m.MinSDK
+	}
+	targetSDK, _ := strconv.Atoi(m.TargetSDK)
+
+	for _, tag := range []string{"uses-permission", "uses-permission-sdk-23"} {
+		for _, p := range root.ChildrenNamed(tag) {
+			if name, ok := p.Attr("name"); ok {
+				m.Permissions = append(m.Permissions, name)
+			}
+		}
+	}
+
+	for _, app := range root.ChildrenNamed("application") {
+		m.Label, _ = app.Attr("label")
+		if v, _ := app.Attr("debuggable"); v == "true" {
+			m.Debuggable = true
+		}
+
+		for _, el := range app.Children {
+			var t ComponentType
+			switch el.Name {
+			case "activity", "activity-alias":
+				t = Activity
+			case "service":
+				t = Service
+			case "receiver":
+				t = Receiver
+			case "provider":
+				t = Provider
+			default:
+				continue
+			}
+			m.Components = append(m.Components, parseComponent(el, t, m.Package, targetSDK))
+		}
+	}
+
+	return m
+}
+
+func parseComponent(el *Element, t ComponentType, pkg string, targetSDK int) Component {
+	name, _ := el.Attr("name")
+	c := Component{
+		Type:    t,
+		Name:    qualifyClass(pkg, name),
+		Enabled: true,
+	}
+
+	if v, ok := el.Attr("enabled"); ok {
+		c.Enabled = v != "false"
+	}
+	c.Permission, _ = el.Attr("permission")
+	if target, ok := el.Attr("targetActivity"); ok {
+		c.TargetActivity = qualifyClass(pkg, target)
+	}
+	if authorities, ok := el.Attr("authorities"); ok {
+		c.Authorities = strings.Split(authorities, ";")
+	}
+
+	for _, f := range el.ChildrenNamed("intent-filter") {
+		c.Filters = append(c.Filters, parseIntentFilter(f))
+	}
+
+	// Without android:exported, components with intent filters are
+	// exported (rejected at install since API 31), and providers are
+	// exported when targeting API 16 or lower.
+	if v, ok := el.Attr("exported"); ok {
+		c.Exported = v == "true"
+	} else if t == Provider {
+		c.Exported = targetSDK > 0 && targetSDK < 17
+	} else {
+		c.Exported = len(c.Filters) > 0
+	}
+
+	return c
+}
+
+func parseIntentFilter(el *Element) IntentFilter {
+	var f IntentFilter
+	if v, ok := el.Attr("autoVerify"); ok {
+		f.AutoVerify = v == "true"
+	}
+	for _, child := range el.Children {
+		switch child.Name {
+		case "action":
+			if name, ok := child.Attr("name"); ok {
+				f.Actions = append(f.Actions, name)
+			}
+		case "category":
+			if name, ok := child.Attr("name"); ok {
+				f.Categories = append(f.Categories, name)
+			}
+		case "data":
+			if data := formatData(child); data != "" {
+				f.Data = append(f.Data, data)
+			}
+			addDataParts(&f, child)
+		}
+	}
+	return f
+}
+
+// formatData renders a <data> element as a URI-like pattern, e.g.
+// "https://example.com/path*" or "mime:image/*".
+func formatData(el *Element) string {
+	scheme, _ := el.Attr("scheme")
+	host, _ := el.Attr("host")
+	port, _ := el.Attr("port")
+	mime, _ := el.Attr("mimeType")
+
+	var b strings.Builder
+	if scheme != "" {
+		b.WriteString(scheme + ":")
+		if host != "" {
+			b.WriteString("//" + host)
+			if port != "" {
+				b.WriteString(":" + port)
+			}
+		}
+	} else if host != "" {
+		b.WriteString("//" + host)
+	}
+
+	if p, ok := el.Attr("path"); ok {
+		b.WriteString(p)
+	} else if p, ok := el.Attr("pathPrefix"); ok {
+		b.WriteString(p + "*")
+	} else if p, ok := el.Attr("pathPattern"); ok {
+		b.WriteString(p)
+	}
+
+	if mime != "" {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString("mime:" + mime)
+	}
+	return b.String()
+}
+
+func addDataParts(f *IntentFilter, el *Element) {
+	if scheme, ok := el.Attr("scheme"); ok {
+		f.Schemes = appendUnique(f.Schemes, scheme)
+	}
+	if host, ok := el.Attr("host"); ok {
+		if port, ok := el.Attr("port"); ok {
+			host += ":" + port
+		}
+		f.Hosts = appendUnique(f.Hosts, host)
+	}
+	if p, ok := el.Attr("path"); ok {
+		f.Paths = appendUnique(f.Paths, p)
+	}
+	if p, ok := el.Attr("pathPrefix"); ok {
+		f.Paths = appendUnique(f.Paths, p+"*")
+	}
+	if p, ok := el.Attr("pathSuffix"); ok {
+		f.Paths = appendUnique(f.Paths, "*"+p)
+	}
+	for _, attr := range []string{"pathPattern", "pathAdvancedPattern"} {
+		if p, ok := el.Attr(attr); ok {
+			f.Paths = appendUnique(f.Paths, p)
+		}
+	}
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// qualifyClass expands ".Foo" and "Foo" to "pkg.Foo".
+func qualifyClass(pkg, name string) string {
+	switch {
+	case strings.HasPrefix(name, "."):
+		return pkg + name
+	case !strings.Contains(name, "."):
+		return pkg + "." + name
+	}
+	return name
+}
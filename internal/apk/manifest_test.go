@@ -0,0 +1,226 @@
+package apk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func el(name string, attrs []Attr, children ...*Element) *Element {
+	return &Element{Name: name, Attrs: attrs, Children: children}
+}
+
+func attrs(pairs ...string) []Attr {
+	var out []Attr
+	for i := 0; i+1 < len(pairs); i += 2 {
+		out = append(out, Attr{Name: pairs[i], Value: pairs[i+1]})
+	}
+	return out
+}
+
+func intentFilter(filterAttrs []Attr, children ...*Element) *Element {
+	return el("intent-filter", filterAttrs, children...)
+}
+
+func action(name string) *Element   { return el("action", attrs("name", name)) }
+func category(name string) *Element { return el("category", attrs("name", name)) }
+
+func TestParseManifest(t *testing.T) {
+	root := el("manifest", attrs("package", "com.example.app", "versionCode", "42", "versionName", "1.4.2"),
+		el("uses-sdk", attrs("minSdkVersion", "24", "targetSdkVersion", "34")),
+		el("uses-permission", attrs("name", "android.permission.INTERNET")),
+		el("uses-permission-sdk-23", attrs("name", "android.permission.CAMERA")),
+		el("application", attrs("label", "@0x7f120001", "debuggable", "true"),
+			el("activity", attrs("name", ".MainActivity", "exported", "true"),
+				intentFilter(nil,
+					action("android.intent.action.MAIN"),
+					category("android.intent.category.LAUNCHER"),
+				),
+			),
+			el("activity", attrs("name", "SettingsActivity")),
+			el("activity-alias", attrs("name", ".Launcher", "targetActivity", ".MainActivity", "enabled", "false")),
+			el("service", attrs("name", "com.example.lib.SyncService", "permission", "android.permission.BIND_JOB_SERVICE")),
+			el("receiver", attrs("name", ".BootReceiver"),
+				intentFilter(nil, action("android.intent.action.BOOT_COMPLETED")),
+			),
+			el("provider", attrs("name", ".data.Provider", "authorities", "com.example.app.data;com.example.app.files")),
+			el("meta-data", attrs("name", "com.google.android.gms.version", "value", "@0x7f0b0001")),
+		),
+	)
+
+	want := Manifest{
+		Package:     "com.example.app",
+		VersionCode: "42",
+		VersionName: "1.4.2",
+		MinSDK:      "24",
+		TargetSDK:   "34",
+		Label:       "@0x7f120001",
+		Debuggable:  true,
+		Permissions: []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+		Components: []Component{
+			{
+				Type:     Activity,
+				Name:     "com.example.app.MainActivity",
+				Exported: true,
+				Enabled:  true,
+				Filters: []IntentFilter{{
+					Actions:    []string{"android.intent.action.MAIN"},
+					Categories: []string{"android.intent.category.LAUNCHER"},
+				}},
+			},
+			{Type: Activity, Name: "com.example.app.SettingsActivity", Enabled: true},
+			{Type: Activity, Name: "com.example.app.Launcher", TargetActivity: "com.example.app.MainActivity"},
+			{Type: Service, Name: "com.example.lib.SyncService", Enabled: true, Permission: "android.permission.BIND_JOB_SERVICE"},
+			{
+				Type:     Receiver,
+				Name:     "com.example.app.BootReceiver",
+				Exported: true,
+				Enabled:  true,
+				Filters:  []IntentFilter{{Actions: []string{"android.intent.action.BOOT_COMPLETED"}}},
+			},
+			{
+				Type:        Provider,
+				Name:        "com.example.app.data.Provider",
+				Enabled:     true,
+				Authorities: []string{"com.example.app.data", "com.example.app.files"},
+			},
+		},
+	}
+
+	got := parseManifest(root)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseManifest() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if launch := got.LaunchActivity(); launch != "com.example.app/.MainActivity" {
+		t.Errorf("LaunchActivity() = %q", launch)
+	}
+	if n := len(got.ComponentsOf(Activity)); n != 3 {
+		t.Errorf("ComponentsOf(Activity) has %d components, want 3", n)
+	}
+}
+
+func TestComponentExportedDefault(t *testing.T) {
+	withFilter := intentFilter(nil, action("com.example.PING"))
+
+	tests := []struct {
+		name      string
+		el        *Element
+		t         ComponentType
+		targetSDK int
+		want      bool
+	}{
+		{"explicit true", el("service", attrs("name", ".S", "exported", "true")), Service, 34, true},
+		{"explicit false despite filter", el("receiver", attrs("name", ".R", "exported", "false"), withFilter), Receiver, 34, false},
+		{"filter without attribute", el("receiver", attrs("name", ".R"), withFilter), Receiver, 30, true},
+		{"no filter", el("activity", attrs("name", ".A")), Activity, 34, false},
+		{"provider targeting 16", el("provider", attrs("name", ".P")), Provider, 16, true},
+		{"provider targeting 17", el("provider", attrs("name", ".P")), Provider, 17, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseComponent(tt.el, tt.t, "com.example.app", tt.targetSDK).Exported; got != tt.want {
+				t.Errorf("Exported = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIntentFilterData(t *testing.T) {
+	filter := intentFilter(attrs("autoVerify", "true"),
+		action("android.intent.action.VIEW"),
+		category("android.intent.category.DEFAULT"),
+		category("android.intent.category.BROWSABLE"),
+		el("data", attrs("scheme", "https")),
+		el("data", attrs("scheme", "http")),
+		el("data", attrs("host", "shop.example.com")),
+		el("data", attrs("host", "localhost", "port", "8080")),
+		el("data", attrs("pathPrefix", "/orders/")),
+		el("data", attrs("path", "/cart")),
+		el("data", attrs("mimeType", "image/*")),
+	)
+
+	got := parseIntentFilter(filter)
+	want := IntentFilter{
+		Actions:    []string{"android.intent.action.VIEW"},
+		Categories: []string{"android.intent.category.DEFAULT", "android.intent.category.BROWSABLE"},
+		Data:       []string{"https:", "http:", "//shop.example.com", "//localhost", "/orders/*", "/cart", "mime:image/*"},
+		AutoVerify: true,
+		Schemes:    []string{"https", "http"},
+		Hosts:      []string{"shop.example.com", "localhost:8080"},
+		Paths:      []string{"/orders/*", "/cart"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIntentFilter() =\n%+v\nwant\n%+v", got, want)
+	}
+	if !got.Has("android.intent.action.VIEW") || got.Has("android.intent.action.SEND") {
+		t.Errorf("Has() does not match Actions %v", got.Actions)
+	}
+
+	patterns := got.URLPatterns()
+	if len(patterns) != 8 || patterns[0] != "https://shop.example.com/orders/*" || patterns[7] != "http://localhost:8080/cart" {
+		t.Errorf("URLPatterns() = %v", patterns)
+	}
+}
+
+func TestFormatData(t *testing.T) {
+	tests := []struct {
+		attrs []Attr
+		want  string
+	}{
+		{attrs("scheme", "https", "host", "example.com", "pathPrefix", "/a"), "https://example.com/a*"},
+		{attrs("scheme", "http", "host", "localhost", "port", "8080", "path", "/x"), "http://localhost:8080/x"},
+		{attrs("scheme", "myapp"), "myapp:"},
+		{attrs("scheme", "content", "mimeType", "vnd.android.cursor.item/contact"), "content: mime:vnd.android.cursor.item/contact"},
+		{attrs("pathPattern", "/.*\\.pdf"), "/.*\\.pdf"},
+	}
+
+	for _, tt := range tests {
+		if got := formatData(el("data", tt.attrs)); got != tt.want {
+			t.Errorf("formatData(%v) = %q, want %q", tt.attrs, got, tt.want)
+		}
+	}
+}
+
+func TestComponentName(t *testing.T) {
+	m := Manifest{Package: "com.example.app"}
+	tests := map[string]string{
+		"com.example.app.MainActivity":     "com.example.app/.MainActivity",
+		"com.example.app.ui.Settings":      "com.example.app/.ui.Settings",
+		"com.example.lib.SyncService":      "com.example.app/com.example.lib.SyncService",
+		"com.example.application.Launcher": "com.example.app/com.example.application.Launcher",
+	}
+	for name, want := range tests {
+		if got := m.ComponentName(Component{Name: name}); got != want {
+			t.Errorf("ComponentName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMergeSplit(t *testing.T) {
+	m := Manifest{
+		Package:     "com.example.app",
+		VersionCode: "42",
+		Permissions: []string{"android.permission.INTERNET"},
+		Components:  []Component{{Type: Activity, Name: "com.example.app.MainActivity"}},
+	}
+	m.MergeSplit(Manifest{
+		Package:     "com.example.app",
+		Permissions: []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+		Components:  []Component{{Type: Activity, Name: "com.example.app.scan.ScanActivity", Exported: true}},
+	})
+	m.MergeSplit(Manifest{Package: "com.example.app"}) // a config split
+
+	want := Manifest{
+		Package:     "com.example.app",
+		VersionCode: "42",
+		Permissions: []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+		Components: []Component{
+			{Type: Activity, Name: "com.example.app.MainActivity"},
+			{Type: Activity, Name: "com.example.app.scan.ScanActivity", Exported: true},
+		},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("MergeSplit() = %+v, want %+v", m, want)
+	}
+}
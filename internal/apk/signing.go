@@ -0,0 +1,304 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Certificate is a signing certificate found in an APK.
+type Certificate struct {
+	Scheme  string // "v3", "v2" or "v1"
+	Subject string
+	SHA256  string
+	SHA1    string
+}
+
+const (
+	eocdSignature   = 0x06054b50
+	eocdMinSize     = 22
+	maxCommentSize  = 0xffff
+	sigBlockMagic   = "APK Sig Block 42"
+	sigSchemeV2ID   = 0x7109871a
+	sigSchemeV3ID   = 0xf05368c0
+	sigBlockMinSize = 32
+)
+
+var ErrNotSigned = errors.New("no signing certificates found")
+
+// SigningCertificates returns the certificates an APK was signed with,
+// preferring the v3 and v2 signing block over v1 JAR signatures.
+func SigningCertificates(apkPath string) ([]Certificate, error) {
+	file, err := os.Open(apkPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if certs, err := signingBlockCertificates(file, info.Size()); err == nil && len(certs) > 0 {
+		return certs, nil
+	}
+
+	return jarCertificates(file, info.Size())
+}
+
+func signingBlockCertificates(r io.ReaderAt, size int64) ([]Certificate, error) {
+	cdOffset, err := centralDirectoryOffset(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if cdOffset < sigBlockMinSize {
+		return nil, ErrNotSigned
+	}
+
+	// Block layout: size (u64), pairs..., size (u64), magic (16 bytes)
+	footer := make([]byte, 24)
+	if _, err := r.ReadAt(footer, cdOffset-24); err != nil {
+		return nil, err
+	}
+	if string(footer[8:]) != sigBlockMagic {
+		return nil, ErrNotSigned
+	}
+
+	blockSize := int64(binary.LittleEndian.Uint64(footer[:8]))
+	start := cdOffset - blockSize - 8
+	if blockSize < 24 || start < 0 {
+		return nil, fmt.Errorf("invalid signing block size")
+	}
+
+	block := make([]byte, blockSize-24)
+	if _, err := r.ReadAt(block, start+8); err != nil {
+		return nil, err
+	}
+
+	pairs := map[uint32][]byte{}
+	for len(block) >= 12 {
+		pairLen := binary.LittleEndian.Uint64(block[:8])
+		if pairLen < 4 || pairLen > uint64(len(block)-8) {
+			return nil, fmt.Errorf("invalid signing block entry")
+		}
+		id := binary.LittleEndian.Uint32(block[8:12])
+		pairs[id] = block[12 : 8+pairLen]
+		block = block[8+pairLen:]
+	}
+
+	for _, scheme := range []struct {
+		id   uint32
+		name string
+	}{{sigSchemeV3ID, "v3"}, {sigSchemeV2ID, "v2"}} {
+		value, ok := pairs[scheme.id]
+		if !ok {
+			continue
+		}
+		certs, err := schemeCertificates(value, scheme.name)
+		if err != nil {
+			return nil, err
+		}
+		if len(certs) > 0 {
+			return certs, nil
+		}
+	}
+
+	return nil, ErrNotSigned
+}
+
+// schemeCertificates walks a v2/v3 value: signers → signer → signed data →
+// certificates, all length-prefixed.
+func schemeCertificates(value []byte, scheme string) ([]Certificate, error) {
+	signers, _, err := lengthPrefixed(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []Certificate
+	for len(signers) > 0 {
+		var signer []byte
+		signer, signers, err = lengthPrefixed(signers)
+		if err != nil {
+			return nil, err
+		}
+
+		signedData, _, err := lengthPrefixed(signer)
+		if err != nil {
+			return nil, err
+		}
+
+		_, rest, err := lengthPrefixed(signedData) // digests
+		if err != nil {
+			return nil, err
+		}
+		encodedCerts, _, err := lengthPrefixed(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only the first certificate of a signer is the signing one; the
+		// rest form its chain.
+		if len(encodedCerts) > 0 {
+			der, _, err := lengthPrefixed(encodedCerts)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, newCertificate(der, scheme))
+		}
+	}
+
+	return certs, nil
+}
+
+func lengthPrefixed(data []byte) (value, rest []byte, err error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("truncated signing block")
+	}
+	n := binary.LittleEndian.Uint32(data[:4])
+	if uint64(n) > uint64(len(data)-4) {
+		return nil, nil, fmt.Errorf("truncated signing block")
+	}
+	return data[4 : 4+n], data[4+n:], nil
+}
+
+func centralDirectoryOffset(r io.ReaderAt, size int64) (int64, error) {
+	if size < eocdMinSize {
+		return 0, fmt.Errorf("not a zip file")
+	}
+
+	tailSize := int64(eocdMinSize + maxCommentSize)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail := make([]byte, tailSize)
+	if _, err := r.ReadAt(tail, size-tailSize); err != nil {
+		return 0, err
+	}
+
+	for i := len(tail) - eocdMinSize; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) == eocdSignature {
+			return int64(binary.LittleEndian.Uint32(tail[i+16:])), nil
+		}
+	}
+	return 0, fmt.Errorf("end of central directory not found")
+}
+
+// jarCertificates reads the PKCS#7 signature files of a v1 signed APK.
+func jarCertificates(r io.ReaderAt, size int64) ([]Certificate, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []Certificate
+	for _, f := range archive.File {
+		dir, name := path.Split(f.Name)
+		ext := strings.ToUpper(path.Ext(name))
+		if dir != "META-INF/" || (ext != ".RSA" && ext != ".DSA" && ext != ".EC") {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		der, err := pkcs7FirstCertificate(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		certs = append(certs, newCertificate(der, "v1"))
+	}
+
+	if len(certs) == 0 {
+		return nil, ErrNotSigned
+	}
+	return certs, nil
+}
+
+// pkcs7FirstCertificate extracts the first certificate from a PKCS#7
+// SignedData blob:
+//
+//	ContentInfo ::= SEQUENCE { contentType OID, content [0] EXPLICIT SignedData }
+//	SignedData  ::= SEQUENCE { version, digestAlgorithms, contentInfo,
+//	                           certificates [0] IMPLICIT SET OF Certificate OPTIONAL, ... }
+func pkcs7FirstCertificate(data []byte) ([]byte, error) {
+	var contentInfo struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"explicit,tag:0"`
+	}
+	if _, err := asn1.Unmarshal(data, &contentInfo); err != nil {
+		return nil, err
+	}
+
+	var signedData asn1.RawValue
+	if _, err := asn1.Unmarshal(contentInfo.Content.Bytes, &signedData); err != nil {
+		return nil, err
+	}
+
+	rest := signedData.Bytes
+	for len(rest) > 0 {
+		var field asn1.RawValue
+		var err error
+		rest, err = asn1.Unmarshal(rest, &field)
+		if err != nil {
+			return nil, err
+		}
+
+		if field.Class == asn1.ClassContextSpecific && field.Tag == 0 {
+			var cert asn1.RawValue
+			if _, err := asn1.Unmarshal(field.Bytes, &cert); err != nil {
+				return nil, err
+			}
+			return cert.FullBytes, nil
+		}
+	}
+
+	return nil, ErrNotSigned
+}
+
+func newCertificate(der []byte, scheme string) Certificate {
+	sum256 := sha256.Sum256(der)
+	sum1 := sha1.Sum(der)
+
+	c := Certificate{
+		Scheme: scheme,
+		SHA256: formatDigest(sum256[:]),
+		SHA1:   formatDigest(sum1[:]),
+	}
+	if parsed, err := x509.ParseCertificate(der); err == nil {
+		c.Subject = parsed.Subject.String()
+	}
+	return c
+}
+
+// formatDigest renders a digest as colon separated upper-case hex, the
+// form printed by apksigner and keytool.
+func formatDigest(sum []byte) string {
+	encoded := strings.ToUpper(hex.EncodeToString(sum))
+	var b bytes.Buffer
+	for i := 0; i < len(encoded); i += 2 {
+		if i > 0 {
+			b.WriteByte(':')
+		}
+		b.WriteString(encoded[i : i+2])
+	}
+	return b.String()
+}
@@ -0,0 +1,232 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCertificate returns a self-signed DER certificate for cn.
+func testCertificate(t *testing.T, cn string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"Example"}},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func prefixed(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(p)))
+		out = append(out, p...)
+	}
+	return out
+}
+
+// schemeValue builds a v2/v3 signature scheme value with one signer per
+// certificate chain.
+func schemeValue(chains ...[][]byte) []byte {
+	var signers []byte
+	for _, chain := range chains {
+		signedData := append(prefixed([]byte{}), prefixed(prefixed(chain...))...)
+		signer := append(prefixed(signedData), prefixed([]byte("signatures"), []byte("public key"))...)
+		signers = append(signers, prefixed(signer)...)
+	}
+	return prefixed(signers)
+}
+
+type blockPair struct {
+	id    uint32
+	value []byte
+}
+
+func signingBlock(pairs ...blockPair) []byte {
+	var body []byte
+	for _, p := range pairs {
+		body = binary.LittleEndian.AppendUint64(body, uint64(4+len(p.value)))
+		body = binary.LittleEndian.AppendUint32(body, p.id)
+		body = append(body, p.value...)
+	}
+	size := uint64(len(body) + 24)
+	block := binary.LittleEndian.AppendUint64(nil, size)
+	block = append(block, body...)
+	block = binary.LittleEndian.AppendUint64(block, size)
+	return append(block, sigBlockMagic...)
+}
+
+// pkcs7 wraps a certificate in a minimal PKCS#7 SignedData, as found in
+// META-INF/CERT.RSA.
+func pkcs7(t *testing.T, der []byte) []byte {
+	t.Helper()
+	signedData, err := asn1.Marshal(struct {
+		Version          int
+		DigestAlgorithms asn1.RawValue
+		ContentInfo      struct{ ContentType asn1.ObjectIdentifier }
+		Certificates     asn1.RawValue
+	}{
+		Version:          1,
+		DigestAlgorithms: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true},
+		ContentInfo:      struct{ ContentType asn1.ObjectIdentifier }{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contentInfo, err := asn1.Marshal(struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}{
+		ContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2},
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contentInfo
+}
+
+type zipEntry struct {
+	name string
+	data []byte
+}
+
+// writeAPK zips files into a temporary APK and inserts block, if any,
+// before the central directory the way apksigner does.
+func writeAPK(t *testing.T, files []zipEntry, block []byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := w.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if block != nil {
+		eocd := len(data) - eocdMinSize
+		cdOffset := binary.LittleEndian.Uint32(data[eocd+16:])
+		signed := append([]byte{}, data[:cdOffset]...)
+		signed = append(signed, block...)
+		signed = append(signed, data[cdOffset:]...)
+		binary.LittleEndian.PutUint32(signed[len(signed)-eocdMinSize+16:], cdOffset+uint32(len(block)))
+		data = signed
+	}
+
+	path := filepath.Join(t.TempDir(), "app.apk")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSigningCertificates(t *testing.T) {
+	release := testCertificate(t, "Release")
+	rotated := testCertificate(t, "Rotated")
+	intermediate := testCertificate(t, "Intermediate")
+	manifest := zipEntry{"AndroidManifest.xml", encodeAXML(testManifest(), false)}
+
+	tests := []struct {
+		name  string
+		files []zipEntry
+		block []byte
+		want  []Certificate
+		err   error
+	}{
+		{
+			name:  "v2 signer with chain",
+			files: []zipEntry{manifest},
+			block: signingBlock(blockPair{sigSchemeV2ID, schemeValue([][]byte{release, intermediate})}),
+			want:  []Certificate{newCertificate(release, "v2")},
+		},
+		{
+			name:  "v3 preferred over v2",
+			files: []zipEntry{manifest},
+			block: signingBlock(
+				blockPair{0x42726577, []byte("padding")},
+				blockPair{sigSchemeV2ID, schemeValue([][]byte{release})},
+				blockPair{sigSchemeV3ID, schemeValue([][]byte{rotated})},
+			),
+			want: []Certificate{newCertificate(rotated, "v3")},
+		},
+		{
+			name:  "v1 JAR signature",
+			files: []zipEntry{manifest, {"META-INF/MANIFEST.MF", []byte("Manifest-Version: 1.0\n")}, {"META-INF/CERT.RSA", pkcs7(t, release)}},
+			want:  []Certificate{newCertificate(release, "v1")},
+		},
+		{
+			name:  "unsigned",
+			files: []zipEntry{manifest},
+			err:   ErrNotSigned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SigningCertificates(writeAPK(t, tt.files, tt.block))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SigningCertificates() error = %v, want %v", err, tt.err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("SigningCertificates() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("certificate %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewCertificate(t *testing.T) {
+	der := testCertificate(t, "Release")
+	c := newCertificate(der, "v2")
+
+	if c.Subject != "CN=Release,O=Example" {
+		t.Errorf("Subject = %q", c.Subject)
+	}
+	sum := sha256.Sum256(der)
+	if c.SHA256 != formatDigest(sum[:]) || len(c.SHA256) != 32*3-1 || len(c.SHA1) != 20*3-1 {
+		t.Errorf("digests = %q, %q", c.SHA256, c.SHA1)
+	}
+}
+
+func TestFormatDigest(t *testing.T) {
+	if got := formatDigest([]byte{0xab, 0x01, 0xff}); got != "AB:01:FF" {
+		t.Errorf("formatDigest() = %q, want AB:01:FF", got)
+	}
+}
@@ -0,0 +1,129 @@
+package bench
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+)
+
+// Run is one measured launch, times in milliseconds.
+type Run struct {
+	TotalTime   int    `json:"total_time_ms"`
+	WaitTime    int    `json:"wait_time_ms"`
+	ThisTime    int    `json:"this_time_ms,omitempty"`
+	LaunchState string `json:"launch_state,omitempty"`
+}
+
+// Session is a completed series of launches in a single mode.
+type Session struct {
+	Time        time.Time `json:"time"`
+	Serial      string    `json:"serial"`
+	Package     string    `json:"package"`
+	VersionName string    `json:"version_name,omitempty"`
+	VersionCode string    `json:"version_code,omitempty"`
+	Mode        string    `json:"mode"`
+	Runs        []Run     `json:"runs"`
+}
+
+type Stats struct {
+	N      int
+	Mean   float64
+	Median float64
+	StdDev float64
+}
+
+func (s Session) TotalTimeStats() Stats {
+	return compute(s.Runs, func(r Run) int { return r.TotalTime })
+}
+
+func (s Session) WaitTimeStats() Stats {
+	return compute(s.Runs, func(r Run) int { return r.WaitTime })
+}
+
+func (s Session) ThisTimeStats() Stats {
+	return compute(s.Runs, func(r Run) int { return r.ThisTime })
+}
+
+func compute(runs []Run, value func(Run) int) Stats {
+	values := make([]float64, 0, len(runs))
+	for _, r := range runs {
+		if v := value(r); v > 0 {
+			values = append(values, float64(v))
+		}
+	}
+
+	st := Stats{N: len(values)}
+	if st.N == 0 {
+		return st
+	}
+
+	sort.Float64s(values)
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	st.Mean = sum / float64(st.N)
+
+	if st.N%2 == 1 {
+		st.Median = values[st.N/2]
+	} else {
+		st.Median = (values[st.N/2-1] + values[st.N/2]) / 2
+	}
+
+	// Sample standard deviation; a single run has none.
+	if st.N > 1 {
+		var sq float64
+		for _, v := range values {
+			sq += (v - st.Mean) * (v - st.Mean)
+		}
+		st.StdDev = math.Sqrt(sq / float64(st.N-1))
+	}
+
+	return st
+}
+
+func historyFile(pkg string) string {
+	return filepath.Join("benchmarks", pkg+".json")
+}
+
+// History returns every saved session for pkg, oldest first.
+func History(pkg string) ([]Session, error) {
+	var sessions []Session
+	if err := config.LoadJSON(historyFile(pkg), &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Save appends session to the package's benchmark history.
+func Save(session Session) error {
+	sessions, err := History(session.Package)
+	if err != nil {
+		return err
+	}
+	return config.SaveJSON(historyFile(session.Package), append(sessions, session))
+}
+
+// Baseline picks the session to compare against: the latest one in the
+// same mode from a different build, or failing that the latest one in the
+// same mode.
+func Baseline(history []Session, current Session) *Session {
+	var sameMode *Session
+	for i := len(history) - 1; i >= 0; i-- {
+		s := history[i]
+		if s.Mode != current.Mode || !s.Time.Before(current.Time) {
+			continue
+		}
+		if s.VersionCode != current.VersionCode {
+			return &history[i]
+		}
+		if sameMode == nil {
+			sameMode = &history[i]
+		}
+	}
+	return sameMode
+}
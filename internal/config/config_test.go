@@ -0,0 +1,19 @@
+package config
+
+import "testing"
+
+func TestSafeFileName(t *testing.T) {
+	tests := map[string]string{
+		"com.example.app":       "com.example.app",
+		"emulator-5554":         "emulator-5554",
+		"192.168.1.20:5555":     "192.168.1.20_5555",
+		"adb-R58N12/_adb._tcp":  "adb-R58N12__adb._tcp",
+		`Pixel 8 "Pro" <test>?`: "Pixel_8__Pro___test__",
+		"Café":                  "Caf_",
+	}
+	for name, want := range tests {
+		if got := SafeFileName(name); got != want {
+			t.Errorf("SafeFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
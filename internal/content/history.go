@@ -0,0 +1,29 @@
+// Package content keeps the Content screen's history of provider URIs.
+package content
+
+import "github.com/SakshhamTheCoder/adbt/internal/config"
+
+const historyFile = "content_uris.json"
+
+// HistoryLimit caps how many URIs are kept.
+const HistoryLimit = 50
+
+// History returns the recently used URIs, newest first.
+func History() ([]string, error) {
+	var uris []string
+	if err := config.LoadJSON(historyFile, &uris); err != nil {
+		return nil, err
+	}
+	return uris, nil
+}
+
+// Remember moves uri to the front of the history and saves it.
+func Remember(uris []string, uri string) ([]string, error) {
+	updated := []string{uri}
+	for _, u := range uris {
+		if u != uri && len(updated) < HistoryLimit {
+			updated = append(updated, u)
+		}
+	}
+	return updated, config.SaveJSON(historyFile, updated)
+}
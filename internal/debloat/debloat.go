@@ -0,0 +1,146 @@
+package debloat
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+)
+
+const profilesFile = "debloat_profiles.json"
+
+// Action is a reversible package state change.
+type Action string
+
+const (
+	Disable   Action = "disable"
+	Enable    Action = "enable"
+	Uninstall Action = "uninstall"
+	Restore   Action = "restore"
+)
+
+// Inverse returns the action that undoes a.
+func (a Action) Inverse() Action {
+	switch a {
+	case Disable:
+		return Enable
+	case Enable:
+		return Disable
+	case Uninstall:
+		return Restore
+	case Restore:
+		return Uninstall
+	}
+	return ""
+}
+
+// Profile is a named list of packages to disable or uninstall for the
+// current user.
+type Profile struct {
+	Name     string   `json:"name"`
+	Action   Action   `json:"action"`
+	Packages []string `json:"packages"`
+}
+
+func (p Profile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if p.Action != Disable && p.Action != Uninstall {
+		return fmt.Errorf("action must be %s or %s", Disable, Uninstall)
+	}
+	if len(p.Packages) == 0 {
+		return fmt.Errorf("at least one package is required")
+	}
+	return nil
+}
+
+func LoadProfiles() ([]Profile, error) {
+	var profiles []Profile
+	if err := config.LoadJSON(profilesFile, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func SaveProfiles(profiles []Profile) error {
+	return config.SaveJSON(profilesFile, profiles)
+}
+
+// Entry is one change in a device's journal.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Serial  string    `json:"serial"`
+	User    int       `json:"user"`
+	Package string    `json:"package"`
+	Action  Action    `json:"action"`
+	Profile string    `json:"profile,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func journalFile(serial string) string {
+	return filepath.Join("debloat", "journal-"+config.SafeFileName(serial)+".jsonl")
+}
+
+// Record appends a change to the device's journal.
+func Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	return config.AppendJSONLine(journalFile(entry.Serial), entry)
+}
+
+// Journal returns every recorded change for serial, oldest first.
+func Journal(serial string) ([]Entry, error) {
+	path, err := config.Path(journalFile(serial))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Rollback returns the actions that undo a profile for one user of a
+// device: the inverse of the last successful change the profile made to
+// each package. Packages changed again since then are left alone.
+func Rollback(journal []Entry, profile string, user int) map[string]Action {
+	last := map[string]Entry{}
+	for _, e := range journal {
+		if e.Error == "" && e.User == user {
+			last[e.Package] = e
+		}
+	}
+
+	undo := map[string]Action{}
+	for pkg, e := range last {
+		if e.Profile == profile && (e.Action == Disable || e.Action == Uninstall) {
+			undo[pkg] = e.Action.Inverse()
+		}
+	}
+	return undo
+}
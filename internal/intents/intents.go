@@ -0,0 +1,345 @@
+// Package intents keeps the Intents screen's history of sent intents and
+// its library of named favorites, which can be shared as JSON or YAML.
+package intents
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	favoritesFile = "intent_favorites.json"
+	historyFile   = "intent_history.jsonl"
+	// HistoryLimit caps how many of the latest history entries are loaded.
+	HistoryLimit = 500
+)
+
+// Spec is an intent as typed into the Intents form, so that placeholders
+// can stand in for any part of it. Extras and flags use the syntax of
+// adb.ParseExtras and adb.ParseIntentFlags.
+type Spec struct {
+	Mode       string `json:"mode" yaml:"mode"`
+	Action     string `json:"action,omitempty" yaml:"action,omitempty"`
+	Data       string `json:"data,omitempty" yaml:"data,omitempty"`
+	MimeType   string `json:"mime_type,omitempty" yaml:"mime_type,omitempty"`
+	Component  string `json:"component,omitempty" yaml:"component,omitempty"`
+	Package    string `json:"package,omitempty" yaml:"package,omitempty"`
+	Categories string `json:"categories,omitempty" yaml:"categories,omitempty"`
+	Flags      string `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Extras     string `json:"extras,omitempty" yaml:"extras,omitempty"`
+	User       string `json:"user,omitempty" yaml:"user,omitempty"`
+}
+
+// Values returns the spec in the order of the Intents form fields.
+func (s Spec) Values() []string {
+	return []string{s.Action, s.Data, s.MimeType, s.Component, s.Package, s.Categories, s.Flags, s.Extras, s.User}
+}
+
+// SpecFromValues is the inverse of Values.
+func SpecFromValues(mode string, values []string) Spec {
+	value := func(i int) string {
+		if i < len(values) {
+			return strings.TrimSpace(values[i])
+		}
+		return ""
+	}
+	return Spec{
+		Mode:       mode,
+		Action:     value(0),
+		Data:       value(1),
+		MimeType:   value(2),
+		Component:  value(3),
+		Package:    value(4),
+		Categories: value(5),
+		Flags:      value(6),
+		Extras:     value(7),
+		User:       value(8),
+	}
+}
+
+// Summary is a one-line description for lists.
+func (s Spec) Summary() string {
+	var parts []string
+	for _, p := range []string{s.Action, s.Component, s.Data, s.Package} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if s.Extras != "" {
+		parts = append(parts, "extras: "+s.Extras)
+	}
+	return strings.Join(parts, "  ")
+}
+
+var placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Placeholders lists the distinct {{name}} placeholders of the spec in
+// order of appearance.
+func (s Spec) Placeholders() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, v := range s.Values() {
+		for _, m := range placeholderRe.FindAllStringSubmatch(v, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
+
+// Fill replaces placeholders with their values. Placeholders without a
+// value are left in place.
+func (s Spec) Fill(values map[string]string) Spec {
+	fill := func(v string) string {
+		return placeholderRe.ReplaceAllStringFunc(v, func(m string) string {
+			name := placeholderRe.FindStringSubmatch(m)[1]
+			if value, ok := values[name]; ok {
+				return value
+			}
+			return m
+		})
+	}
+	vals := s.Values()
+	for i := range vals {
+		vals[i] = fill(vals[i])
+	}
+	return SpecFromValues(s.Mode, vals)
+}
+
+// Favorite is a named intent. Favorites with a Project only show up when
+// adbt runs in that project; the others show up everywhere.
+type Favorite struct {
+	Name    string `json:"name" yaml:"name"`
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+	Spec    `yaml:",inline"`
+}
+
+func (f Favorite) Validate() error {
+	if strings.TrimSpace(f.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if f.Action == "" && f.Data == "" && f.Component == "" && f.Package == "" {
+		return fmt.Errorf("favorite %q has no action, data URI, component or package", f.Name)
+	}
+	return nil
+}
+
+func LoadFavorites() ([]Favorite, error) {
+	var favorites []Favorite
+	if err := config.LoadJSON(favoritesFile, &favorites); err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+func SaveFavorites(favorites []Favorite) error {
+	return config.SaveJSON(favoritesFile, favorites)
+}
+
+// ForProject returns the favorites of project and the global ones.
+func ForProject(favorites []Favorite, project string) []Favorite {
+	var out []Favorite
+	for _, f := range favorites {
+		if f.Project == "" || f.Project == project {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Merge adds favorites to existing, replacing those with the same name
+// and project. It returns how many were added and replaced.
+func Merge(existing, favorites []Favorite) ([]Favorite, int, int) {
+	added, replaced := 0, 0
+	for _, f := range favorites {
+		found := false
+		for i := range existing {
+			if existing[i].Name == f.Name && existing[i].Project == f.Project {
+				existing[i] = f
+				found = true
+				replaced++
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, f)
+			added++
+		}
+	}
+	return existing, added, replaced
+}
+
+// Entry is one sent intent in the history.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Serial  string    `json:"serial"`
+	Spec    Spec      `json:"spec"`
+	Command string    `json:"command"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Record appends a sent intent to the history.
+func Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	return config.AppendJSONLine(historyFile, entry)
+}
+
+// History returns the latest HistoryLimit entries, newest first.
+func History() ([]Entry, error) {
+	path, err := config.Path(historyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) > HistoryLimit {
+		entries = entries[len(entries)-HistoryLimit:]
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, scanner.Err()
+}
+
+// Export writes favorites to path as YAML when it ends in .yaml or .yml,
+// and as JSON otherwise.
+func Export(path string, favorites []Favorite) error {
+	var data []byte
+	var err error
+	if isYAML(path) {
+		data, err = yaml.Marshal(favorites)
+	} else {
+		data, err = json.MarshalIndent(favorites, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Import reads favorites written by Export, or by hand in the same
+// format, and validates them.
+func Import(path string) ([]Favorite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var favorites []Favorite
+	if isYAML(path) {
+		favorites, err = decodeYAML(data)
+	} else {
+		favorites, err = decodeJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	for _, f := range favorites {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return favorites, nil
+}
+
+// decodeJSON rejects unknown keys like decodeYAML, and data after the list.
+func decodeJSON(data []byte) ([]Favorite, error) {
+	var favorites []Favorite
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&favorites); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the favorites")
+	}
+	return favorites, nil
+}
+
+// decodeYAML rejects unknown keys, so a typo does not silently drop a
+// field. An empty file holds no favorites.
+func decodeYAML(data []byte) ([]Favorite, error) {
+	var favorites []Favorite
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&favorites); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Match reports whether the letters of query appear in text in order,
+// ignoring case, and scores the match: higher for runs of consecutive
+// letters and matches at word starts.
+func Match(query, text string) (int, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return 0, true
+	}
+	lower := strings.ToLower(text)
+
+	score := 0
+	qi := 0
+	prev := -2
+	for i := 0; i < len(lower) && qi < len(query); i++ {
+		if lower[i] != query[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 3
+		}
+		if i == 0 || strings.ContainsRune(" ./_-:", rune(lower[i-1])) {
+			score += 2
+		}
+		prev = i
+		qi++
+	}
+	return score, qi == len(query)
+}
@@ -0,0 +1,209 @@
+package intents
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testFavorites = []Favorite{
+	{
+		Name: "Open deep link",
+		Spec: Spec{
+			Mode:   "start",
+			Action: "android.intent.action.VIEW",
+			Data:   "myapp://orders/{{order}}",
+			Flags:  "--activity-clear-top",
+			Extras: `note=a\;b; i:count=3; z:debug=true`,
+			User:   "10",
+		},
+	},
+	{
+		Name:    "Sync: nightly # job",
+		Project: "/home/dev/app",
+		Spec: Spec{
+			Mode:      "broadcast",
+			Component: "com.example.app/.SyncReceiver",
+			Extras:    "token=line one\nline two",
+		},
+	},
+}
+
+func TestExportImport(t *testing.T) {
+	for _, name := range []string{"favorites.json", "favorites.yaml", "favorites.YML"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "share", name)
+			if err := Export(path, testFavorites); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Import(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, testFavorites) {
+				t.Errorf("Import(Export()) = %+v, want %+v", got, testFavorites)
+			}
+		})
+	}
+}
+
+func TestImportInvalid(t *testing.T) {
+	tests := []struct {
+		name, file, data, wantErr string
+	}{
+		{"unknown yaml key", "f.yaml", "- name: X\n  mode: start\n  acton: android.intent.action.VIEW\n", "acton"},
+		{"unknown json key", "f.json", `[{"name":"X","mode":"start","acton":"android.intent.action.VIEW"}]`, "acton"},
+		{"no target", "f.yaml", "- name: X\n  mode: start\n  extras: a=b\n", "has no action"},
+		{"no name", "f.json", `[{"mode":"start","action":"android.intent.action.MAIN"}]`, "name is required"},
+		{"bad json", "f.json", `{"name":"X"}`, "f.json"},
+		{"trailing json", "f.json", `[{"name":"X","mode":"start","action":"a"}] []`, "unexpected data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Import(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Import() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Favorite
+	}{
+		{name: "empty", data: ""},
+		{name: "comments only", data: "# shared favorites\n"},
+		{
+			name: "hand written",
+			data: `# shared with the team
+- name: Settings
+  mode: start
+  action: android.settings.SETTINGS
+  user: 10
+- name: "Boot: completed"
+  mode: broadcast
+  action: android.intent.action.BOOT_COMPLETED # resend
+  extras: |-
+    a=1;
+    b=2
+`,
+			want: []Favorite{
+				{Name: "Settings", Spec: Spec{Mode: "start", Action: "android.settings.SETTINGS", User: "10"}},
+				{Name: "Boot: completed", Spec: Spec{Mode: "broadcast", Action: "android.intent.action.BOOT_COMPLETED", Extras: "a=1;\nb=2"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeYAML([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeYAML() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	existing := []Favorite{
+		{Name: "A", Spec: Spec{Action: "a"}},
+		{Name: "A", Project: "/p", Spec: Spec{Action: "a-project"}},
+	}
+	imported := []Favorite{
+		{Name: "A", Project: "/p", Spec: Spec{Action: "a-new"}},
+		{Name: "B", Spec: Spec{Action: "b"}},
+	}
+
+	got, added, replaced := Merge(existing, imported)
+	want := []Favorite{
+		{Name: "A", Spec: Spec{Action: "a"}},
+		{Name: "A", Project: "/p", Spec: Spec{Action: "a-new"}},
+		{Name: "B", Spec: Spec{Action: "b"}},
+	}
+	if !reflect.DeepEqual(got, want) || added != 1 || replaced != 1 {
+		t.Errorf("Merge() = %+v, %d, %d; want %+v, 1, 1", got, added, replaced, want)
+	}
+}
+
+func TestForProject(t *testing.T) {
+	favorites := []Favorite{{Name: "global"}, {Name: "mine", Project: "/p"}, {Name: "other", Project: "/q"}}
+	got := ForProject(favorites, "/p")
+	if len(got) != 2 || got[0].Name != "global" || got[1].Name != "mine" {
+		t.Errorf("ForProject() = %+v", got)
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	s := Spec{
+		Data:      "myapp://users/{{ user_id }}/orders/{{order}}",
+		Component: "{{pkg}}/.Main",
+		Extras:    "id={{order}}; name={{}}",
+		User:      "{{user.id}}",
+	}
+	if got, want := s.Placeholders(), []string{"user_id", "order", "pkg", "user.id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Placeholders() = %q, want %q", got, want)
+	}
+
+	filled := s.Fill(map[string]string{"user_id": "7", "order": "A1", "user.id": "10"})
+	want := Spec{
+		Data:      "myapp://users/7/orders/A1",
+		Component: "{{pkg}}/.Main",
+		Extras:    "id=A1; name={{}}",
+		User:      "10",
+	}
+	if filled != want {
+		t.Errorf("Fill() = %+v, want %+v", filled, want)
+	}
+}
+
+func TestSpecFromValues(t *testing.T) {
+	s := Spec{Mode: "start", Action: "a", Data: "d", MimeType: "m", Component: "c", Package: "p", Categories: "cat", Flags: "f", Extras: "e", User: "u"}
+	if got := SpecFromValues("start", s.Values()); got != s {
+		t.Errorf("SpecFromValues(Values()) = %+v, want %+v", got, s)
+	}
+	if got := SpecFromValues("broadcast", []string{" a "}); got != (Spec{Mode: "broadcast", Action: "a"}) {
+		t.Errorf("SpecFromValues() with short values = %+v", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		ok          bool
+	}{
+		{"", "anything", true},
+		{"odl", "Open deep link", true},
+		{"OPEN", "Open deep link", true},
+		{"lo", "Open deep link", false},
+		{"xyz", "Open deep link", false},
+	}
+	for _, tt := range tests {
+		if _, ok := Match(tt.query, tt.text); ok != tt.ok {
+			t.Errorf("Match(%q, %q) ok = %v, want %v", tt.query, tt.text, ok, tt.ok)
+		}
+	}
+
+	wordStarts, _ := Match("odl", "Open deep link")
+	scattered, _ := Match("odl", "cold lake")
+	if wordStarts <= scattered {
+		t.Errorf("word start score %d not above scattered score %d", wordStarts, scattered)
+	}
+	run, _ := Match("deep", "Open deep link")
+	split, _ := Match("deep", "dark eel epic pond")
+	if run <= split {
+		t.Errorf("consecutive score %d not above split score %d", run, split)
+	}
+}
@@ -0,0 +1,221 @@
+package perf
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/config"
+)
+
+type Format int
+
+const (
+	FormatCSV Format = iota
+	FormatJSONLines
+)
+
+var csvHeader = []string{
+	"timestamp",
+	"cpu_percent",
+	"mem_used_kb",
+	"mem_available_kb",
+	"mem_total_kb",
+	"mem_percent",
+	"net_rx_bytes_per_s",
+	"net_tx_bytes_per_s",
+}
+
+// FormatForPath picks the recording format from the file extension.
+// Anything that is not .json/.jsonl is written as CSV.
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".ndjson":
+		return FormatJSONLines
+	default:
+		return FormatCSV
+	}
+}
+
+// Recorder appends samples to a CSV or JSON lines file. Every sample is
+// flushed immediately so an interrupted endurance run keeps its data.
+type Recorder struct {
+	Path    string
+	Samples []Sample
+
+	file   *os.File
+	format Format
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		Path:   path,
+		file:   file,
+		format: FormatForPath(path),
+	}
+
+	if r.format == FormatJSONLines {
+		r.json = json.NewEncoder(file)
+		return r, nil
+	}
+
+	r.csv = csv.NewWriter(file)
+	if err := r.csv.Write(csvHeader); err != nil {
+		file.Close()
+		return nil, err
+	}
+	r.csv.Flush()
+	return r, r.csv.Error()
+}
+
+func (r *Recorder) Write(s Sample) error {
+	r.Samples = append(r.Samples, s)
+
+	if r.format == FormatJSONLines {
+		return r.json.Encode(s)
+	}
+
+	if err := r.csv.Write([]string{
+		s.Time.Format(time.RFC3339Nano),
+		strconv.FormatFloat(s.CPUPercent, 'f', 2, 64),
+		strconv.FormatUint(s.MemUsedKB, 10),
+		strconv.FormatUint(s.MemAvailableKB, 10),
+		strconv.FormatUint(s.MemTotalKB, 10),
+		strconv.FormatFloat(s.MemPercent, 'f', 2, 64),
+		strconv.FormatUint(s.NetRxRate, 10),
+		strconv.FormatUint(s.NetTxRate, 10),
+	}); err != nil {
+		return err
+	}
+	r.csv.Flush()
+	return r.csv.Error()
+}
+
+// Close finishes the recording and writes the summary report next to it
+// as <path>.summary.txt.
+func (r *Recorder) Close() (Summary, error) {
+	summary := Summarize(r.Samples)
+
+	if err := r.file.Close(); err != nil {
+		return summary, err
+	}
+
+	err := os.WriteFile(r.Path+".summary.txt", []byte(summary.Report()), 0o644)
+	return summary, err
+}
+
+// Load reads a recording written by Recorder.
+func Load(path string) ([]Sample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if FormatForPath(path) == FormatJSONLines {
+		return loadJSONLines(file)
+	}
+	return loadCSV(file)
+}
+
+func loadJSONLines(file *os.File) ([]Sample, error) {
+	var samples []Sample
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var s Sample
+		if err := json.Unmarshal([]byte(text), &s); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		samples = append(samples, s)
+	}
+
+	return samples, scanner.Err()
+}
+
+func loadCSV(file *os.File) ([]Sample, error) {
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, name := range csvHeader {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	samples := make([]Sample, 0, len(records)-1)
+	for i, record := range records[1:] {
+		field := func(name string) string {
+			return record[columns[name]]
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, field("timestamp"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+2, err)
+		}
+
+		s := Sample{Time: t}
+		s.CPUPercent, _ = strconv.ParseFloat(field("cpu_percent"), 64)
+		s.MemUsedKB, _ = strconv.ParseUint(field("mem_used_kb"), 10, 64)
+		s.MemAvailableKB, _ = strconv.ParseUint(field("mem_available_kb"), 10, 64)
+		s.MemTotalKB, _ = strconv.ParseUint(field("mem_total_kb"), 10, 64)
+		s.MemPercent, _ = strconv.ParseFloat(field("mem_percent"), 64)
+		s.NetRxRate, _ = strconv.ParseUint(field("net_rx_bytes_per_s"), 10, 64)
+		s.NetTxRate, _ = strconv.ParseUint(field("net_tx_bytes_per_s"), 10, 64)
+
+		samples = append(samples, s)
+	}
+
+	return samples, nil
+}
+
+// DefaultRecordingPath returns ~/Downloads/adbt-perf-<serial>-<time>.csv,
+// matching where the Files screen pulls to.
+func DefaultRecordingPath(serial string, now time.Time) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+
+	name := fmt.Sprintf(
+		"adbt-perf-%s-%s.csv",
+		config.SafeFileName(serial),
+		now.Format("20060102-150405"),
+	)
+	return filepath.Join(home, "Downloads", name)
+}
@@ -0,0 +1,66 @@
+package perf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordingRoundTrip(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: start, CPUPercent: 12.5, MemUsedKB: 6000, MemAvailableKB: 2000, MemTotalKB: 8000, MemPercent: 75, NetRxRate: 2048, NetTxRate: 512},
+		{Time: start.Add(time.Second), CPUPercent: 40.25, MemUsedKB: 6100, MemAvailableKB: 1900, MemTotalKB: 8000, MemPercent: 76.25, NetRxRate: 0, NetTxRate: 64},
+	}
+
+	for _, name := range []string{"perf.csv", "perf.jsonl"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			r, err := NewRecorder(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range samples {
+				if err := r.Write(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			summary, err := r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary.Samples != len(samples) {
+				t.Errorf("summary has %d samples, want %d", summary.Samples, len(samples))
+			}
+
+			got, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, samples) {
+				t.Errorf("Load() = %+v, want %+v", got, samples)
+			}
+
+			report, err := os.ReadFile(path + ".summary.txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(report), "Samples:  2") {
+				t.Errorf("summary report = %q", report)
+			}
+		})
+	}
+}
+
+func TestLoadCSVMissingColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf.csv")
+	if err := os.WriteFile(path, []byte("timestamp,cpu_percent\n2024-05-01T12:00:00Z,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "mem_used_kb") {
+		t.Errorf("Load() error = %v, want missing mem_used_kb", err)
+	}
+}
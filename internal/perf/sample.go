@@ -0,0 +1,68 @@
+package perf
+
+import (
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+)
+
+// Sample is a single derived performance reading, as shown on the
+// Performance screen and written to recordings.
+type Sample struct {
+	Time time.Time `json:"time"`
+
+	CPUPercent float64 `json:"cpu_percent"`
+
+	MemUsedKB      uint64  `json:"mem_used_kb"`
+	MemAvailableKB uint64  `json:"mem_available_kb"`
+	MemTotalKB     uint64  `json:"mem_total_kb"`
+	MemPercent     float64 `json:"mem_percent"`
+
+	NetRxRate uint64 `json:"net_rx_bytes_per_s"`
+	NetTxRate uint64 `json:"net_tx_bytes_per_s"`
+}
+
+// Derive turns two consecutive raw counter snapshots into a Sample. Rates
+// are normalised by the real time between the snapshots rather than the
+// nominal tick interval.
+//
+// Values are only derived from sources read in both snapshots. ok is false
+// when any source was missing, in which case the sample is fit for a live
+// display but must not be recorded or summarised: a delta against a
+// zeroed counter would be a huge bogus rate.
+func Derive(prev, cur adb.SystemStats) (s Sample, ok bool) {
+	s = Sample{Time: cur.SampledAt}
+
+	if cur.HasMem {
+		s.MemUsedKB = cur.MemUsed
+		s.MemAvailableKB = cur.MemAvailable
+		s.MemTotalKB = cur.MemTotal
+		s.MemPercent = float64(cur.MemUsed) / float64(cur.MemTotal) * 100
+	}
+
+	if prev.HasCPU && cur.HasCPU && cur.CPUTotal > prev.CPUTotal && cur.CPUIdle >= prev.CPUIdle {
+		deltaTotal := cur.CPUTotal - prev.CPUTotal
+		deltaIdle := cur.CPUIdle - prev.CPUIdle
+		if deltaIdle <= deltaTotal {
+			s.CPUPercent = float64(deltaTotal-deltaIdle) / float64(deltaTotal) * 100
+		}
+	}
+
+	elapsed := cur.SampledAt.Sub(prev.SampledAt).Seconds()
+	if elapsed <= 0 {
+		elapsed = 1
+	}
+
+	// Counters reset when an interface goes down; skip the rate rather
+	// than report a wrapped value.
+	if prev.HasNet && cur.HasNet {
+		if cur.NetRxBytes >= prev.NetRxBytes {
+			s.NetRxRate = uint64(float64(cur.NetRxBytes-prev.NetRxBytes) / elapsed)
+		}
+		if cur.NetTxBytes >= prev.NetTxBytes {
+			s.NetTxRate = uint64(float64(cur.NetTxBytes-prev.NetTxBytes) / elapsed)
+		}
+	}
+
+	return s, prev.Complete() && cur.Complete()
+}
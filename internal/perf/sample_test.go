@@ -0,0 +1,87 @@
+package perf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+)
+
+func TestDerive(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	prev := adb.SystemStats{
+		SampledAt:  t0,
+		CPUTotal:   1000,
+		CPUIdle:    800,
+		MemTotal:   8000,
+		MemUsed:    6000,
+		NetRxBytes: 10000,
+		NetTxBytes: 5000,
+		HasCPU:     true,
+		HasMem:     true,
+		HasNet:     true,
+	}
+	cur := prev
+	cur.SampledAt = t0.Add(2 * time.Second)
+	cur.CPUTotal = 1200
+	cur.CPUIdle = 950
+	cur.MemUsed = 2000
+	cur.MemAvailable = 6000
+	cur.NetRxBytes = 14000
+	cur.NetTxBytes = 6000
+
+	with := func(s adb.SystemStats, edit func(*adb.SystemStats)) adb.SystemStats {
+		edit(&s)
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		prev, cur adb.SystemStats
+		want      Sample
+		wantOK    bool
+	}{
+		{
+			name:   "both complete",
+			prev:   prev,
+			cur:    cur,
+			want:   Sample{Time: cur.SampledAt, CPUPercent: 25, MemUsedKB: 2000, MemAvailableKB: 6000, MemTotalKB: 8000, MemPercent: 25, NetRxRate: 2000, NetTxRate: 500},
+			wantOK: true,
+		},
+		{
+			name:   "counter reset",
+			prev:   prev,
+			cur:    with(cur, func(s *adb.SystemStats) { s.NetRxBytes = 100 }),
+			want:   Sample{Time: cur.SampledAt, CPUPercent: 25, MemUsedKB: 2000, MemAvailableKB: 6000, MemTotalKB: 8000, MemPercent: 25, NetTxRate: 500},
+			wantOK: true,
+		},
+		{
+			name: "net missing before",
+			prev: with(prev, func(s *adb.SystemStats) {
+				s.NetRxBytes, s.NetTxBytes, s.HasNet = 0, 0, false
+			}),
+			cur:    cur,
+			want:   Sample{Time: cur.SampledAt, CPUPercent: 25, MemUsedKB: 2000, MemAvailableKB: 6000, MemTotalKB: 8000, MemPercent: 25},
+			wantOK: false,
+		},
+		{
+			name: "cpu and memory missing now",
+			prev: prev,
+			cur: with(cur, func(s *adb.SystemStats) {
+				s.CPUTotal, s.CPUIdle, s.HasCPU = 0, 0, false
+				s.MemTotal, s.MemUsed, s.MemAvailable, s.HasMem = 0, 0, 0, false
+			}),
+			want:   Sample{Time: cur.SampledAt, NetRxRate: 2000, NetTxRate: 500},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Derive(tt.prev, tt.cur)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Derive() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
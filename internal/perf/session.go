@@ -0,0 +1,59 @@
+package perf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+)
+
+// RecordSession samples the device every interval and writes the derived
+// samples to path until ctx is cancelled or duration elapses (zero means
+// no limit). The summary report is printed to out when the session ends.
+func RecordSession(ctx context.Context, serial, path string, interval, duration time.Duration, out io.Writer) error {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		return err
+	}
+
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	fmt.Fprintf(out, "Recording %s to %s (ctrl+c to stop)\n", serial, path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prev := adb.GetSystemStats(serial)
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			cur := adb.GetSystemStats(serial)
+			if sample, ok := Derive(prev, cur); ok {
+				if err := recorder.Write(sample); err != nil {
+					recorder.Close()
+					return err
+				}
+			}
+			prev = cur
+		}
+	}
+
+	summary, err := recorder.Close()
+	fmt.Fprintln(out)
+	fmt.Fprint(out, summary.Report())
+	return err
+}
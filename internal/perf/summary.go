@@ -0,0 +1,119 @@
+package perf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+)
+
+// Stat is the min/avg/p95/max of one metric over a recording.
+type Stat struct {
+	Min float64
+	Avg float64
+	P95 float64
+	Max float64
+}
+
+type Summary struct {
+	Start   time.Time
+	End     time.Time
+	Samples int
+
+	CPUPercent     Stat
+	MemPercent     Stat
+	MemAvailableKB Stat
+	NetRxRate      Stat
+	NetTxRate      Stat
+}
+
+func Summarize(samples []Sample) Summary {
+	summary := Summary{Samples: len(samples)}
+	if len(samples) == 0 {
+		return summary
+	}
+
+	summary.Start = samples[0].Time
+	summary.End = samples[len(samples)-1].Time
+
+	summary.CPUPercent = computeStat(samples, func(s Sample) float64 { return s.CPUPercent })
+	summary.MemPercent = computeStat(samples, func(s Sample) float64 { return s.MemPercent })
+	summary.MemAvailableKB = computeStat(samples, func(s Sample) float64 { return float64(s.MemAvailableKB) })
+	summary.NetRxRate = computeStat(samples, func(s Sample) float64 { return float64(s.NetRxRate) })
+	summary.NetTxRate = computeStat(samples, func(s Sample) float64 { return float64(s.NetTxRate) })
+
+	return summary
+}
+
+func computeStat(samples []Sample, value func(Sample) float64) Stat {
+	values := make([]float64, len(samples))
+	var sum float64
+	for i, s := range samples {
+		values[i] = value(s)
+		sum += values[i]
+	}
+	sort.Float64s(values)
+
+	// Nearest-rank percentile.
+	rank := int(math.Ceil(0.95*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return Stat{
+		Min: values[0],
+		Avg: sum / float64(len(values)),
+		P95: values[rank],
+		Max: values[len(values)-1],
+	}
+}
+
+func (s Summary) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Report renders the summary as a plain-text table.
+func (s Summary) Report() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Samples:  %d\n", s.Samples)
+	if s.Samples == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Start:    %s\n", s.Start.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Duration: %s\n\n", s.Duration().Round(time.Second))
+
+	fmt.Fprintf(&b, "%-16s %12s %12s %12s %12s\n", "Metric", "Min", "Avg", "P95", "Max")
+	for _, row := range s.Rows() {
+		fmt.Fprintf(&b, "%-16s %12s %12s %12s %12s\n", row[0], row[1], row[2], row[3], row[4])
+	}
+
+	return b.String()
+}
+
+// Rows returns the summary as formatted table rows of
+// metric, min, avg, p95 and max.
+func (s Summary) Rows() [][5]string {
+	percent := func(v float64) string { return fmt.Sprintf("%.1f%%", v) }
+	kb := func(v float64) string { return formatBytes(v * 1024) }
+	rate := func(v float64) string { return formatBytes(v) + "/s" }
+
+	row := func(name string, st Stat, format func(float64) string) [5]string {
+		return [5]string{name, format(st.Min), format(st.Avg), format(st.P95), format(st.Max)}
+	}
+
+	return [][5]string{
+		row("CPU", s.CPUPercent, percent),
+		row("Memory used", s.MemPercent, percent),
+		row("Memory avail", s.MemAvailableKB, kb),
+		row("Net ↓", s.NetRxRate, rate),
+		row("Net ↑", s.NetTxRate, rate),
+	}
+}
+
+func formatBytes(n float64) string {
+	return adb.FormatFileSize(fmt.Sprintf("%.0f", n))
+}
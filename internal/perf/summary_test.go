@@ -0,0 +1,78 @@
+package perf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStat(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   Stat
+	}{
+		{
+			name:   "single sample",
+			values: []float64{42},
+			want:   Stat{Min: 42, Avg: 42, P95: 42, Max: 42},
+		},
+		{
+			name:   "nearest rank of twenty",
+			values: []float64{20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			want:   Stat{Min: 1, Avg: 10.5, P95: 19, Max: 20},
+		},
+		{
+			name:   "nearest rank rounds up",
+			values: []float64{10, 30, 20},
+			want:   Stat{Min: 10, Avg: 20, P95: 30, Max: 30},
+		},
+		{
+			name:   "one spike",
+			values: []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 100},
+			want:   Stat{Min: 5, Avg: 9.75, P95: 5, Max: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([]Sample, len(tt.values))
+			for i, v := range tt.values {
+				samples[i].CPUPercent = v
+			}
+			got := computeStat(samples, func(s Sample) float64 { return s.CPUPercent })
+			if got != tt.want {
+				t.Errorf("computeStat(%v) = %+v, want %+v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: start, CPUPercent: 10, MemPercent: 50, MemAvailableKB: 4000, NetRxRate: 100, NetTxRate: 10},
+		{Time: start.Add(time.Second), CPUPercent: 30, MemPercent: 60, MemAvailableKB: 3000, NetRxRate: 300, NetTxRate: 30},
+		{Time: start.Add(2 * time.Second), CPUPercent: 20, MemPercent: 55, MemAvailableKB: 3500, NetRxRate: 200, NetTxRate: 20},
+	}
+
+	got := Summarize(samples)
+	if got.Samples != 3 {
+		t.Errorf("Samples = %d, want 3", got.Samples)
+	}
+	if got.Duration() != 2*time.Second {
+		t.Errorf("Duration() = %v, want 2s", got.Duration())
+	}
+	if want := (Stat{Min: 10, Avg: 20, P95: 30, Max: 30}); got.CPUPercent != want {
+		t.Errorf("CPUPercent = %+v, want %+v", got.CPUPercent, want)
+	}
+	if want := (Stat{Min: 3000, Avg: 3500, P95: 4000, Max: 4000}); got.MemAvailableKB != want {
+		t.Errorf("MemAvailableKB = %+v, want %+v", got.MemAvailableKB, want)
+	}
+	if want := (Stat{Min: 10, Avg: 20, P95: 30, Max: 30}); got.NetTxRate != want {
+		t.Errorf("NetTxRate = %+v, want %+v", got.NetTxRate, want)
+	}
+
+	if empty := Summarize(nil); empty.Samples != 0 || empty.Report() != "Samples:  0\n" {
+		t.Errorf("Summarize(nil) = %+v, report %q", empty, empty.Report())
+	}
+}
@@ -0,0 +1,196 @@
+// Package project detects a Gradle Android project in the working
+// directory from its build outputs. It only reads files that a build has
+// already written and never runs Gradle.
+package project
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// OutputsDir is where the Android Gradle plugin writes the app module's
+// APKs, one directory per variant with an output-metadata.json.
+var OutputsDir = filepath.Join("app", "build", "outputs", "apk")
+
+type Variant struct {
+	// Name is the variant name, e.g. "debug" or "freeRelease".
+	Name          string
+	ApplicationID string
+	VersionCode   int
+	VersionName   string
+	APK           string
+	Modified      time.Time
+}
+
+type Project struct {
+	Root string
+	// Variants are sorted by build time, newest first.
+	Variants []Variant
+}
+
+// output-metadata.json as written by the Android Gradle plugin.
+type outputMetadata struct {
+	ApplicationID string `json:"applicationId"`
+	VariantName   string `json:"variantName"`
+	Elements      []struct {
+		Filters []struct {
+			FilterType string `json:"filterType"`
+			Value      string `json:"value"`
+		} `json:"filters"`
+		VersionCode int    `json:"versionCode"`
+		VersionName string `json:"versionName"`
+		OutputFile  string `json:"outputFile"`
+	} `json:"elements"`
+}
+
+// Detect looks for an Android project in dir. It returns nil when dir has
+// neither a Gradle settings file nor app build outputs.
+func Detect(dir string) (*Project, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := filepath.Join(root, OutputsDir)
+	if !isGradleRoot(root) && !exists(outputs) {
+		return nil, nil
+	}
+
+	p := &Project{Root: root}
+	if !exists(outputs) {
+		return p, nil
+	}
+
+	err = filepath.WalkDir(outputs, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "output-metadata.json" {
+			return nil
+		}
+		if v, ok := readVariant(path); ok {
+			p.Variants = append(p.Variants, v)
+		}
+		return nil
+	})
+
+	sort.SliceStable(p.Variants, func(i, j int) bool {
+		return p.Variants[i].Modified.After(p.Variants[j].Modified)
+	})
+	return p, err
+}
+
+// readVariant picks the variant's newest universal APK, falling back to
+// per-ABI or per-density outputs when there is no universal one.
+func readVariant(metadataPath string) (Variant, bool) {
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return Variant{}, false
+	}
+
+	var meta outputMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return Variant{}, false
+	}
+
+	dir := filepath.Dir(metadataPath)
+	var best Variant
+	bestUniversal := false
+	for _, el := range meta.Elements {
+		if el.OutputFile == "" {
+			continue
+		}
+		apkPath := filepath.Join(dir, el.OutputFile)
+		info, err := os.Stat(apkPath)
+		if err != nil {
+			continue
+		}
+
+		universal := len(el.Filters) == 0
+		better := best.APK == "" ||
+			universal && !bestUniversal ||
+			universal == bestUniversal && info.ModTime().After(best.Modified)
+		if !better {
+			continue
+		}
+		bestUniversal = universal
+		best = Variant{
+			Name:          meta.VariantName,
+			ApplicationID: meta.ApplicationID,
+			VersionCode:   el.VersionCode,
+			VersionName:   el.VersionName,
+			APK:           apkPath,
+			Modified:      info.ModTime(),
+		}
+	}
+
+	if best.APK == "" {
+		return Variant{}, false
+	}
+	if best.Name == "" {
+		best.Name = filepath.Base(dir)
+	}
+	return best, true
+}
+
+// Latest is the most recently built variant, or nil before the first
+// build.
+func (p *Project) Latest() *Variant {
+	if p == nil || len(p.Variants) == 0 {
+		return nil
+	}
+	return &p.Variants[0]
+}
+
+// ApplicationID of the latest build. Variants can differ, e.g. through an
+// applicationIdSuffix on debug builds.
+func (p *Project) ApplicationID() string {
+	if v := p.Latest(); v != nil {
+		return v.ApplicationID
+	}
+	return ""
+}
+
+// Owns reports whether one of the project's variants builds pkg.
+func (p *Project) Owns(pkg string) bool {
+	if p == nil || pkg == "" {
+		return false
+	}
+	for _, v := range p.Variants {
+		if v.ApplicationID == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// OutputsPath is the app module's APK output directory.
+func (p *Project) OutputsPath() string {
+	return filepath.Join(p.Root, OutputsDir)
+}
+
+func (v Variant) Version() string {
+	if v.VersionName == "" {
+		return strconv.Itoa(v.VersionCode)
+	}
+	return v.VersionName + " (" + strconv.Itoa(v.VersionCode) + ")"
+}
+
+func isGradleRoot(dir string) bool {
+	for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+		if exists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
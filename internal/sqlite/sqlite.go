@@ -0,0 +1,179 @@
+// Package sqlite reads SQLite databases pulled from a device through the
+// host's sqlite3 command-line shell (3.37 or newer for -safe), opened
+// read-only.
+package sqlite
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// NullValue is how NULL is shown in results.
+const NullValue = "NULL"
+
+var ErrNoSQLite = errors.New("sqlite3 not found on PATH; install the SQLite command-line shell")
+
+type Table struct {
+	Name string
+	// Type is "table" or "view".
+	Type   string
+	Schema string
+	Rows   int
+}
+
+// Result is the output of a query. Columns is empty when the query
+// returned no rows, since sqlite3 only prints headers with the first row.
+type Result struct {
+	Columns []string
+	Rows    [][]string
+}
+
+type DB struct {
+	Path string
+}
+
+func Open(path string) (*DB, error) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return nil, ErrNoSQLite
+	}
+	return &DB{Path: path}, nil
+}
+
+// Query runs SQL against the database opened read-only, so writes fail.
+// -safe also refuses dot-commands such as .shell and ATTACH in ad-hoc
+// queries.
+func (db *DB) Query(query string) (Result, error) {
+	cmd := exec.Command(
+		"sqlite3",
+		"-safe",
+		"-readonly",
+		"-bail",
+		"-csv",
+		"-header",
+		"-nullvalue", NullValue,
+		db.Path,
+	)
+	cmd.Stdin = strings.NewReader(query)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Result{}, errors.New(msg)
+		}
+		return Result{}, err
+	}
+
+	return parseResult(stdout.Bytes())
+}
+
+// parseResult reads sqlite3 -csv -header output. Values may span lines
+// and contain commas and quotes, which sqlite3 quotes the CSV way.
+func parseResult(out []byte) (Result, error) {
+	reader := csv.NewReader(bytes.NewReader(out))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to parse sqlite3 output: %w", err)
+	}
+	if len(records) == 0 {
+		return Result{}, nil
+	}
+	return Result{Columns: records[0], Rows: records[1:]}, nil
+}
+
+// Tables lists tables and views with their schema and row count.
+func (db *DB) Tables() ([]Table, error) {
+	res, err := db.Query(
+		"SELECT name, type, sql FROM sqlite_master " +
+			"WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' " +
+			"ORDER BY name;",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]Table, 0, len(res.Rows))
+	var counts strings.Builder
+	for _, row := range res.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		tables = append(tables, Table{Name: row[0], Type: row[1], Schema: row[2]})
+		fmt.Fprintf(&counts, "SELECT %s, count(*) FROM %s;\n", Quote(row[0]), Ident(row[0]))
+	}
+	if len(tables) == 0 {
+		return tables, nil
+	}
+
+	// Counts go in one run; a view over a missing table leaves its count
+	// at zero rather than failing the list.
+	cmd := exec.Command("sqlite3", "-safe", "-readonly", "-csv", db.Path)
+	cmd.Stdin = strings.NewReader(counts.String())
+	out, _ := cmd.Output()
+	byName := parseCounts(out)
+	for i := range tables {
+		tables[i].Rows = byName[tables[i].Name]
+	}
+
+	return tables, nil
+}
+
+// parseCounts reads "name,count" records, keeping those before any
+// malformed one.
+func parseCounts(out []byte) map[string]int {
+	byName := map[string]int{}
+	reader := csv.NewReader(bytes.NewReader(out))
+	reader.FieldsPerRecord = -1
+	for {
+		r, err := reader.Read()
+		if err != nil {
+			break
+		}
+		if len(r) == 2 {
+			byName[r[0]], _ = strconv.Atoi(r[1])
+		}
+	}
+	return byName
+}
+
+// Page returns up to limit rows of a table starting at offset.
+func (db *DB) Page(table string, limit, offset int) (Result, error) {
+	res, err := db.Query(fmt.Sprintf("SELECT * FROM %s LIMIT %d OFFSET %d;", Ident(table), limit, offset))
+	if err != nil {
+		return res, err
+	}
+	if len(res.Columns) == 0 {
+		res.Columns, err = db.Columns(table)
+	}
+	return res, err
+}
+
+// Columns returns a table's column names in order.
+func (db *DB) Columns(table string) ([]string, error) {
+	res, err := db.Query(fmt.Sprintf("SELECT name FROM pragma_table_info(%s);", Quote(table)))
+	if err != nil {
+		return nil, err
+	}
+	cols := make([]string, 0, len(res.Rows))
+	for _, row := range res.Rows {
+		cols = append(cols, row[0])
+	}
+	return cols, nil
+}
+
+// Ident quotes an identifier such as a table name.
+func Ident(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Quote quotes a string literal.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    Result
+		wantErr bool
+	}{
+		{
+			name: "rows",
+			out:  "_id,name,value\r\n1,adb_enabled,1\r\n2,device_name,NULL\r\n",
+			want: Result{Columns: []string{"_id", "name", "value"}, Rows: [][]string{{"1", "adb_enabled", "1"}, {"2", "device_name", "NULL"}}},
+		},
+		{
+			name: "quoted commas, quotes and newlines",
+			out:  "id,body\r\n7,\"Meet at 5, \"\"sharp\"\"\nbring keys\"\r\n8,\r\n",
+			want: Result{Columns: []string{"id", "body"}, Rows: [][]string{{"7", "Meet at 5, \"sharp\"\nbring keys"}, {"8", ""}}},
+		},
+		{
+			name: "header only",
+			out:  "count(*)\r\n",
+			want: Result{Columns: []string{"count(*)"}, Rows: [][]string{}},
+		},
+		{
+			name: "several statements",
+			out:  "a\r\n1\r\nb,c\r\n2,3\r\n",
+			want: Result{Columns: []string{"a"}, Rows: [][]string{{"1"}, {"b", "c"}, {"2", "3"}}},
+		},
+		{name: "no rows", out: ""},
+		{name: "malformed", out: "a,b\r\n\"unterminated\r\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseResult([]byte(tt.out))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseResult() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCounts(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[string]int
+	}{
+		{"counts", "items,25\r\nempty,0\r\n", map[string]int{"items": 25, "empty": 0}},
+		{"quoted name", "\"a,b\",3\r\n\"odd \"\"name\"\"\",4\r\n", map[string]int{"a,b": 3, `odd "name"`: 4}},
+		{"not a number", "items,lots\r\n", map[string]int{"items": 0}},
+		{"malformed record", "items,25\r\n\"broken\r\n", map[string]int{"items": 25}},
+		{"nothing", "", map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCounts([]byte(tt.out)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testDB creates a database with the sqlite3 shell, skipping the test
+// when it is not installed.
+func testDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Skip(err)
+	}
+
+	var script strings.Builder
+	script.WriteString("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT);\n")
+	for i := 1; i <= 25; i++ {
+		script.WriteString("INSERT INTO items (name) VALUES ('item " + strconv.Itoa(i) + "');\n")
+	}
+	script.WriteString(`CREATE TABLE empty (a, b);
+CREATE TABLE "odd ""name""" (x);
+INSERT INTO "odd ""name""" VALUES (NULL);
+CREATE TABLE gone (y);
+CREATE VIEW first_items AS SELECT * FROM items WHERE id <= 3;
+CREATE VIEW broken AS SELECT * FROM gone;
+DROP TABLE gone;
+`)
+
+	cmd := exec.Command("sqlite3", db.Path)
+	cmd.Stdin = strings.NewReader(script.String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("creating test database: %v: %s", err, out)
+	}
+	return db
+}
+
+func TestTables(t *testing.T) {
+	db := testDB(t)
+	tables, err := db.Tables()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]Table{}
+	var names []string
+	for _, table := range tables {
+		got[table.Name] = table
+		names = append(names, table.Name)
+	}
+	if want := []string{"broken", "empty", "first_items", "items", `odd "name"`}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Tables() names = %q, want %q", names, want)
+	}
+
+	for name, want := range map[string]struct {
+		typ  string
+		rows int
+	}{
+		"items":       {"table", 25},
+		"empty":       {"table", 0},
+		`odd "name"`:  {"table", 1},
+		"first_items": {"view", 3},
+		"broken":      {"view", 0},
+	} {
+		if table := got[name]; table.Type != want.typ || table.Rows != want.rows {
+			t.Errorf("%s: type %q with %d rows, want %q with %d", name, table.Type, table.Rows, want.typ, want.rows)
+		}
+	}
+	if !strings.HasPrefix(got["items"].Schema, "CREATE TABLE items") {
+		t.Errorf("items schema = %q", got["items"].Schema)
+	}
+}
+
+func TestPage(t *testing.T) {
+	db := testDB(t)
+
+	tests := []struct {
+		name          string
+		table         string
+		limit, offset int
+		wantIDs       []string
+	}{
+		{"first page", "items", 10, 0, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}},
+		{"last partial page", "items", 10, 20, []string{"21", "22", "23", "24", "25"}},
+		{"past the end", "items", 10, 30, nil},
+		{"empty table", "empty", 10, 0, nil},
+		{"quoted name", `odd "name"`, 10, 0, []string{NullValue}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := db.Page(tt.table, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Columns) == 0 {
+				t.Fatalf("Page() has no columns")
+			}
+			var ids []string
+			for _, row := range res.Rows {
+				ids = append(ids, row[0])
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Page() first column = %q, want %q", ids, tt.wantIDs)
+			}
+		})
+	}
+
+	res, err := db.Page("empty", 10, 0)
+	if err != nil || !reflect.DeepEqual(res.Columns, []string{"a", "b"}) {
+		t.Errorf("Page() of an empty table columns = %q, %v, want a, b", res.Columns, err)
+	}
+}
+
+func TestQueryReadOnly(t *testing.T) {
+	db := testDB(t)
+	if _, err := db.Query("DELETE FROM items;"); err == nil {
+		t.Error("Query() ran a DELETE on a read-only database")
+	}
+	if _, err := db.Query(".shell echo hi"); err == nil {
+		t.Error("Query() ran .shell despite -safe")
+	}
+}
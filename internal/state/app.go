@@ -1,24 +1,55 @@
 package state
 
-import "github.com/SakshhamTheCoder/adbt/internal/adb"
+import (
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/alerts"
+	"github.com/SakshhamTheCoder/adbt/internal/project"
+)
 
 type AppState struct {
 	SelectedDeviceSerial string
-	Devices              []adb.Device
-	Width                int
-	Height               int
+	// SelectedUser is the Android user that app actions target.
+	SelectedUser    int
+	SelectedPackage string
+	Devices         []adb.Device
+	Width           int
+	Height          int
+
+	Alerts *alerts.Monitor
+
+	// Project is the Android project adbt was started in, nil outside one.
+	Project *project.Project
 }
 
 func New() *AppState {
 	return &AppState{
 		Devices: []adb.Device{},
+		Alerts:  alerts.NewMonitor(),
 	}
 }
 
 func (s *AppState) SelectDevice(serial string) {
+	if serial != s.SelectedDeviceSerial {
+		if s.Alerts != nil {
+			s.Alerts.Reset()
+		}
+		s.SelectedUser = 0
+	}
 	s.SelectedDeviceSerial = serial
 }
 
+// SelectUser switches the user that app actions target, e.g. a work
+// profile.
+func (s *AppState) SelectUser(user int) {
+	s.SelectedUser = user
+}
+
+// SelectPackage records the app that per-package screens (benchmark,
+// details, ...) operate on.
+func (s *AppState) SelectPackage(pkg string) {
+	s.SelectedPackage = pkg
+}
+
 func (s *AppState) SelectedDevice() *adb.Device {
 	if s.SelectedDeviceSerial == "" {
 		return nil
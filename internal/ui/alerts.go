@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/alerts"
+	"github.com/SakshhamTheCoder/adbt/internal/perf"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alerts are evaluated at the app level so they keep firing whichever
+// screen is open. The poller only talks to the device while rules exist.
+const alertPollInterval = 2 * time.Second
+
+type alertTickMsg time.Time
+
+type alertSampleMsg struct {
+	serial  string
+	stats   adb.SystemStats
+	battery adb.BatteryStats
+	err     error
+}
+
+func alertTickCmd() tea.Cmd {
+	return tea.Tick(alertPollInterval, func(t time.Time) tea.Msg {
+		return alertTickMsg(t)
+	})
+}
+
+func (a *App) pollAlertsCmd() tea.Cmd {
+	if !a.state.Alerts.HasRules() || !a.state.HasDevice() {
+		return nil
+	}
+
+	serial := a.state.DeviceSerial()
+	return func() tea.Msg {
+		battery, err := adb.GetBatteryStats(serial)
+		return alertSampleMsg{
+			serial:  serial,
+			stats:   adb.GetSystemStats(serial),
+			battery: battery,
+			err:     err,
+		}
+	}
+}
+
+// observeStats feeds a /proc snapshot into the alert rules. Snapshots come
+// from both the alert poller and the Performance screen; since rates are
+// derived from the snapshot timestamps the two streams can be interleaved.
+func (a *App) observeStats(serial string, stats adb.SystemStats) {
+	if serial == "" || serial != a.state.DeviceSerial() {
+		return
+	}
+
+	if a.alertSerial == serial && stats.SampledAt.After(a.alertStats.SampledAt) {
+		if sample, ok := perf.Derive(a.alertStats, stats); ok {
+			a.state.Alerts.Evaluate(serial, alerts.SampleValues(sample), stats.SampledAt)
+		}
+	}
+
+	if a.alertSerial != serial || stats.SampledAt.After(a.alertStats.SampledAt) {
+		a.alertSerial = serial
+		a.alertStats = stats
+	}
+}
+
+func (a *App) observeBattery(serial string, battery adb.BatteryStats) {
+	if serial == "" || serial != a.state.DeviceSerial() {
+		return
+	}
+	a.state.Alerts.Evaluate(serial, alerts.BatteryValues(battery), battery.SampledAt)
+}
+
+func (a *App) renderAlertBanner(view string) string {
+	pending := a.state.Alerts.Unacknowledged
+	if len(pending) == 0 || a.screenName == "alerts" {
+		return view
+	}
+
+	latest := pending[len(pending)-1]
+	message := fmt.Sprintf(
+		"⚠ %s (now %.1f%s) at %s",
+		latest.Rule,
+		latest.Value,
+		latest.Rule.Metric.Unit(),
+		latest.Time.Format("15:04:05"),
+	)
+	if len(pending) > 1 {
+		message += fmt.Sprintf("  +%d more", len(pending)-1)
+	}
+	message += "  — open Alerts [!] to acknowledge"
+
+	return components.RenderBanner(view, message, a.state)
+}
@@ -1,6 +1,10 @@
 package ui
 
 import (
+	"os"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/project"
 	"github.com/SakshhamTheCoder/adbt/internal/state"
 	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
 	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
@@ -13,6 +17,10 @@ type App struct {
 	state         *state.AppState
 	currentScreen tea.Model
 	screenName    string
+
+	// Last snapshot seen by the alert rules
+	alertSerial string
+	alertStats  adb.SystemStats
 }
 
 type LifecycleScreen interface {
@@ -20,8 +28,18 @@ type LifecycleScreen interface {
 	Cleanup() tea.Cmd
 }
 
+// InputScreen is a screen with forms or a search bar. While one of them
+// takes keystrokes, "q" is typed into it instead of quitting.
+type InputScreen interface {
+	tea.Model
+	InputActive() bool
+}
+
 func NewApp() *App {
 	appState := state.New()
+	if cwd, err := os.Getwd(); err == nil {
+		appState.Project, _ = project.Detect(cwd)
+	}
 
 	return &App{
 		state:         appState,
@@ -31,7 +49,7 @@ func NewApp() *App {
 }
 
 func (a *App) Init() tea.Cmd {
-	return tea.Batch(a.setAppTitle(), a.currentScreen.Init())
+	return tea.Batch(a.setAppTitle(), a.currentScreen.Init(), alertTickCmd())
 }
 
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -42,9 +60,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "q":
+		case "ctrl+c":
 			return a, tea.Batch(a.cleanupCurrentScreen(), tea.Quit)
 
+		case "q":
+			if !a.inputActive() {
+				return a, tea.Batch(a.cleanupCurrentScreen(), tea.Quit)
+			}
+
 		case "esc":
 			var cmd tea.Cmd
 			a.currentScreen, cmd = a.currentScreen.Update(msg)
@@ -59,6 +82,26 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case navigation.SwitchScreenMsg:
 		return a.switchScreen(msg.Screen)
+
+	case alertTickMsg:
+		return a, tea.Batch(a.pollAlertsCmd(), alertTickCmd())
+
+	case alertSampleMsg:
+		a.observeStats(msg.serial, msg.stats)
+		if msg.err == nil {
+			a.observeBattery(msg.serial, msg.battery)
+		}
+		return a, nil
+
+	case adb.SystemStatsMsg:
+		if msg.Error == nil {
+			a.observeStats(a.state.DeviceSerial(), msg.Stats)
+		}
+
+	case adb.BatteryStatsMsg:
+		if msg.Error == nil {
+			a.observeBattery(msg.Serial, msg.Stats)
+		}
 	}
 
 	var cmd tea.Cmd
@@ -80,14 +123,38 @@ func (a *App) switchScreen(name string) (*App, tea.Cmd) {
 		newScreen = screens.NewDeviceInfo(a.state)
 	case "files":
 		newScreen = screens.NewFiles(a.state)
+	case "app_files":
+		newScreen = screens.NewAppFiles(a.state)
 	case "logcat":
 		newScreen = screens.NewLogcat(a.state)
 	case "perf_monitor":
 		newScreen = screens.NewPerfMonitor(a.state)
 	case "intents":
 		newScreen = screens.NewIntents(a.state)
+	case "content":
+		newScreen = screens.NewContent(a.state)
 	case "ports":
 		newScreen = screens.NewPorts(a.state)
+	case "alerts":
+		newScreen = screens.NewAlerts(a.state)
+	case "benchmark":
+		newScreen = screens.NewBenchmark(a.state)
+	case "trace":
+		newScreen = screens.NewTrace(a.state)
+	case "app_details":
+		newScreen = screens.NewAppDetails(a.state)
+	case "permissions":
+		newScreen = screens.NewPermissions(a.state)
+	case "components":
+		newScreen = screens.NewComponents(a.state)
+	case "app_links":
+		newScreen = screens.NewAppLinks(a.state)
+	case "debloat":
+		newScreen = screens.NewDebloat(a.state)
+	case "watch":
+		newScreen = screens.NewWatch(a.state)
+	case "project":
+		newScreen = screens.NewProject(a.state)
 
 	default:
 		return a, nil
@@ -103,7 +170,7 @@ func (a *App) View() string {
 	if a.state.Width == 0 {
 		return "Initializing..."
 	}
-	return a.currentScreen.View()
+	return a.renderAlertBanner(a.currentScreen.View())
 }
 
 func (a *App) cleanupCurrentScreen() tea.Cmd {
@@ -114,6 +181,11 @@ func (a *App) cleanupCurrentScreen() tea.Cmd {
 	return screen.Cleanup()
 }
 
+func (a *App) inputActive() bool {
+	screen, ok := a.currentScreen.(InputScreen)
+	return ok && screen.InputActive()
+}
+
 func (a *App) setAppTitle() tea.Cmd {
 	// ADBT explicitly requests its title on startup and screen switches. Some
 	// terminals with shell integration may still override titles temporarily.
@@ -132,14 +204,38 @@ func screenDisplayTitle(name string) string {
 		return "Device Info"
 	case "files":
 		return "Files"
+	case "app_files":
+		return "App Files"
 	case "logcat":
 		return "Logcat"
 	case "perf_monitor":
 		return "Performance"
 	case "intents":
 		return "Intents"
+	case "content":
+		return "Content Providers"
 	case "ports":
 		return "Ports"
+	case "alerts":
+		return "Alerts"
+	case "benchmark":
+		return "Benchmark"
+	case "trace":
+		return "Trace"
+	case "app_details":
+		return "App Details"
+	case "permissions":
+		return "Permissions"
+	case "components":
+		return "Components"
+	case "app_links":
+		return "App Links"
+	case "debloat":
+		return "Debloat"
+	case "watch":
+		return "Watch"
+	case "project":
+		return "Project"
 	default:
 		return name
 	}
@@ -0,0 +1,20 @@
+package components
+
+import (
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var bannerStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FFFFFF")).
+	Background(Error).
+	Bold(true).
+	Padding(0, 1)
+
+// RenderBanner draws a full-width warning bar over the first line of base.
+func RenderBanner(base, message string, s *state.AppState) string {
+	width := max(s.Width, 20)
+	banner := bannerStyle.Width(width).MaxWidth(width).Render(message)
+	return RenderOverlayAt(base, banner, 0, 0)
+}
@@ -16,7 +16,11 @@ const (
 	ActionPerfMonitor Action = "perf_monitor"
 	ActionDeviceInfo  Action = "device_info"
 	ActionIntents     Action = "intents"
+	ActionContent     Action = "content"
 	ActionPorts       Action = "ports"
+	ActionAlerts      Action = "alerts"
+	ActionTrace       Action = "trace"
+	ActionProject     Action = "project"
 )
 
 func ResolveAction(action Action, state *state.AppState) tea.Cmd {
@@ -27,6 +31,16 @@ func ResolveAction(action Action, state *state.AppState) tea.Cmd {
 			return SwitchScreenMsg{Screen: "devices"}
 		}
 
+	case ActionAlerts:
+		return func() tea.Msg {
+			return SwitchScreenMsg{Screen: "alerts"}
+		}
+
+	case ActionProject:
+		return func() tea.Msg {
+			return SwitchScreenMsg{Screen: "project"}
+		}
+
 	case ActionLogcat:
 		if !state.HasDevice() {
 			return func() tea.Msg {
@@ -77,6 +91,26 @@ func ResolveAction(action Action, state *state.AppState) tea.Cmd {
 			return SwitchScreenMsg{Screen: "intents"}
 		}
 
+	case ActionContent:
+		if !state.HasDevice() {
+			return func() tea.Msg {
+				return SwitchScreenMsg{Screen: "devices"}
+			}
+		}
+		return func() tea.Msg {
+			return SwitchScreenMsg{Screen: "content"}
+		}
+
+	case ActionTrace:
+		if !state.HasDevice() {
+			return func() tea.Msg {
+				return SwitchScreenMsg{Screen: "devices"}
+			}
+		}
+		return func() tea.Msg {
+			return SwitchScreenMsg{Screen: "trace"}
+		}
+
 	case ActionPorts:
 		if !state.HasDevice() {
 			return func() tea.Msg {
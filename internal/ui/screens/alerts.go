@@ -0,0 +1,283 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/alerts"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxAlertLogRows is how many recent alerts are listed under the rules.
+const maxAlertLogRows = 20
+
+type Alerts struct {
+	state  *state.AppState
+	cursor int
+
+	form    components.FormModal
+	confirm components.ConfirmPrompt
+	toast   components.Toast
+
+	viewport viewport.Model
+}
+
+func NewAlerts(state *state.AppState) *Alerts {
+	return &Alerts{
+		state:    state,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (a *Alerts) Init() tea.Cmd {
+	// Opening the screen counts as seeing the alerts.
+	a.state.Alerts.Acknowledge()
+	return nil
+}
+
+// Cleanup acknowledges alerts that fired while the screen was open.
+func (a *Alerts) Cleanup() tea.Cmd {
+	a.state.Alerts.Acknowledge()
+	return nil
+}
+
+func (a *Alerts) InputActive() bool {
+	return a.form.Visible
+}
+
+func (a *Alerts) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	a.toast.Update(msg)
+	monitor := a.state.Alerts
+
+	if a.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			a.form.Hide()
+			rule, err := parseAlertRule(msg.Values)
+			if err == nil {
+				err = monitor.AddRule(rule)
+			}
+			if err != nil {
+				var cmd tea.Cmd
+				a.toast, cmd = components.ShowToast(
+					"Invalid rule: "+err.Error(),
+					true,
+					3*time.Second,
+				)
+				return a, cmd
+			}
+			a.cursor = len(monitor.Rules) - 1
+			var cmd tea.Cmd
+			a.toast, cmd = components.ShowToast(
+				"Rule added",
+				false,
+				2*time.Second,
+			)
+			return a, cmd
+		case components.FormCancelMsg:
+			a.form.Hide()
+			return a, nil
+		}
+		return a, a.form.Update(msg)
+	}
+
+	if a.confirm.Visible {
+		switch msg.(type) {
+		case components.ConfirmYesMsg:
+			a.confirm.Hide()
+			if err := monitor.RemoveRule(a.cursor); err != nil {
+				var cmd tea.Cmd
+				a.toast, cmd = components.ShowToast(
+					"Failed to save rules: "+err.Error(),
+					true,
+					3*time.Second,
+				)
+				return a, cmd
+			}
+			if a.cursor >= len(monitor.Rules) && a.cursor > 0 {
+				a.cursor--
+			}
+			return a, nil
+		case components.ConfirmNoMsg:
+			a.confirm.Hide()
+			return a, nil
+		}
+		return a, a.confirm.Update(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "k":
+			if a.cursor > 0 {
+				a.cursor--
+			}
+		case "down", "j":
+			if a.cursor < len(monitor.Rules)-1 {
+				a.cursor++
+			}
+		case "a":
+			a.showForm()
+		case "d":
+			if a.cursor < len(monitor.Rules) {
+				a.confirm.Show("Delete rule:\n" + monitor.Rules[a.cursor].String())
+			}
+		case "c":
+			monitor.Events = nil
+			monitor.Acknowledge()
+		default:
+			return a, a.updateViewport(msg)
+		}
+	}
+
+	return a, nil
+}
+
+func (a *Alerts) showForm() {
+	metrics := make([]string, len(alerts.Metrics))
+	for i, m := range alerts.Metrics {
+		metrics[i] = string(m)
+	}
+	comparators := make([]string, len(alerts.Comparators))
+	for i, c := range alerts.Comparators {
+		comparators[i] = string(c)
+	}
+
+	a.form.Show("Add Alert Rule", []components.FormField{
+		{Label: "Metric", Type: components.FormFieldSelect, Options: metrics, Value: string(alerts.MetricCPU)},
+		{Label: "Comparator", Type: components.FormFieldSelect, Options: comparators, Value: string(alerts.Above)},
+		{Label: "Threshold", Placeholder: "90"},
+		{Label: "Duration (s)", Value: "0"},
+		{Label: "Hook Command", Placeholder: "optional, e.g. notify-send \"$ADBT_ALERT_RULE\""},
+	})
+}
+
+func parseAlertRule(values []string) (alerts.Rule, error) {
+	if len(values) < 5 {
+		return alerts.Rule{}, fmt.Errorf("missing fields")
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(values[2]), 64)
+	if err != nil {
+		return alerts.Rule{}, fmt.Errorf("threshold must be a number")
+	}
+
+	duration := 0
+	if d := strings.TrimSpace(values[3]); d != "" {
+		duration, err = strconv.Atoi(d)
+		if err != nil {
+			return alerts.Rule{}, fmt.Errorf("duration must be whole seconds")
+		}
+	}
+
+	return alerts.Rule{
+		Metric:          alerts.Metric(values[0]),
+		Comparator:      alerts.Comparator(values[1]),
+		Threshold:       threshold,
+		DurationSeconds: duration,
+		Hook:            strings.TrimSpace(values[4]),
+	}, nil
+}
+
+func (a *Alerts) View() string {
+	monitor := a.state.Alerts
+
+	maxWidth := a.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var body strings.Builder
+	body.WriteString(components.TitleStyle.Render("Rules") + "\n")
+
+	if monitor.LoadError != nil {
+		body.WriteString(components.ErrorStyle.Render("  Failed to load rules: "+monitor.LoadError.Error()) + "\n")
+	}
+
+	if len(monitor.Rules) == 0 {
+		body.WriteString(components.StatusMuted.Render("  No rules configured") + "\n")
+		body.WriteString(components.StatusMuted.Render("  Press [a] to add a rule") + "\n")
+	}
+
+	for i, rule := range monitor.Rules {
+		prefix := "  "
+		style := components.ListItemStyle
+		if i == a.cursor {
+			prefix = "› "
+			style = components.ListItemSelectedStyle
+		}
+
+		line := prefix + style.Render(rule.String())
+		if rule.Hook != "" {
+			line += " " + components.StatusMuted.Render("→ "+rule.Hook)
+		}
+		body.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	body.WriteString("\n" + components.TitleStyle.Render("Recent Alerts") + "\n")
+
+	if len(monitor.Events) == 0 {
+		body.WriteString(components.StatusMuted.Render("  No alerts fired this session") + "\n")
+	}
+
+	events := monitor.Events
+	if len(events) > maxAlertLogRows {
+		events = events[len(events)-maxAlertLogRows:]
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		line := fmt.Sprintf(
+			"  %s  %s  %s  %s",
+			components.StatusMuted.Render(e.Time.Format("15:04:05")),
+			components.StatusMuted.Render(e.Serial),
+			components.ErrorStyle.Render(fmt.Sprintf("%.1f%s", e.Value, e.Rule.Metric.Unit())),
+			e.Rule.String(),
+		)
+		if e.HookError != "" {
+			line += " " + components.ErrorStyle.Render("hook failed: "+e.HookError)
+		}
+		body.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	if !a.state.HasDevice() {
+		body.WriteString("\n" + components.StatusMuted.Render("Rules are evaluated once a device is selected.") + "\n")
+	}
+
+	rendered := components.RenderLayoutWithScrollableSection(a.state, components.LayoutWithScrollProps{
+		Title:             "Alerts",
+		ScrollableContent: body.String(),
+		Footer: components.Help("↑/↓", "navigate") + "  " +
+			components.Help("a", "add rule") + "  " +
+			components.Help("d", "delete rule") + "  " +
+			components.Help("c", "clear log") + "  " +
+			components.Help("esc", "back"),
+		Viewport: &a.viewport,
+	})
+
+	if a.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, a.form, a.state)
+	}
+
+	if a.confirm.Visible {
+		rendered = components.RenderOverlay(rendered, a.confirm.View(), a.state)
+	}
+
+	if a.toast.Visible {
+		rendered = components.RenderOverlay(rendered, a.toast.View(), a.state)
+	}
+
+	return rendered
+}
+
+func (a *Alerts) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	a.viewport, cmd = a.viewport.Update(msg)
+	return cmd
+}
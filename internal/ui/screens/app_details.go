@@ -0,0 +1,306 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type AppDetails struct {
+	state *state.AppState
+	pkg   string
+
+	loading bool
+	details *adb.PackageDetails
+	err     error
+
+	certsLoading bool
+	certs        []apk.Certificate
+	certsErr     error
+
+	toast    components.Toast
+	viewport viewport.Model
+}
+
+func NewAppDetails(state *state.AppState) *AppDetails {
+	return &AppDetails{
+		state:    state,
+		pkg:      state.SelectedPackage,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (a *AppDetails) Init() tea.Cmd {
+	if !a.state.HasDevice() || a.pkg == "" {
+		return nil
+	}
+	return a.load()
+}
+
+func (a *AppDetails) load() tea.Cmd {
+	serial := a.state.DeviceSerial()
+	a.loading = true
+	a.certsLoading = true
+	a.err = nil
+	a.certsErr = nil
+	return tea.Batch(
+		adb.GetPackageDetailsCmd(serial, a.pkg),
+		adb.GetPackageCertificatesCmd(serial, a.pkg),
+	)
+}
+
+func (a *AppDetails) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	a.toast.Update(msg)
+
+	switch msg := msg.(type) {
+	case adb.PackageDetailsMsg:
+		if msg.Package != a.pkg {
+			return a, nil
+		}
+		a.loading = false
+		a.err = msg.Error
+		if msg.Error == nil {
+			a.details = &msg.Details
+		}
+
+	case adb.PackageCertificatesMsg:
+		if msg.Package != a.pkg {
+			return a, nil
+		}
+		a.certsLoading = false
+		a.certs = msg.Certificates
+		a.certsErr = msg.Error
+
+	case adb.AppActionResultMsg:
+		var cmd tea.Cmd
+		a.toast, cmd = components.ShowToast(
+			msg.Action+" successful",
+			false,
+			2*time.Second,
+		)
+		return a, cmd
+
+	case adb.AppActionErrorMsg:
+		var cmd tea.Cmd
+		a.toast, cmd = components.ShowToast(
+			msg.Action+" failed: "+msg.Error.Error(),
+			true,
+			3*time.Second,
+		)
+		return a, cmd
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "l":
+			if a.pkg != "" {
+				return a, adb.LaunchAppCmd(a.state.DeviceSerial(), a.pkg, a.state.SelectedUser)
+			}
+		case "p":
+			if a.pkg != "" {
+				return a, func() tea.Msg {
+					return navigation.SwitchScreenMsg{Screen: "permissions"}
+				}
+			}
+		case "c":
+			if a.pkg != "" {
+				return a, func() tea.Msg {
+					return navigation.SwitchScreenMsg{Screen: "components"}
+				}
+			}
+		case "f":
+			if a.pkg != "" {
+				return a, func() tea.Msg {
+					return navigation.SwitchScreenMsg{Screen: "app_files"}
+				}
+			}
+		case "a":
+			if a.pkg != "" {
+				return a, func() tea.Msg {
+					return navigation.SwitchScreenMsg{Screen: "app_links"}
+				}
+			}
+		case "r":
+			if a.state.HasDevice() && a.pkg != "" {
+				return a, a.load()
+			}
+		case "esc":
+			return a, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "apps"}
+			}
+		default:
+			return a, a.updateViewport(msg)
+		}
+	}
+
+	return a, nil
+}
+
+func (a *AppDetails) View() string {
+	if !a.state.HasDevice() {
+		return components.RenderNoDevice(a.state, "App Details")
+	}
+
+	maxWidth := a.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	static.WriteString(components.TitleStyle.Render(a.pkg) + "\n")
+
+	var body strings.Builder
+
+	switch {
+	case a.loading:
+		body.WriteString(components.StatusMuted.Render("Loading package details..."))
+	case a.err != nil:
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render(a.err.Error())))
+	case a.details != nil:
+		body.WriteString(a.renderDetails(*a.details, truncStyle))
+	}
+
+	footer := components.Help("l", "launch") + "  " +
+		components.Help("p", "permissions") + "  " +
+		components.Help("c", "components") + "  " +
+		components.Help("f", "files") + "  " +
+		components.Help("a", "app links") + "  " +
+		components.Help("r", "reload") + "  " +
+		components.Help("↑/↓", "scroll") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(a.state, components.LayoutWithScrollProps{
+		Title:             "App Details",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &a.viewport,
+	})
+
+	if a.toast.Visible {
+		rendered = components.RenderOverlay(rendered, a.toast.View(), a.state)
+	}
+
+	return rendered
+}
+
+func (a *AppDetails) renderDetails(d adb.PackageDetails, truncStyle lipgloss.Style) string {
+	var out strings.Builder
+
+	version := valueOrDash(d.VersionName)
+	if d.VersionCode != "" {
+		version += components.StatusMuted.Render(" (" + d.VersionCode + ")")
+	}
+
+	out.WriteString(components.TitleStyle.Render("Package") + "\n")
+	out.WriteString(truncStyle.Render(components.KeyValueList([]components.KeyValueRow{
+		{Key: "  Version:       ", Value: version},
+		{Key: "  Min SDK:       ", Value: valueOrDash(d.MinSDK)},
+		{Key: "  Target SDK:    ", Value: valueOrDash(d.TargetSDK)},
+		{Key: "  UID:           ", Value: valueOrDash(d.UID)},
+		{Key: "  ABI:           ", Value: valueOrDash(d.PrimaryABI)},
+		{Key: "  Installer:     ", Value: valueOrDash(d.Installer)},
+		{Key: "  Users:         ", Value: installedUsers(d.InstalledUsers)},
+		{Key: "  First install: ", Value: valueOrDash(d.FirstInstallTime)},
+		{Key: "  Last update:   ", Value: valueOrDash(d.LastUpdateTime)},
+		{Key: "  Code path:     ", Value: valueOrDash(d.CodePath)},
+		{Key: "  Data dir:      ", Value: valueOrDash(d.DataDir)},
+	})))
+
+	out.WriteString("\n" + components.TitleStyle.Render("Flags") + "\n")
+	out.WriteString("  " + flagBadge("debuggable", d.Debuggable()) + "  " +
+		flagBadge("allowBackup", d.AllowBackup()) + "  " +
+		flagBadge("system", d.HasFlag("SYSTEM")) + "\n")
+	if len(d.Flags) > 0 {
+		out.WriteString(truncStyle.Render("  "+components.StatusMuted.Render(strings.Join(d.Flags, " "))) + "\n")
+	}
+	if len(d.PrivateFlags) > 0 {
+		out.WriteString(truncStyle.Render("  "+components.StatusMuted.Render(strings.Join(d.PrivateFlags, " "))) + "\n")
+	}
+
+	out.WriteString("\n" + components.TitleStyle.Render("Signing") + "\n")
+	switch {
+	case a.certsLoading:
+		out.WriteString(components.StatusMuted.Render("  Reading certificates from the APK...") + "\n")
+	case a.certsErr != nil:
+		out.WriteString(truncStyle.Render(components.ErrorStyle.Render("  "+a.certsErr.Error())) + "\n")
+	default:
+		for _, c := range a.certs {
+			out.WriteString(truncStyle.Render(fmt.Sprintf(
+				"  %s %s",
+				components.StatusMuted.Render("["+c.Scheme+"]"),
+				valueOrDash(c.Subject),
+			)) + "\n")
+			out.WriteString(truncStyle.Render("  "+components.StatusMuted.Render("SHA-256: ")+c.SHA256) + "\n")
+			out.WriteString(truncStyle.Render("  "+components.StatusMuted.Render("SHA-1:   ")+c.SHA1) + "\n")
+		}
+	}
+
+	granted := 0
+	var perms strings.Builder
+	for _, name := range d.RequestedPermissions {
+		ok, known := d.PermissionGranted(name, a.state.SelectedUser)
+		mark := components.StatusMuted.Render("–")
+		switch {
+		case ok:
+			granted++
+			mark = components.StatusConnected.Render("✓")
+		case known:
+			mark = components.ErrorStyle.Render("✗")
+		}
+		perms.WriteString(truncStyle.Render("  "+mark+" "+name) + "\n")
+	}
+
+	out.WriteString("\n" + components.TitleStyle.Render(fmt.Sprintf(
+		"Requested Permissions (%d/%d granted)", granted, len(d.RequestedPermissions),
+	)) + "\n")
+	if len(d.RequestedPermissions) == 0 {
+		out.WriteString(components.StatusMuted.Render("  None") + "\n")
+	}
+	out.WriteString(perms.String())
+
+	out.WriteString("\n" + components.TitleStyle.Render("Declared Permissions") + "\n")
+	if len(d.DeclaredPermissions) == 0 {
+		out.WriteString(components.StatusMuted.Render("  None") + "\n")
+	}
+	for _, name := range d.DeclaredPermissions {
+		out.WriteString(truncStyle.Render("  "+name) + "\n")
+	}
+
+	return out.String()
+}
+
+func installedUsers(users []int) string {
+	if len(users) == 0 {
+		return valueOrDash("")
+	}
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = strconv.Itoa(u)
+	}
+	return strings.Join(ids, ", ")
+}
+
+func flagBadge(name string, set bool) string {
+	if set {
+		return components.WarningStyle.Render("● " + name)
+	}
+	return components.StatusMuted.Render("○ " + name)
+}
+
+func (a *AppDetails) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	a.viewport, cmd = a.viewport.Update(msg)
+	return cmd
+}
@@ -0,0 +1,424 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const viewAction = "android.intent.action.VIEW"
+
+// AppLinks shows a package's app link domains with their verification and
+// user selection state, and the URL patterns of its VIEW intent filters,
+// and fires test URLs to see which app opens them.
+type AppLinks struct {
+	state *state.AppState
+	pkg   string
+
+	loading     bool
+	links       *adb.AppLinks
+	linksErr    error
+	manifest    *apk.Manifest
+	manifestErr error
+	cursor      int
+
+	testURL    string
+	testResult *adb.StartResult
+	testErr    error
+	testing    bool
+
+	form     components.FormModal
+	toast    components.Toast
+	viewport viewport.Model
+}
+
+// appLinkRow is either a domain from `pm get-app-links` or a URL pattern
+// from the manifest.
+type appLinkRow struct {
+	domain     *adb.AppLinkDomain
+	pattern    string
+	activity   string
+	autoVerify bool
+}
+
+func NewAppLinks(state *state.AppState) *AppLinks {
+	return &AppLinks{
+		state:    state,
+		pkg:      state.SelectedPackage,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (l *AppLinks) Init() tea.Cmd {
+	if !l.state.HasDevice() || l.pkg == "" {
+		return nil
+	}
+	l.loading = true
+	return tea.Batch(l.loadLinks(), adb.GetPackageManifestCmd(l.state.DeviceSerial(), l.pkg))
+}
+
+func (l *AppLinks) loadLinks() tea.Cmd {
+	return adb.GetAppLinksCmd(l.state.DeviceSerial(), l.pkg, l.state.SelectedUser)
+}
+
+func (l *AppLinks) rows() []appLinkRow {
+	var rows []appLinkRow
+	if l.links != nil {
+		for i := range l.links.Domains {
+			rows = append(rows, appLinkRow{domain: &l.links.Domains[i]})
+		}
+	}
+	if l.manifest != nil {
+		for _, comp := range l.manifest.ComponentsOf(apk.Activity) {
+			for _, f := range comp.Filters {
+				if !f.Has(viewAction) {
+					continue
+				}
+				for _, pattern := range f.URLPatterns() {
+					rows = append(rows, appLinkRow{
+						pattern:    pattern,
+						activity:   shortClass(comp.Name),
+						autoVerify: f.AutoVerify,
+					})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func (l *AppLinks) selected() *appLinkRow {
+	rows := l.rows()
+	if l.cursor >= len(rows) {
+		return nil
+	}
+	return &rows[l.cursor]
+}
+
+func (l *AppLinks) InputActive() bool {
+	return l.form.Visible
+}
+
+func (l *AppLinks) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	l.toast.Update(msg)
+	serial := l.state.DeviceSerial()
+
+	if l.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			l.form.Hide()
+			if len(msg.Values) == 0 || strings.TrimSpace(msg.Values[0]) == "" {
+				return l, nil
+			}
+			l.testURL = strings.TrimSpace(msg.Values[0])
+			l.testResult = nil
+			l.testErr = nil
+			l.testing = true
+			return l, adb.SendIntentCmd(serial, viewAction, l.testURL, nil)
+		case components.FormCancelMsg:
+			l.form.Hide()
+			return l, nil
+		}
+		return l, l.form.Update(msg)
+	}
+
+	switch msg := msg.(type) {
+	case adb.AppLinksMsg:
+		if msg.Package != l.pkg {
+			return l, nil
+		}
+		l.loading = false
+		l.linksErr = msg.Error
+		if msg.Error == nil {
+			l.links = &msg.Links
+		}
+		l.clampCursor()
+
+	case adb.PackageManifestMsg:
+		if msg.Package != l.pkg {
+			return l, nil
+		}
+		l.manifestErr = msg.Error
+		if msg.Error == nil {
+			l.manifest = &msg.Manifest
+		}
+		l.clampCursor()
+
+	case adb.IntentResultMsg:
+		l.testing = false
+		l.testResult = &msg.Result
+
+	case adb.IntentErrorMsg:
+		l.testing = false
+		l.testErr = msg.Error
+
+	case adb.AppActionResultMsg:
+		var cmd tea.Cmd
+		l.toast, cmd = components.ShowToast(msg.Action+" successful", false, 2*time.Second)
+		return l, tea.Batch(cmd, l.loadLinks())
+
+	case adb.AppActionErrorMsg:
+		var cmd tea.Cmd
+		l.toast, cmd = components.ShowToast(msg.Action+" failed: "+msg.Error.Error(), true, 3*time.Second)
+		return l, cmd
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if l.cursor > 0 {
+				l.cursor--
+				l.ensureCursorVisible()
+			}
+		case "down", "j":
+			if l.cursor < len(l.rows())-1 {
+				l.cursor++
+				l.ensureCursorVisible()
+			}
+		case "enter", "t":
+			url := ""
+			if row := l.selected(); row != nil {
+				url = sampleURL(*row)
+			}
+			l.form.Show("Open URL", []components.FormField{
+				{Label: "URL", Value: url, Placeholder: "https://example.com/path"},
+			})
+		case "s":
+			if row := l.selected(); row != nil && row.domain != nil {
+				return l, adb.SetAppLinkSelectionCmd(serial, l.pkg, l.state.SelectedUser, row.domain.Domain, !row.domain.Selected)
+			}
+		case "a":
+			if l.links != nil {
+				return l, adb.SetAppLinksAllowedCmd(serial, l.pkg, l.state.SelectedUser, !l.links.Allowed)
+			}
+		case "v":
+			return l, adb.ReverifyAppLinksCmd(serial, l.pkg)
+		case "r":
+			if l.state.HasDevice() && l.pkg != "" {
+				return l, l.Init()
+			}
+		case "esc":
+			return l, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "apps"}
+			}
+		default:
+			var cmd tea.Cmd
+			l.viewport, cmd = l.viewport.Update(msg)
+			return l, cmd
+		}
+	}
+
+	return l, nil
+}
+
+// sampleURL turns a row into a URL to test, dropping the wildcards of
+// domains and path patterns.
+func sampleURL(row appLinkRow) string {
+	if row.domain != nil {
+		return "https://" + strings.TrimPrefix(row.domain.Domain, "*.") + "/"
+	}
+	url := strings.ReplaceAll(row.pattern, ".*", "")
+	return strings.ReplaceAll(url, "*", "")
+}
+
+func (l *AppLinks) clampCursor() {
+	if n := len(l.rows()); l.cursor >= n {
+		l.cursor = max(n-1, 0)
+	}
+}
+
+// ensureCursorVisible accounts for the section headings above and between
+// the domains and the URL patterns.
+func (l *AppLinks) ensureCursorVisible() {
+	line := 1 + l.cursor
+	if domains := l.domainCount(); l.cursor >= domains {
+		line += 2
+		if domains == 0 {
+			line++
+		}
+	}
+	ensureViewportLineVisible(&l.viewport, line)
+}
+
+func (l *AppLinks) domainCount() int {
+	if l.links == nil {
+		return 0
+	}
+	return len(l.links.Domains)
+}
+
+func (l *AppLinks) View() string {
+	if !l.state.HasDevice() {
+		return components.RenderNoDevice(l.state, "App Links")
+	}
+
+	maxWidth := l.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	static.WriteString(components.TitleStyle.Render(l.pkg) + "\n")
+
+	if l.links != nil {
+		verified := 0
+		for _, d := range l.links.Domains {
+			if d.Verified() {
+				verified++
+			}
+		}
+		allowed := components.StatusConnected.Render("allowed")
+		if !l.links.Allowed {
+			allowed = components.ErrorStyle.Render("not allowed")
+		}
+		rows := []components.KeyValueRow{
+			{Key: "  Link handling: ", Value: allowed + components.StatusMuted.Render(fmt.Sprintf(" for user %d", l.state.SelectedUser))},
+			{Key: "  Verified:      ", Value: fmt.Sprintf("%d of %d domain(s)", verified, len(l.links.Domains))},
+		}
+		if l.links.LegacyStatus != "" {
+			rows = append(rows, components.KeyValueRow{Key: "  Status:        ", Value: l.links.LegacyStatus})
+		}
+		static.WriteString(truncStyle.Render(components.KeyValueList(rows)) + "\n")
+	}
+
+	switch {
+	case l.testing:
+		static.WriteString(components.WarningStyle.Render("  ● Opening "+l.testURL+"...") + "\n")
+	case l.testErr != nil:
+		static.WriteString(truncStyle.Render(components.ErrorStyle.Render("  ✗ "+l.testURL+": "+l.testErr.Error())) + "\n")
+	case l.testResult != nil:
+		static.WriteString(l.renderTestResult(truncStyle))
+	}
+
+	var body strings.Builder
+	if l.loading && l.links == nil && l.manifest == nil {
+		body.WriteString(components.StatusMuted.Render("Loading app links..."))
+	} else {
+		body.WriteString(l.renderRows(truncStyle))
+	}
+
+	footer := components.Help("↑/↓", "navigate") + "  " +
+		components.Help("enter", "test url") + "  " +
+		components.Help("s", "toggle selection") + "  " +
+		components.Help("a", "allow/disallow links") + "  " +
+		components.Help("v", "re-verify") + "  " +
+		components.Help("r", "reload") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(l.state, components.LayoutWithScrollProps{
+		Title:             "App Links",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &l.viewport,
+	})
+
+	if l.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, l.form, l.state)
+	}
+
+	if l.toast.Visible {
+		rendered = components.RenderOverlay(rendered, l.toast.View(), l.state)
+	}
+
+	return rendered
+}
+
+// renderTestResult tells whether the test URL opened the package or was
+// handled by another app, such as the browser.
+func (l *AppLinks) renderTestResult(truncStyle lipgloss.Style) string {
+	r := l.testResult
+	line := "  " + l.testURL + " → "
+	var out string
+	switch {
+	case r.Error != "":
+		return truncStyle.Render(components.ErrorStyle.Render(line+r.Error)) + "\n"
+	case r.Activity == "":
+		out = components.WarningStyle.Render(line + "no activity reported")
+	case strings.HasPrefix(r.Activity, l.pkg+"/"):
+		out = components.StatusConnected.Render(line + r.Activity)
+	default:
+		out = components.WarningStyle.Render(line + r.Activity + " (another app)")
+	}
+	out = truncStyle.Render(out) + "\n"
+	if r.Warning != "" {
+		out += truncStyle.Render(components.WarningStyle.Render("  ! "+r.Warning)) + "\n"
+	}
+	return out
+}
+
+func (l *AppLinks) renderRows(truncStyle lipgloss.Style) string {
+	var out strings.Builder
+	rows := l.rows()
+
+	domains := l.domainCount()
+
+	out.WriteString(components.TitleStyle.Render("Domains") + "\n")
+	switch {
+	case l.linksErr != nil:
+		out.WriteString(truncStyle.Render(components.ErrorStyle.Render("  "+l.linksErr.Error())) + "\n")
+	case domains == 0:
+		out.WriteString(components.StatusMuted.Render("  No domains reported by pm get-app-links") + "\n")
+	}
+
+	for i, row := range rows {
+		if i == domains {
+			out.WriteString("\n" + components.TitleStyle.Render("VIEW Intent Filters") + "\n")
+		}
+
+		prefix, style := "  ", components.ListItemStyle
+		if i == l.cursor {
+			prefix, style = "› ", components.ListItemSelectedStyle
+		}
+
+		var line string
+		if d := row.domain; d != nil {
+			selection := components.StatusMuted.Render("not selected")
+			if d.Selected {
+				selection = components.StatusConnected.Render("selected    ")
+			}
+			line = prefix + renderDomainState(d) + " " + selection + " " + style.Render(d.Domain)
+		} else {
+			line = prefix + style.Render(row.pattern) + components.StatusMuted.Render(" → "+row.activity)
+			if row.autoVerify {
+				line += " " + components.StatusConnected.Render("autoVerify")
+			}
+		}
+		out.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	if len(rows) == domains {
+		out.WriteString("\n" + components.TitleStyle.Render("VIEW Intent Filters") + "\n")
+		switch {
+		case l.manifestErr != nil:
+			out.WriteString(truncStyle.Render(components.ErrorStyle.Render("  "+l.manifestErr.Error())) + "\n")
+		case l.manifest == nil:
+			out.WriteString(components.StatusMuted.Render("  Reading the manifest from the APK...") + "\n")
+		default:
+			out.WriteString(components.StatusMuted.Render("  No VIEW intent filters with URLs") + "\n")
+		}
+	}
+
+	return out.String()
+}
+
+func renderDomainState(d *adb.AppLinkDomain) string {
+	label := fmt.Sprintf("%-10s", valueOrDash(d.State))
+	switch {
+	case d.Verified():
+		return components.StatusConnected.Render(label)
+	case d.State == "" || d.State == "none":
+		return components.StatusMuted.Render(label)
+	}
+	return components.ErrorStyle.Render(label)
+}
@@ -1,13 +1,20 @@
 package screens
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/debloat"
 	"github.com/SakshhamTheCoder/adbt/internal/state"
 	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,11 +25,28 @@ const (
 	FilterAll AppFilter = iota
 	FilterUser
 	FilterSystem
+	FilterDisabled
+	FilterDebuggable
+	FilterUpdated
 )
 
 type AppFilter int
 
-var filterNames = []string{"All", "User", "System"}
+var filterNames = []string{"All", "User", "System", "Disabled", "Debuggable", "Updated"}
+
+// recentUpdateWindow is how far back the Updated filter looks.
+const recentUpdateWindow = 7 * 24 * time.Hour
+
+const (
+	SortName AppSort = iota
+	SortSize
+	SortInstalled
+	SortLastUsed
+)
+
+type AppSort int
+
+var sortNames = []string{"name", "size", "install date", "last used"}
 
 type AppManager struct {
 	state   *state.AppState
@@ -33,16 +57,68 @@ type AppManager struct {
 	search components.SearchState
 
 	filterType AppFilter
+	sortBy     AppSort
+
+	// stats fill in sizes, dates and flags after the list has loaded.
+	stats        map[string]adb.AppStats
+	statsLoading bool
+
+	// labels are read from the APKs in batches after the list has loaded.
+	labels        map[string]string
+	labelsLoading bool
 
 	viewport viewport.Model
 
 	confirm components.ConfirmPrompt
 	toast   components.Toast
 	pending string
+	// pendingPkg is the app the pending single-app action was confirmed
+	// for. The list can reload and move the cursor while the prompt is up.
+	pendingPkg string
 
 	installForm components.FormModal
+	install     *installProgress
+	installErr  error
+
+	extractForm components.FormModal
+	restoreForm components.FormModal
+	extracting  string
+
+	users    []adb.User
+	userForm components.FormModal
+
+	// marked are the packages that force stop, clear data, uninstall,
+	// disable and extract apply to, across filters.
+	marked map[string]bool
+	// batchPkgs are the packages of the batch action waiting for
+	// confirmation, batchDir the extract destination.
+	batchPkgs    []string
+	batchDir     string
+	batchRunning string
+	batchResult  *adb.BatchDoneMsg
+}
+
+// installProgress tracks a running install. adb reports no byte progress,
+// so the stage and elapsed time are shown instead.
+type installProgress struct {
+	stage   string
+	started time.Time
+	plan    adb.InstallPlan
+	// check is set while the preview waits for confirmation.
+	check *adb.InstallCheck
 }
 
+type installTickMsg struct{}
+
+var yesNoOptions = []string{"No", "Yes"}
+
+// debloatPending prefixes pending confirm actions that change a package's
+// state for the user.
+const debloatPending = "debloat:"
+
+// batchPending prefixes pending confirm actions on every marked app.
+const batchPending = "batch:"
+
 func NewAppManager(state *state.AppState) *AppManager {
 	return &AppManager{
 		state:    state,
@@ -56,7 +132,50 @@ func (a *AppManager) Init() tea.Cmd {
 	}
 
 	a.loading = true
-	return adb.ListAppsCmd(a.state.DeviceSerial())
+	return tea.Batch(a.listApps(), a.loadStats(), adb.ListUsersCmd(a.state.DeviceSerial()))
+}
+
+func (a *AppManager) listApps() tea.Cmd {
+	return adb.ListAppsCmd(a.state.DeviceSerial(), a.state.SelectedUser)
+}
+
+// loadStats collects the slower per-app details. Usage is per user, so
+// they are reloaded with the list on a user switch.
+func (a *AppManager) loadStats() tea.Cmd {
+	a.statsLoading = true
+	return adb.GetAppStatsCmd(a.state.DeviceSerial(), a.state.SelectedUser)
+}
+
+// loadLabels starts reading the labels of the listed apps, unless that is
+// already under way. Labels do not depend on the user.
+func (a *AppManager) loadLabels() tea.Cmd {
+	if a.labelsLoading || len(a.apps) == 0 {
+		return nil
+	}
+	a.labelsLoading = true
+	return adb.GetAppLabelsCmd(a.state.DeviceSerial(), append([]adb.App(nil), a.apps...))
+}
+
+func (a *AppManager) selectedUser() *adb.User {
+	for i := range a.users {
+		if a.users[i].ID == a.state.SelectedUser {
+			return &a.users[i]
+		}
+	}
+	return nil
+}
+
+// userLabel names the selected user, falling back to its id until the user
+// list has loaded.
+func (a *AppManager) userLabel() string {
+	u := a.selectedUser()
+	if u == nil {
+		return strconv.Itoa(a.state.SelectedUser)
+	}
+	if u.WorkProfile() {
+		return u.Label() + " · work profile"
+	}
+	return u.Label()
 }
 
 func (a *AppManager) filteredApps() []adb.App {
@@ -73,10 +192,23 @@ func (a *AppManager) filteredApps() []adb.App {
 			if !app.IsSystem {
 				continue
 			}
+		case FilterDisabled:
+			if !app.Disabled {
+				continue
+			}
+		case FilterDebuggable:
+			if !app.Debuggable {
+				continue
+			}
+		case FilterUpdated:
+			if app.Updated.IsZero() || time.Since(app.Updated) > recentUpdateWindow {
+				continue
+			}
 		}
 
 		if a.search.Query != "" {
-			if !strings.Contains(strings.ToLower(app.PackageName), lowerSearch) {
+			if !strings.Contains(strings.ToLower(app.PackageName), lowerSearch) &&
+				!strings.Contains(strings.ToLower(app.Label), lowerSearch) {
 				continue
 			}
 		}
@@ -84,9 +216,29 @@ func (a *AppManager) filteredApps() []adb.App {
 		filtered = append(filtered, app)
 	}
 
+	sortApps(filtered, a.sortBy)
 	return filtered
 }
 
+// sortApps orders apps by label, or largest, newest and most recently used
+// first. Apps without the value keep name order at the end.
+func sortApps(apps []adb.App, by AppSort) {
+	sort.SliceStable(apps, func(i, j int) bool {
+		switch by {
+		case SortSize:
+			return apps[i].Size > apps[j].Size
+		case SortInstalled:
+			return apps[i].Installed.After(apps[j].Installed)
+		case SortLastUsed:
+			return apps[i].LastUsed.After(apps[j].LastUsed)
+		}
+		if a, b := strings.ToLower(apps[i].Name()), strings.ToLower(apps[j].Name()); a != b {
+			return a < b
+		}
+		return apps[i].PackageName < apps[j].PackageName
+	})
+}
+
 func (a *AppManager) selectedApp() *adb.App {
 	filtered := a.filteredApps()
 	if len(filtered) == 0 || a.cursor >= len(filtered) {
@@ -95,32 +247,75 @@ func (a *AppManager) selectedApp() *adb.App {
 	return &filtered[a.cursor]
 }
 
+func (a *AppManager) InputActive() bool {
+	return a.search.Active || a.installForm.Visible || a.extractForm.Visible ||
+		a.restoreForm.Visible || a.userForm.Visible
+}
+
 func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	a.toast.Update(msg)
 
+	// Dialogs only take keys. Loads, installs and batch results fall
+	// through to the switch below, so they are not lost while one is open.
 	if a.installForm.Visible {
 		switch msg := msg.(type) {
 		case components.FormSubmitMsg:
-			values := msg.Values
 			a.installForm.Hide()
-			if len(values) > 0 && values[0] != "" {
-				var toastCmd tea.Cmd
-				a.toast, toastCmd = components.ShowToast(
-					"Installing APK...",
-					false,
-					2*time.Second,
-				)
-				return a, tea.Batch(
-					toastCmd,
-					adb.InstallAppCmd(a.state.DeviceSerial(), values[0]),
-				)
+			return a, a.startInstall(msg.Values)
+		case components.FormCancelMsg:
+			a.installForm.Hide()
+			return a, nil
+		}
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return a, a.installForm.Update(msg)
+		}
+	}
+
+	if a.extractForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			a.extractForm.Hide()
+			if a.batchPkgs != nil {
+				a.confirmBatchExtract(msg.Values)
+				return a, nil
 			}
+			return a, a.startExtract(msg.Values)
+		case components.FormCancelMsg:
+			a.extractForm.Hide()
+			a.batchPkgs = nil
 			return a, nil
+		}
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return a, a.extractForm.Update(msg)
+		}
+	}
+
+	if a.restoreForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			a.restoreForm.Hide()
+			return a, a.startRestore(msg.Values)
 		case components.FormCancelMsg:
-			a.installForm.Hide()
+			a.restoreForm.Hide()
 			return a, nil
 		}
-		return a, a.installForm.Update(msg)
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return a, a.restoreForm.Update(msg)
+		}
+	}
+
+	if a.userForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			a.userForm.Hide()
+			return a, a.switchUser(msg.Values)
+		case components.FormCancelMsg:
+			a.userForm.Hide()
+			return a, nil
+		}
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return a, a.userForm.Update(msg)
+		}
 	}
 
 	if a.confirm.Visible {
@@ -128,28 +323,49 @@ func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case components.ConfirmYesMsg:
 			a.confirm.Hide()
-			app := a.selectedApp()
-			if app == nil {
+			if a.pending == "install" {
+				a.pending = ""
+				return a, a.confirmInstall()
+			}
+			if action, ok := strings.CutPrefix(a.pending, batchPending); ok {
+				a.pending = ""
+				return a, a.runBatch(action)
+			}
+			pkg := a.pendingPkg
+			a.pendingPkg = ""
+			if pkg == "" {
 				return a, nil
 			}
 			serial := a.state.DeviceSerial()
+			user := a.state.SelectedUser
 			switch a.pending {
 			case "uninstall":
-				return a, adb.UninstallAppCmd(serial, app.PackageName)
+				return a, adb.UninstallAppCmd(serial, pkg, user)
 			case "clear data":
-				return a, adb.ClearAppDataCmd(serial, app.PackageName)
+				return a, adb.ClearAppDataCmd(serial, pkg, user)
 			case "force_stop":
-				return a, adb.ForceStopAppCmd(serial, app.PackageName)
+				return a, adb.ForceStopAppCmd(serial, pkg, user)
+			}
+			if action, ok := strings.CutPrefix(a.pending, debloatPending); ok {
+				return a, adb.DebloatCmd(serial, user, pkg, debloat.Action(action), "")
 			}
 			return a, nil
 
 		case components.ConfirmNoMsg:
 			a.confirm.Hide()
+			if a.pending == "install" && a.install != nil {
+				a.install.plan.Cleanup()
+				a.install = nil
+			}
 			a.pending = ""
+			a.pendingPkg = ""
+			a.batchPkgs = nil
 			return a, tea.Batch()
 		}
 
-		return a, a.confirm.Update(msg)
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return a, a.confirm.Update(msg)
+		}
 	}
 
 	switch msg := msg.(type) {
@@ -157,8 +373,53 @@ func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case adb.AppsLoadedMsg:
 		a.loading = false
 		a.apps = msg.Apps
+		adb.ApplyAppStats(a.apps, a.stats)
+		adb.ApplyAppLabels(a.apps, a.labels)
+		a.pruneMarks()
 		a.cursor = 0
 		a.gotoTop()
+		a.restoreSelection()
+		return a, a.loadLabels()
+
+	case adb.AppLabelsLoadedMsg:
+		if msg.Serial != a.state.DeviceSerial() {
+			return a, nil
+		}
+		if a.labels == nil {
+			a.labels = map[string]string{}
+		}
+		for pkg, label := range msg.Labels {
+			a.labels[pkg] = label
+		}
+		selected := a.selectedApp()
+		adb.ApplyAppLabels(a.apps, a.labels)
+		if selected != nil {
+			a.keepSelection(selected.PackageName)
+		}
+		if len(msg.Pending) > 0 {
+			return a, adb.GetAppLabelsCmd(msg.Serial, msg.Pending)
+		}
+		a.labelsLoading = false
+		return a, nil
+
+	case adb.AppStatsLoadedMsg:
+		if msg.Serial != a.state.DeviceSerial() || msg.User != a.state.SelectedUser {
+			return a, nil
+		}
+		a.statsLoading = false
+		a.stats = msg.Stats
+		selected := a.selectedApp()
+		adb.ApplyAppStats(a.apps, a.stats)
+		if selected != nil {
+			a.keepSelection(selected.PackageName)
+		}
+		return a, nil
+
+	case adb.UsersLoadedMsg:
+		if msg.Serial != a.state.DeviceSerial() || msg.Error != nil {
+			return a, nil
+		}
+		a.users = msg.Users
 		return a, nil
 
 	case adb.AppsLoadErrorMsg:
@@ -171,6 +432,104 @@ func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		)
 		return a, cmd
 
+	case adb.InstallPreparedMsg:
+		if a.install == nil {
+			msg.Plan.Cleanup()
+			return a, nil
+		}
+		if msg.Error != nil {
+			return a, a.finishInstall(msg.Error)
+		}
+		a.install.plan = msg.Plan
+		a.install.stage = fmt.Sprintf(
+			"%s: %d APK(s), %s",
+			a.install.stage,
+			len(msg.Plan.APKs),
+			adb.FormatFileSize(fmt.Sprint(msg.Plan.TotalBytes)),
+		)
+		if len(msg.Plan.OBBs) > 0 {
+			a.install.stage += fmt.Sprintf(" and %d OBB file(s)", len(msg.Plan.OBBs))
+		}
+		if len(msg.Plan.Skipped) > 0 {
+			a.install.stage += fmt.Sprintf(" (%d split(s) not for this device)", len(msg.Plan.Skipped))
+		}
+		return a, adb.CheckInstallCmd(a.state.DeviceSerial(), msg.Plan)
+
+	case adb.InstallCheckedMsg:
+		if a.install == nil {
+			msg.Plan.Cleanup()
+			return a, nil
+		}
+		a.install.check = &msg.Check
+		a.pending = "install"
+
+		prompt := "Install " + valueOrDash(msg.Check.APK.Manifest.Package) + "?"
+		if n := len(msg.Check.Warnings); n > 0 {
+			prompt = fmt.Sprintf("Install %s despite %d warning(s)?", valueOrDash(msg.Check.APK.Manifest.Package), n)
+		}
+		a.confirm.Show(prompt)
+		return a, nil
+
+	case adb.InstallResultMsg:
+		if a.install == nil {
+			return a, nil
+		}
+		return a, a.finishInstall(msg.Error)
+
+	case adb.DebloatMsg:
+		var cmd tea.Cmd
+		switch {
+		case msg.Error != nil:
+			a.toast, cmd = components.ShowToast(
+				string(msg.Action)+" failed: "+msg.Error.Error(),
+				true,
+				3*time.Second,
+			)
+			return a, cmd
+		case msg.JournalError != nil:
+			a.toast, cmd = components.ShowToast(
+				msg.Err().Error(),
+				true,
+				3*time.Second,
+			)
+		default:
+			a.toast, cmd = components.ShowToast(
+				string(msg.Action)+" successful",
+				false,
+				2*time.Second,
+			)
+		}
+		a.state.SelectPackage(msg.Package)
+		return a, tea.Batch(cmd, a.listApps())
+
+	case adb.ExtractAPKMsg:
+		a.extracting = ""
+		if msg.Error != nil {
+			var cmd tea.Cmd
+			a.toast, cmd = components.ShowToast(
+				"extract failed: "+msg.Error.Error(),
+				true,
+				3*time.Second,
+			)
+			return a, cmd
+		}
+		var cmd tea.Cmd
+		a.toast, cmd = components.ShowToast(
+			fmt.Sprintf("Extracted %d APK(s) to %s", len(msg.Manifest.Files), msg.Dir),
+			false,
+			3*time.Second,
+		)
+		return a, cmd
+
+	case adb.BatchDoneMsg:
+		return a, a.finishBatch(msg)
+
+	case installTickMsg:
+		if a.install == nil {
+			return a, nil
+		}
+		return a, installTickCmd()
+
 	case adb.AppActionResultMsg:
 		var cmd tea.Cmd
 		a.toast, cmd = components.ShowToast(
@@ -181,7 +540,7 @@ func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Action == "uninstall" || msg.Action == "install" {
 			return a, tea.Batch(
 				cmd,
-				adb.ListAppsCmd(a.state.DeviceSerial()),
+				a.listApps(),
 			)
 		}
 		return a, cmd
@@ -221,39 +580,172 @@ func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.ensureCursorVisible()
 			}
 
-		case "enter", "l":
+		case "enter":
+			if app := a.selectedApp(); app != nil {
+				return a, a.openPackageScreen(app.PackageName, "app_details")
+			}
+
+		case "l":
 			if app := a.selectedApp(); app != nil {
 				return a, adb.LaunchAppCmd(
 					a.state.DeviceSerial(),
 					app.PackageName,
+					a.state.SelectedUser,
 				)
 			}
 
-		case "s":
+		case " ":
 			if app := a.selectedApp(); app != nil {
-				a.pending = "force_stop"
-				a.confirm.Show("Force stop:\n" + app.PackageName)
+				a.toggleMark(app.PackageName)
+				if a.cursor < len(filtered)-1 {
+					a.cursor++
+					a.ensureCursorVisible()
+				}
+			}
+
+		case "a":
+			for _, app := range filtered {
+				a.setMark(app.PackageName, true)
+			}
+
+		case "v":
+			for _, app := range filtered {
+				a.toggleMark(app.PackageName)
+			}
+
+		case "s":
+			if len(a.marked) > 0 {
+				a.showBatchConfirm("force stop", a.markedApps())
+			} else if app := a.selectedApp(); app != nil {
+				a.confirmApp("force_stop", "Force stop:", app.PackageName)
 			}
 
 		case "u":
-			if app := a.selectedApp(); app != nil {
+			if len(a.marked) > 0 {
+				var apps []adb.App
+				for _, app := range a.markedApps() {
+					if !app.IsSystem {
+						apps = append(apps, app)
+					}
+				}
+				if len(apps) == 0 {
+					var cmd tea.Cmd
+					a.toast, cmd = components.ShowToast(
+						"Only system apps are marked, press h to remove them for the user one by one",
+						true,
+						2*time.Second,
+					)
+					return a, cmd
+				}
+				a.showBatchConfirm("uninstall", apps)
+			} else if app := a.selectedApp(); app != nil {
 				if app.IsSystem {
 					var cmd tea.Cmd
 					a.toast, cmd = components.ShowToast(
-						"Cannot uninstall system app",
+						"Cannot uninstall system app, press h to remove it for the user",
 						true,
 						2*time.Second,
 					)
 					return a, cmd
 				}
-				a.pending = "uninstall"
-				a.confirm.Show("Uninstall:\n" + app.PackageName)
+				a.confirmApp("uninstall", "Uninstall:", app.PackageName)
 			}
 
 		case "x":
+			if len(a.marked) > 0 {
+				a.showBatchConfirm("clear data", a.markedApps())
+			} else if app := a.selectedApp(); app != nil {
+				a.confirmApp("clear data", "Clear data:", app.PackageName)
+			}
+
+		case "z":
+			if len(a.marked) > 0 {
+				var apps []adb.App
+				for _, app := range a.markedApps() {
+					if !app.Uninstalled {
+						apps = append(apps, app)
+					}
+				}
+				if len(apps) > 0 {
+					a.showBatchConfirm(string(debloat.Disable), apps)
+				}
+			} else if app := a.selectedApp(); app != nil && !app.Uninstalled {
+				if app.Disabled {
+					a.confirmApp(debloatPending+string(debloat.Enable), "Enable:", app.PackageName)
+				} else {
+					a.confirmApp(debloatPending+string(debloat.Disable), "Disable for user "+a.userLabel()+":", app.PackageName)
+				}
+			}
+
+		case "h":
+			if app := a.selectedApp(); app != nil {
+				if app.Uninstalled {
+					a.confirmApp(debloatPending+string(debloat.Restore), "Reinstall for user "+a.userLabel()+":", app.PackageName)
+				} else {
+					a.confirmApp(debloatPending+string(debloat.Uninstall), "Uninstall for user "+a.userLabel()+" (keeps data, reversible):", app.PackageName)
+				}
+			}
+
+		case "D":
+			return a, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "debloat"}
+			}
+
+		case "b":
+			if app := a.selectedApp(); app != nil {
+				return a, a.openPackageScreen(app.PackageName, "benchmark")
+			}
+
+		case "t":
+			if app := a.selectedApp(); app != nil {
+				return a, a.openPackageScreen(app.PackageName, "trace")
+			}
+
+		case "p":
+			if app := a.selectedApp(); app != nil {
+				return a, a.openPackageScreen(app.PackageName, "permissions")
+			}
+
+		case "c":
+			if app := a.selectedApp(); app != nil {
+				return a, a.openPackageScreen(app.PackageName, "components")
+			}
+
+		case "f":
+			if app := a.selectedApp(); app != nil {
+				return a, a.openPackageScreen(app.PackageName, "app_files")
+			}
+
+		case "L":
 			if app := a.selectedApp(); app != nil {
-				a.pending = "clear data"
-				a.confirm.Show("Clear data:\n" + app.PackageName)
+				return a, a.openPackageScreen(app.PackageName, "app_links")
+			}
+
+		case "e":
+			if len(a.marked) > 0 {
+				// Marks always mean a batch, even while another one runs.
+				if a.batchRunning == "" {
+					a.batchPkgs = nil
+					for _, app := range a.markedApps() {
+						a.batchPkgs = append(a.batchPkgs, app.PackageName)
+					}
+					a.extractForm.Show(fmt.Sprintf("Extract APKs of %d App(s)", len(a.batchPkgs)), []components.FormField{
+						{Label: "Output Dir", Value: defaultExtractDir()},
+					})
+				}
+			} else if app := a.selectedApp(); app != nil && a.extracting == "" {
+				a.extractForm.Show("Extract APK", []components.FormField{
+					{Label: "Package", Value: app.PackageName},
+					{Label: "Output Dir", Value: defaultExtractDir()},
+				})
+			}
+
+		case "E":
+			if a.install == nil {
+				a.restoreForm.Show("Restore Extracted App", []components.FormField{
+					{Label: "Folder", Placeholder: "folder created by extract"},
+					{Label: "Downgrade (-d)", Type: components.FormFieldSelect, Options: yesNoOptions, Value: "No"},
+				})
 			}
 
 		case "/":
@@ -264,21 +756,48 @@ func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.loading = true
 				a.cursor = 0
 				a.gotoTop()
-				return a, adb.ListAppsCmd(a.state.DeviceSerial())
+				return a, tea.Batch(a.listApps(), a.loadStats())
+			}
+
+		case "o":
+			selected := a.selectedApp()
+			a.sortBy = (a.sortBy + 1) % AppSort(len(sortNames))
+			if selected != nil {
+				a.keepSelection(selected.PackageName)
 			}
 
 		case "i":
-			a.installForm.Show("Install APK", []components.FormField{
-				{Label: "APK Path", Value: ""},
-			})
+			if a.install == nil {
+				a.showInstallForm()
+			}
+
+		case "w":
+			return a, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "watch"}
+			}
+
+		case "U":
+			if len(a.users) > 1 {
+				labels := make([]string, len(a.users))
+				for i, u := range a.users {
+					labels[i] = u.Label()
+				}
+				current := ""
+				if u := a.selectedUser(); u != nil {
+					current = u.Label()
+				}
+				a.userForm.Show("Switch User", []components.FormField{
+					{Label: "User", Type: components.FormFieldSelect, Options: labels, Value: current},
+				})
+			}
 
 		case "right":
-			a.filterType = (a.filterType + 1) % 3
+			a.filterType = (a.filterType + 1) % AppFilter(len(filterNames))
 			a.cursor = 0
 			a.gotoTop()
 
 		case "left":
-			a.filterType = (a.filterType + 2) % 3
+			a.filterType = (a.filterType + AppFilter(len(filterNames)) - 1) % AppFilter(len(filterNames))
 			a.cursor = 0
 			a.gotoTop()
 
@@ -289,6 +808,10 @@ func (a *AppManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.gotoTop()
 				return a, consumeKeyCmd()
 			}
+			if len(a.marked) > 0 {
+				a.marked = nil
+				return a, consumeKeyCmd()
+			}
 
 		default:
 			return a, a.updateViewport(msg)
@@ -317,8 +840,43 @@ func (a *AppManager) View() string {
 			staticContent.WriteString(components.StatusMuted.Render(" / "))
 		}
 	}
+	staticContent.WriteString(components.StatusMuted.Render("  sort: ") + sortNames[a.sortBy])
+	switch {
+	case a.statsLoading:
+		staticContent.WriteString(components.StatusMuted.Render("  (loading sizes and usage...)"))
+	case a.labelsLoading:
+		staticContent.WriteString(components.StatusMuted.Render("  (loading labels...)"))
+	}
 	staticContent.WriteString("\n")
 
+	if len(a.users) > 1 || a.state.SelectedUser != 0 {
+		staticContent.WriteString("  " + components.StatusMuted.Render("user: ") + a.userLabel() + "\n")
+	}
+
+	if a.install != nil && a.install.check != nil {
+		staticContent.WriteString(renderInstallPreview(*a.install.check, a.state.Width-8))
+	} else if a.install != nil {
+		staticContent.WriteString(components.WarningStyle.Render(fmt.Sprintf(
+			"  ● %s... %s",
+			a.install.stage,
+			time.Since(a.install.started).Truncate(time.Second),
+		)) + "\n")
+	} else if a.installErr != nil {
+		staticContent.WriteString(renderInstallError(a.installErr, a.state.Width-8))
+	}
+	if a.extracting != "" {
+		staticContent.WriteString(components.WarningStyle.Render("  ● Extracting "+a.extracting+"...") + "\n")
+	}
+	if a.batchRunning != "" {
+		staticContent.WriteString(components.WarningStyle.Render("  ● "+a.batchRunning+"...") + "\n")
+	} else if a.batchResult != nil {
+		staticContent.WriteString(renderBatchResult(*a.batchResult, a.state.Width-8))
+	}
+	if len(a.marked) > 0 {
+		staticContent.WriteString("  " + components.HelpKeyStyle.Render(fmt.Sprintf("%d selected", len(a.marked))) +
+			components.StatusMuted.Render("  s/x/u/z/e apply to the selection, esc clears it") + "\n")
+	}
+
 	if a.search.Active {
 		staticContent.WriteString(
 			components.HelpKeyStyle.Render("search: ") + a.search.Query + "▌\n",
@@ -350,30 +908,47 @@ func (a *AppManager) View() string {
 				if i == a.cursor {
 					prefix = "› "
 				}
+				if a.marked[app.PackageName] {
+					prefix += components.HelpKeyStyle.Render("●") + " "
+				} else if len(a.marked) > 0 {
+					prefix += components.StatusMuted.Render("○") + " "
+				}
 
 				tag := components.StatusMuted.Render("[U]")
 				if app.IsSystem {
 					tag = components.StatusMuted.Render("[S]")
 				}
 
+				state := ""
+				switch {
+				case app.Uninstalled:
+					state = " " + components.ErrorStyle.Render("removed for user")
+				case app.Disabled:
+					state = " " + components.WarningStyle.Render("disabled")
+				}
+
 				var line string
 				if i == a.cursor {
 					line = fmt.Sprintf(
 						"%s%s %s",
 						prefix,
 						tag,
-						components.ListItemSelectedStyle.Render(app.PackageName),
+						components.ListItemSelectedStyle.Render(app.Name()),
 					)
 				} else {
 					line = fmt.Sprintf(
 						"%s%s %s",
 						prefix,
 						tag,
-						components.ListItemStyle.Render(app.PackageName),
+						components.ListItemStyle.Render(app.Name()),
 					)
 				}
 
-				scrollableContent.WriteString(truncStyle.Render(line) + "\n")
+				if app.Label != "" {
+					line += " " + components.StatusMuted.Render(app.PackageName)
+				}
+
+				scrollableContent.WriteString(truncStyle.Render(line+state+a.appDetails(app)) + "\n")
 			}
 		}
 	}
@@ -384,17 +959,35 @@ func (a *AppManager) View() string {
 			components.Help("esc", "cancel")
 	} else if a.search.Query != "" {
 		footer = components.Help("↑/↓", "navigate") + "  " +
-			components.Help("enter", "launch") + "  " +
+			components.Help("enter", "details") + "  " +
+			components.Help("l", "launch") + "  " +
 			components.Help("/", "search") + "  " +
 			components.Help("esc", "clear filter")
 	} else {
 		footer = components.Help("↑/↓", "navigate") + "  " +
-			components.Help("enter", "launch") + "  " +
+			components.Help("enter", "details") + "  " +
+			components.Help("space", "mark") + "  " +
+			components.Help("a", "mark all") + "  " +
+			components.Help("v", "invert marks") + "  " +
+			components.Help("l", "launch") + "  " +
 			components.Help("i", "install") + "  " +
+			components.Help("w", "watch") + "  " +
 			components.Help("s", "stop") + "  " +
 			components.Help("u", "uninstall") + "  " +
 			components.Help("x", "clear") + "  " +
+			components.Help("b", "benchmark") + "  " +
+			components.Help("t", "trace") + "  " +
+			components.Help("p", "permissions") + "  " +
+			components.Help("c", "components") + "  " +
+			components.Help("f", "app files") + "  " +
+			components.Help("L", "app links") + "  " +
+			components.Help("e/E", "extract/restore") + "  " +
+			components.Help("z", "disable/enable") + "  " +
+			components.Help("h", "remove for user") + "  " +
+			components.Help("D", "debloat") + "  " +
+			components.Help("U", "switch user") + "  " +
 			components.Help("←/→", "filter") + "  " +
+			components.Help("o", "sort") + "  " +
 			components.Help("/", "search") + "  " +
 			components.Help("r", "reload") + "  " +
 			components.Help("esc", "back")
@@ -412,6 +1005,18 @@ func (a *AppManager) View() string {
 		rendered = components.RenderFormOverlay(rendered, a.installForm, a.state)
 	}
 
+	if a.extractForm.Visible {
+		rendered = components.RenderFormOverlay(rendered, a.extractForm, a.state)
+	}
+
+	if a.restoreForm.Visible {
+		rendered = components.RenderFormOverlay(rendered, a.restoreForm, a.state)
+	}
+
+	if a.userForm.Visible {
+		rendered = components.RenderFormOverlay(rendered, a.userForm, a.state)
+	}
+
 	if a.confirm.Visible {
 		rendered = components.RenderOverlay(rendered, a.confirm.View(), a.state)
 	}
@@ -423,6 +1028,491 @@ func (a *AppManager) View() string {
 	return rendered
 }
 
+// appDetails is the muted suffix of a list row: version, size and the
+// date the list is sorted by.
+func (a *AppManager) appDetails(app adb.App) string {
+	var parts []string
+	if app.VersionCode != "" {
+		parts = append(parts, "v"+app.VersionCode)
+	}
+	if app.Size > 0 {
+		parts = append(parts, adb.FormatFileSize(strconv.FormatInt(app.Size, 10)))
+	}
+	switch a.sortBy {
+	case SortInstalled:
+		if !app.Installed.IsZero() {
+			parts = append(parts, "installed "+app.Installed.Format("2006-01-02"))
+		}
+	case SortLastUsed:
+		if !app.LastUsed.IsZero() {
+			parts = append(parts, "used "+app.LastUsed.Format("2006-01-02 15:04"))
+		} else if a.stats != nil {
+			parts = append(parts, "never used")
+		}
+	}
+	if app.Debuggable {
+		parts = append(parts, "debuggable")
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return "  " + components.StatusMuted.Render(strings.Join(parts, " · "))
+}
+
+func (a *AppManager) toggleMark(pkg string) {
+	a.setMark(pkg, !a.marked[pkg])
+}
+
+func (a *AppManager) setMark(pkg string, marked bool) {
+	if !marked {
+		delete(a.marked, pkg)
+		return
+	}
+	if a.marked == nil {
+		a.marked = map[string]bool{}
+	}
+	a.marked[pkg] = true
+}
+
+// pruneMarks drops marks of packages that are no longer listed, e.g. after
+// an uninstall or a user switch.
+func (a *AppManager) pruneMarks() {
+	listed := make(map[string]bool, len(a.apps))
+	for _, app := range a.apps {
+		listed[app.PackageName] = true
+	}
+	for pkg := range a.marked {
+		if !listed[pkg] {
+			delete(a.marked, pkg)
+		}
+	}
+}
+
+// markedApps are the marked apps in list order, including those the
+// current filter or search hides.
+func (a *AppManager) markedApps() []adb.App {
+	var apps []adb.App
+	for _, app := range a.apps {
+		if a.marked[app.PackageName] {
+			apps = append(apps, app)
+		}
+	}
+	sortApps(apps, a.sortBy)
+	return apps
+}
+
+// confirmApp asks to run action on pkg, naming it under prompt.
+func (a *AppManager) confirmApp(action, prompt, pkg string) {
+	a.pending = action
+	a.pendingPkg = pkg
+	a.confirm.Show(prompt + "\n" + pkg)
+}
+
+func (a *AppManager) showBatchConfirm(action string, apps []adb.App) {
+	if a.batchRunning != "" {
+		return
+	}
+	a.batchPkgs = a.batchPkgs[:0]
+	for _, app := range apps {
+		a.batchPkgs = append(a.batchPkgs, app.PackageName)
+	}
+
+	title := strings.ToUpper(action[:1]) + action[1:]
+	if action == string(debloat.Disable) {
+		title += " for user " + a.userLabel()
+	}
+	if skipped := len(a.markedApps()) - len(apps); skipped > 0 {
+		title += fmt.Sprintf(" (%d marked app(s) skipped)", skipped)
+	}
+	a.pending = batchPending + action
+	a.confirm.Show(a.batchPrompt(title, a.batchPkgs))
+}
+
+func (a *AppManager) confirmBatchExtract(values []string) {
+	a.batchDir = ""
+	if len(values) > 0 {
+		a.batchDir = expandHome(strings.TrimSpace(values[0]))
+	}
+	if a.batchDir == "" {
+		a.batchDir = defaultExtractDir()
+	}
+	a.pending = batchPending + "extract"
+	a.confirm.Show(a.batchPrompt("Extract APKs to "+a.batchDir, a.batchPkgs))
+}
+
+// batchPrompt lists the packages a batch action applies to, one per line,
+// as far as the terminal fits them.
+func (a *AppManager) batchPrompt(title string, pkgs []string) string {
+	room := max(a.state.Height-12, 3)
+	lines := []string{fmt.Sprintf("%s: %d app(s)", title, len(pkgs))}
+	for i, pkg := range pkgs {
+		if i == room-1 && len(pkgs) > room {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(pkgs)-i))
+			break
+		}
+		lines = append(lines, "  "+pkg)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runBatch applies action to every package in batchPkgs, one after the
+// other.
+func (a *AppManager) runBatch(action string) tea.Cmd {
+	pkgs := a.batchPkgs
+	a.batchPkgs = nil
+	if len(pkgs) == 0 {
+		return nil
+	}
+
+	serial := a.state.DeviceSerial()
+	user := a.state.SelectedUser
+
+	var run func(pkg string) error
+	switch action {
+	case "force stop":
+		run = func(pkg string) error { return adb.ForceStopApp(serial, pkg, user) }
+	case "clear data":
+		run = func(pkg string) error { return adb.ClearAppData(serial, pkg, user) }
+	case "uninstall":
+		run = func(pkg string) error { return adb.UninstallApp(serial, pkg, user) }
+	case string(debloat.Disable):
+		run = func(pkg string) error {
+			return adb.RunDebloat(serial, user, pkg, debloat.Disable, "").Err()
+		}
+	case "extract":
+		model := ""
+		if dev := a.state.SelectedDevice(); dev != nil {
+			model = dev.Model
+		}
+		dir := a.batchDir
+		run = func(pkg string) error {
+			_, _, err := adb.ExtractAPK(serial, model, pkg, dir)
+			return err
+		}
+	default:
+		return nil
+	}
+
+	a.batchResult = nil
+	a.batchRunning = fmt.Sprintf("%s: %d app(s)", action, len(pkgs))
+	return adb.BatchCmd(action, pkgs, run)
+}
+
+// finishBatch keeps the packages the action failed for marked, so that it
+// can be retried on them.
+func (a *AppManager) finishBatch(msg adb.BatchDoneMsg) tea.Cmd {
+	a.batchRunning = ""
+	a.batchResult = &msg
+	a.marked = nil
+	for _, r := range msg.Results {
+		if r.Error != nil {
+			a.setMark(r.Package, true)
+		}
+	}
+
+	failed := msg.Failed()
+	var cmd tea.Cmd
+	a.toast, cmd = components.ShowToast(
+		fmt.Sprintf("%s: %d succeeded, %d failed", msg.Action, len(msg.Results)-failed, failed),
+		failed > 0,
+		3*time.Second,
+	)
+
+	switch msg.Action {
+	case "uninstall", string(debloat.Disable):
+		return tea.Batch(cmd, a.listApps())
+	}
+	return cmd
+}
+
+// batchResultLines caps the per-package results shown above the list.
+const batchResultLines = 8
+
+// renderBatchResult summarizes the last batch action with a line per
+// package, failures first.
+func renderBatchResult(msg adb.BatchDoneMsg, maxWidth int) string {
+	truncStyle := lipgloss.NewStyle().MaxWidth(max(maxWidth, 20))
+	failed := msg.Failed()
+
+	var b strings.Builder
+	summary := fmt.Sprintf("  %s: %d succeeded, %d failed", msg.Action, len(msg.Results)-failed, failed)
+	if failed > 0 {
+		b.WriteString(components.ErrorStyle.Render(summary) + "\n")
+	} else {
+		b.WriteString(components.StatusConnected.Render(summary) + "\n")
+	}
+
+	results := make([]adb.BatchResult, 0, len(msg.Results))
+	for _, r := range msg.Results {
+		if r.Error != nil {
+			results = append(results, r)
+		}
+	}
+	for _, r := range msg.Results {
+		if r.Error == nil {
+			results = append(results, r)
+		}
+	}
+
+	for i, r := range results {
+		if i == batchResultLines {
+			b.WriteString(components.StatusMuted.Render(fmt.Sprintf("    ... and %d more", len(results)-i)) + "\n")
+			break
+		}
+		if r.Error != nil {
+			b.WriteString(truncStyle.Render(components.ErrorStyle.Render("    ✗ "+r.Package+": ")+r.Error.Error()) + "\n")
+		} else {
+			b.WriteString(truncStyle.Render(components.StatusMuted.Render("    ✓ "+r.Package)) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (a *AppManager) showInstallForm() {
+	a.installForm.Show("Install APK", []components.FormField{
+		{Label: "APK Path", Placeholder: ".apk, split dir or .apks/.xapk; comma separated"},
+		{Label: "Replace (-r)", Type: components.FormFieldSelect, Options: yesNoOptions, Value: "Yes"},
+		{Label: "Downgrade (-d)", Type: components.FormFieldSelect, Options: yesNoOptions, Value: "No"},
+		{Label: "Grant Perms (-g)", Type: components.FormFieldSelect, Options: yesNoOptions, Value: "No"},
+		{Label: "Allow Test (-t)", Type: components.FormFieldSelect, Options: yesNoOptions, Value: "No"},
+		{Label: "User", Placeholder: "all users", Value: a.installUser()},
+	})
+}
+
+// installUser is the --user for installs: empty (every user) unless a
+// secondary user or profile is selected.
+func (a *AppManager) installUser() string {
+	if a.state.SelectedUser == 0 {
+		return ""
+	}
+	return strconv.Itoa(a.state.SelectedUser)
+}
+
+func (a *AppManager) switchUser(values []string) tea.Cmd {
+	if len(values) == 0 {
+		return nil
+	}
+	for _, u := range a.users {
+		if u.Label() != values[0] || u.ID == a.state.SelectedUser {
+			continue
+		}
+		a.state.SelectUser(u.ID)
+		a.loading = true
+		a.cursor = 0
+		a.gotoTop()
+		return tea.Batch(a.listApps(), a.loadStats())
+	}
+	return nil
+}
+
+func (a *AppManager) startInstall(values []string) tea.Cmd {
+	if len(values) < 6 {
+		return nil
+	}
+
+	var paths []string
+	for _, p := range strings.Split(values[0], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, expandHome(p))
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	return a.beginInstall(paths, adb.InstallOptions{
+		Replace:          values[1] == "Yes",
+		Downgrade:        values[2] == "Yes",
+		GrantPermissions: values[3] == "Yes",
+		AllowTest:        values[4] == "Yes",
+		User:             strings.TrimSpace(values[5]),
+	})
+}
+
+func (a *AppManager) beginInstall(paths []string, opts adb.InstallOptions) tea.Cmd {
+	a.installErr = nil
+	a.install = &installProgress{
+		stage:   "Installing",
+		started: time.Now(),
+	}
+	return tea.Batch(
+		adb.PrepareInstallCmd(a.state.DeviceSerial(), paths, opts),
+		installTickCmd(),
+	)
+}
+
+func defaultExtractDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	return filepath.Join(home, "Downloads", "adbt-apks")
+}
+
+func (a *AppManager) startExtract(values []string) tea.Cmd {
+	if len(values) < 2 || strings.TrimSpace(values[0]) == "" {
+		return nil
+	}
+
+	dir := expandHome(strings.TrimSpace(values[1]))
+	if dir == "" {
+		dir = defaultExtractDir()
+	}
+
+	model := ""
+	if dev := a.state.SelectedDevice(); dev != nil {
+		model = dev.Model
+	}
+
+	a.extracting = strings.TrimSpace(values[0])
+	return adb.ExtractAPKCmd(a.state.DeviceSerial(), model, a.extracting, dir)
+}
+
+// startRestore reinstalls a folder created by extract, replacing the
+// installed app.
+func (a *AppManager) startRestore(values []string) tea.Cmd {
+	if len(values) < 2 {
+		return nil
+	}
+
+	dir := expandHome(strings.TrimSpace(values[0]))
+	manifest, err := adb.ReadAPKManifest(dir)
+	if err != nil {
+		a.installErr = err
+		var cmd tea.Cmd
+		a.toast, cmd = components.ShowToast(
+			"restore failed",
+			true,
+			3*time.Second,
+		)
+		return cmd
+	}
+
+	a.state.SelectPackage(manifest.Package)
+	cmd := a.beginInstall(manifest.RestorePaths(dir), adb.InstallOptions{
+		Replace:   true,
+		Downgrade: values[1] == "Yes",
+		User:      a.installUser(),
+	})
+	a.install.stage = fmt.Sprintf("Restoring %s (%s)", manifest.Package, manifest.VersionCode)
+	return cmd
+}
+
+// confirmInstall starts the install the preview was shown for.
+func (a *AppManager) confirmInstall() tea.Cmd {
+	if a.install == nil {
+		return nil
+	}
+	a.install.check = nil
+	return adb.InstallCmd(a.state.DeviceSerial(), a.install.plan)
+}
+
+// renderInstallPreview describes the APK about to be installed and lists
+// the compatibility warnings.
+func renderInstallPreview(check adb.InstallCheck, maxWidth int) string {
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+	m := check.APK.Manifest
+
+	version := valueOrDash(m.VersionName)
+	if m.VersionCode != "" {
+		version += components.StatusMuted.Render(" (" + m.VersionCode + ")")
+	}
+	installed := components.StatusMuted.Render("not installed")
+	if check.Installed {
+		installed = valueOrDash(check.InstalledVersionName) +
+			components.StatusMuted.Render(" ("+check.InstalledVersionCode+")")
+	}
+	abis := "any (no native code)"
+	if len(check.ABIs) > 0 {
+		abis = strings.Join(check.ABIs, ", ")
+	}
+	signer := components.StatusMuted.Render("unsigned")
+	if len(check.APK.Certificates) > 0 {
+		c := check.APK.Certificates[0]
+		signer = valueOrDash(c.Subject) + components.StatusMuted.Render(" ["+c.Scheme+"] "+c.SHA256)
+	}
+
+	var out strings.Builder
+	out.WriteString(truncStyle.Render(components.KeyValueList([]components.KeyValueRow{
+		{Key: "  Package:     ", Value: valueOrDash(m.Package)},
+		{Key: "  Version:     ", Value: version},
+		{Key: "  Installed:   ", Value: installed},
+		{Key: "  SDK:         ", Value: "min " + valueOrDash(m.MinSDK) + ", target " + valueOrDash(m.TargetSDK)},
+		{Key: "  ABIs:        ", Value: abis},
+		{Key: "  Launch:      ", Value: valueOrDash(check.APK.LaunchActivity)},
+		{Key: "  Signer:      ", Value: signer},
+		{Key: "  Permissions: ", Value: shortPermissions(m.Permissions)},
+	})) + "\n")
+
+	for _, w := range check.Warnings {
+		out.WriteString(truncStyle.Render(components.WarningStyle.Render("  ⚠ "+w)) + "\n")
+	}
+	return out.String()
+}
+
+// shortPermissions joins permission names without the android.permission
+// prefix.
+func shortPermissions(perms []string) string {
+	if len(perms) == 0 {
+		return valueOrDash("")
+	}
+	names := make([]string, len(perms))
+	for i, p := range perms {
+		names[i] = strings.TrimPrefix(p, "android.permission.")
+	}
+	return fmt.Sprintf("%d: %s", len(perms), strings.Join(names, ", "))
+}
+
+func (a *AppManager) finishInstall(err error) tea.Cmd {
+	a.install = nil
+	a.installErr = err
+
+	if err != nil {
+		var cmd tea.Cmd
+		a.toast, cmd = components.ShowToast(
+			"install failed",
+			true,
+			3*time.Second,
+		)
+		return cmd
+	}
+
+	var cmd tea.Cmd
+	a.toast, cmd = components.ShowToast(
+		"install successful",
+		false,
+		2*time.Second,
+	)
+	return tea.Batch(cmd, a.listApps())
+}
+
+func installTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return installTickMsg{}
+	})
+}
+
+// renderInstallError shows the parsed package manager failure and a hint
+// for known codes.
+func renderInstallError(err error, maxWidth int) string {
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	out := truncStyle.Render(components.ErrorStyle.Render("  ✗ Install failed: "+err.Error())) + "\n"
+
+	var failure *adb.InstallError
+	if errors.As(err, &failure) && failure.Hint() != "" {
+		out += truncStyle.Render(components.StatusMuted.Render("    "+failure.Hint())) + "\n"
+	}
+	return out
+}
+
 func (a *AppManager) updateViewport(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 	a.viewport, cmd = a.viewport.Update(msg)
@@ -437,6 +1527,44 @@ func (a *AppManager) ensureCursorVisible() {
 	ensureViewportLineVisible(&a.viewport, a.cursor)
 }
 
+// openPackageScreen switches to a per-package screen. The package stays
+// selected so the cursor lands on it again when coming back.
+func (a *AppManager) openPackageScreen(pkg, screen string) tea.Cmd {
+	a.state.SelectPackage(pkg)
+	return func() tea.Msg {
+		return navigation.SwitchScreenMsg{Screen: screen}
+	}
+}
+
+// keepSelection moves the cursor back to pkg after the list was reordered.
+func (a *AppManager) keepSelection(pkg string) {
+	for i, app := range a.filteredApps() {
+		if app.PackageName == pkg {
+			a.cursor = i
+			a.ensureCursorVisible()
+			return
+		}
+	}
+	a.cursor = 0
+	a.gotoTop()
+}
+
+// restoreSelection moves the cursor to the last selected package.
+func (a *AppManager) restoreSelection() {
+	if a.state.SelectedPackage == "" {
+		return
+	}
+	for i, app := range a.filteredApps() {
+		if app.PackageName == a.state.SelectedPackage {
+			a.cursor = i
+			// The viewport has no height before the first render, so
+			// scroll the selection to the top instead.
+			a.viewport.YOffset = i
+			return
+		}
+	}
+}
+
 func consumeKeyCmd() tea.Cmd {
 	return func() tea.Msg {
 		return nil
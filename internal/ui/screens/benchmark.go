@@ -0,0 +1,408 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/bench"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var benchmarkModeOptions = []string{"Cold", "Warm", "Cold + Warm"}
+
+type Benchmark struct {
+	state *state.AppState
+	pkg   string
+
+	form  components.FormModal
+	toast components.Toast
+
+	// Queued modes, current first
+	modes     []adb.BenchmarkMode
+	runs      int
+	running   bool
+	component string
+	current   *bench.Session
+	failures  int
+	lastError error
+
+	history   []bench.Session
+	completed []bench.Session
+	baselines []*bench.Session
+
+	viewport viewport.Model
+}
+
+func NewBenchmark(state *state.AppState) *Benchmark {
+	return &Benchmark{
+		state:    state,
+		pkg:      state.SelectedPackage,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (b *Benchmark) Init() tea.Cmd {
+	if !b.state.HasDevice() || b.pkg == "" {
+		return nil
+	}
+
+	history, err := bench.History(b.pkg)
+	b.history = history
+	b.showForm()
+
+	if err != nil {
+		var cmd tea.Cmd
+		b.toast, cmd = components.ShowToast(
+			"Failed to load previous runs: "+err.Error(),
+			true,
+			3*time.Second,
+		)
+		return cmd
+	}
+	return nil
+}
+
+func (b *Benchmark) Cleanup() tea.Cmd {
+	b.running = false
+	b.modes = nil
+	return nil
+}
+
+func (b *Benchmark) showForm() {
+	b.form.Show("Startup Benchmark", []components.FormField{
+		{Label: "Mode", Type: components.FormFieldSelect, Options: benchmarkModeOptions, Value: "Cold"},
+		{Label: "Runs", Value: "10"},
+	})
+}
+
+func (b *Benchmark) InputActive() bool {
+	return b.form.Visible
+}
+
+func (b *Benchmark) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	b.toast.Update(msg)
+
+	if b.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			b.form.Hide()
+			return b, b.start(msg.Values)
+		case components.FormCancelMsg:
+			b.form.Hide()
+			return b, nil
+		}
+		return b, b.form.Update(msg)
+	}
+
+	switch msg := msg.(type) {
+	case adb.BenchmarkPreparedMsg:
+		if !b.running || b.current == nil || msg.Mode != b.modes[0] {
+			return b, nil
+		}
+		if msg.Error != nil {
+			return b, b.abort(msg.Error)
+		}
+		b.component = msg.Component
+		b.current.VersionName = msg.VersionName
+		b.current.VersionCode = msg.VersionCode
+		return b, adb.BenchmarkRunCmd(b.state.DeviceSerial(), b.component, b.state.SelectedUser, msg.Mode, 1)
+
+	case adb.BenchmarkRunMsg:
+		if !b.running || b.current == nil || msg.Mode != b.modes[0] {
+			return b, nil
+		}
+
+		if msg.Error != nil {
+			b.failures++
+			b.lastError = msg.Error
+		} else {
+			b.current.Runs = append(b.current.Runs, bench.Run{
+				TotalTime:   msg.Result.TotalTime,
+				WaitTime:    msg.Result.WaitTime,
+				ThisTime:    msg.Result.ThisTime,
+				LaunchState: msg.Result.LaunchState,
+			})
+		}
+
+		if msg.Run < b.runs {
+			return b, adb.BenchmarkRunCmd(b.state.DeviceSerial(), b.component, b.state.SelectedUser, msg.Mode, msg.Run+1)
+		}
+		return b, b.finishMode()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "n":
+			if !b.running && b.pkg != "" {
+				b.showForm()
+			}
+		case "esc":
+			b.Cleanup()
+			return b, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "apps"}
+			}
+		default:
+			return b, b.updateViewport(msg)
+		}
+	}
+
+	return b, nil
+}
+
+func (b *Benchmark) start(values []string) tea.Cmd {
+	runs := 0
+	if len(values) > 1 {
+		runs, _ = strconv.Atoi(strings.TrimSpace(values[1]))
+	}
+	if runs < 1 || runs > 100 {
+		var cmd tea.Cmd
+		b.toast, cmd = components.ShowToast(
+			"Runs must be between 1 and 100",
+			true,
+			2*time.Second,
+		)
+		return cmd
+	}
+
+	switch values[0] {
+	case "Warm":
+		b.modes = []adb.BenchmarkMode{adb.BenchmarkWarm}
+	case "Cold + Warm":
+		b.modes = []adb.BenchmarkMode{adb.BenchmarkCold, adb.BenchmarkWarm}
+	default:
+		b.modes = []adb.BenchmarkMode{adb.BenchmarkCold}
+	}
+
+	b.runs = runs
+	b.running = true
+	b.failures = 0
+	b.lastError = nil
+	b.completed = nil
+	b.baselines = nil
+	return b.startMode()
+}
+
+func (b *Benchmark) startMode() tea.Cmd {
+	mode := b.modes[0]
+	b.current = &bench.Session{
+		Time:    time.Now(),
+		Serial:  b.state.DeviceSerial(),
+		Package: b.pkg,
+		Mode:    string(mode),
+	}
+	return adb.PrepareBenchmarkCmd(b.state.DeviceSerial(), b.pkg, b.state.SelectedUser, mode)
+}
+
+func (b *Benchmark) finishMode() tea.Cmd {
+	session := *b.current
+	b.current = nil
+	b.modes = b.modes[1:]
+
+	var cmds []tea.Cmd
+	if len(session.Runs) > 0 {
+		b.completed = append(b.completed, session)
+		b.baselines = append(b.baselines, bench.Baseline(b.history, session))
+
+		if err := bench.Save(session); err != nil {
+			var cmd tea.Cmd
+			b.toast, cmd = components.ShowToast(
+				"Failed to save runs: "+err.Error(),
+				true,
+				3*time.Second,
+			)
+			cmds = append(cmds, cmd)
+		}
+		b.history = append(b.history, session)
+	}
+
+	if len(b.modes) > 0 {
+		return tea.Batch(append(cmds, b.startMode())...)
+	}
+
+	b.running = false
+	if len(cmds) == 0 {
+		var cmd tea.Cmd
+		b.toast, cmd = components.ShowToast(
+			"Benchmark complete",
+			false,
+			2*time.Second,
+		)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (b *Benchmark) abort(err error) tea.Cmd {
+	b.running = false
+	b.modes = nil
+	b.current = nil
+	b.lastError = err
+
+	var cmd tea.Cmd
+	b.toast, cmd = components.ShowToast(
+		"Benchmark failed: "+err.Error(),
+		true,
+		3*time.Second,
+	)
+	return cmd
+}
+
+func (b *Benchmark) View() string {
+	if !b.state.HasDevice() {
+		return components.RenderNoDevice(b.state, "Benchmark")
+	}
+
+	maxWidth := b.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	static.WriteString(components.TitleStyle.Render("Startup Benchmark") + "\n")
+	static.WriteString(components.StatusMuted.Render("  Package: ") + b.pkg + "\n")
+
+	switch {
+	case b.running && b.current != nil:
+		static.WriteString(components.WarningStyle.Render(fmt.Sprintf(
+			"  ● %s start, run %d/%d",
+			b.current.Mode,
+			len(b.current.Runs)+b.failures+1,
+			b.runs,
+		)) + "\n")
+	case b.running:
+		static.WriteString(components.WarningStyle.Render("  ● preparing") + "\n")
+	default:
+		static.WriteString(components.StatusMuted.Render("  ● idle") + "\n")
+	}
+
+	var body strings.Builder
+
+	if b.lastError != nil {
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render(fmt.Sprintf(
+			"%d failed run(s), last error: %s", b.failures, b.lastError.Error(),
+		))) + "\n\n")
+	}
+
+	for i, session := range b.completed {
+		body.WriteString(b.renderSession(session, b.baselines[i], truncStyle))
+	}
+	if b.current != nil && len(b.current.Runs) > 0 {
+		body.WriteString(b.renderSession(*b.current, nil, truncStyle))
+	}
+
+	if len(b.completed) == 0 && b.current == nil {
+		if len(b.history) > 0 {
+			last := b.history[len(b.history)-1]
+			body.WriteString(components.StatusMuted.Render("Last saved run") + "\n")
+			body.WriteString(b.renderSession(last, nil, truncStyle))
+		} else {
+			body.WriteString(components.StatusMuted.Render("Press [n] to start a benchmark") + "\n")
+		}
+	}
+
+	footer := components.Help("n", "new benchmark") + "  " +
+		components.Help("↑/↓", "scroll") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(b.state, components.LayoutWithScrollProps{
+		Title:             "Benchmark",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &b.viewport,
+	})
+
+	if b.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, b.form, b.state)
+	}
+
+	if b.toast.Visible {
+		rendered = components.RenderOverlay(rendered, b.toast.View(), b.state)
+	}
+
+	return rendered
+}
+
+func (b *Benchmark) renderSession(s bench.Session, baseline *bench.Session, truncStyle lipgloss.Style) string {
+	var out strings.Builder
+
+	title := strings.ToUpper(s.Mode[:1]) + s.Mode[1:] + " start"
+	out.WriteString(components.TitleStyle.Render(title) + " " +
+		components.StatusMuted.Render(fmt.Sprintf("%s  %s", versionLabel(s), s.Time.Format("2006-01-02 15:04"))) + "\n")
+
+	times := make([]string, len(s.Runs))
+	for i, r := range s.Runs {
+		times[i] = strconv.Itoa(r.TotalTime)
+	}
+	out.WriteString(truncStyle.Render("  "+components.StatusMuted.Render("Runs (ms): ")+strings.Join(times, " ")) + "\n")
+
+	out.WriteString(components.StatusMuted.Render(fmt.Sprintf(
+		"  %-10s %10s %10s %10s %5s", "", "Mean", "Median", "StdDev", "N",
+	)) + "\n")
+	writeStats := func(name string, st bench.Stats) {
+		if st.N == 0 {
+			return
+		}
+		out.WriteString(fmt.Sprintf(
+			"  %-10s %10.1f %10.1f %10.1f %5d\n",
+			name, st.Mean, st.Median, st.StdDev, st.N,
+		))
+	}
+	writeStats("TotalTime", s.TotalTimeStats())
+	writeStats("WaitTime", s.WaitTimeStats())
+	writeStats("ThisTime", s.ThisTimeStats())
+
+	if baseline != nil {
+		prev := baseline.TotalTimeStats().Mean
+		cur := s.TotalTimeStats().Mean
+		delta := cur - prev
+
+		style := components.StatusConnected
+		if delta > 0 {
+			style = components.ErrorStyle
+		}
+
+		change := ""
+		if prev > 0 {
+			change = fmt.Sprintf(" (%+.1f%%)", delta/prev*100)
+		}
+
+		out.WriteString(truncStyle.Render(fmt.Sprintf(
+			"  vs %s on %s: mean TotalTime %.1f → %.1f ms %s",
+			versionLabel(*baseline),
+			baseline.Time.Format("2006-01-02 15:04"),
+			prev,
+			cur,
+			style.Render(fmt.Sprintf("%+.1f ms%s", delta, change)),
+		)) + "\n")
+	}
+
+	out.WriteString("\n")
+	return out.String()
+}
+
+func versionLabel(s bench.Session) string {
+	switch {
+	case s.VersionName != "" && s.VersionCode != "":
+		return fmt.Sprintf("v%s (%s)", s.VersionName, s.VersionCode)
+	case s.VersionCode != "":
+		return "versionCode " + s.VersionCode
+	default:
+		return "unknown version"
+	}
+}
+
+func (b *Benchmark) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	b.viewport, cmd = b.viewport.Update(msg)
+	return cmd
+}
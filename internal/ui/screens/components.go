@@ -0,0 +1,421 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/apk"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var componentTabNames = []string{"Activities", "Services", "Receivers", "Providers"}
+
+// resultLines caps how much of an action's output is shown above the list.
+const resultLines = 6
+
+// Components lists the activities, services, receivers and providers
+// declared in a package's manifest, one type per tab.
+type Components struct {
+	state *state.AppState
+	pkg   string
+
+	loading  bool
+	manifest *apk.Manifest
+	err      error
+	tab      int
+	cursor   int
+
+	running string
+	result  *adb.ComponentResultMsg
+
+	form  components.FormModal
+	toast components.Toast
+
+	viewport viewport.Model
+}
+
+func NewComponents(state *state.AppState) *Components {
+	return &Components{
+		state:    state,
+		pkg:      state.SelectedPackage,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (c *Components) Init() tea.Cmd {
+	if !c.state.HasDevice() || c.pkg == "" {
+		return nil
+	}
+	return c.load()
+}
+
+func (c *Components) load() tea.Cmd {
+	c.loading = true
+	c.err = nil
+	return adb.GetPackageManifestCmd(c.state.DeviceSerial(), c.pkg)
+}
+
+func (c *Components) items() []apk.Component {
+	if c.manifest == nil {
+		return nil
+	}
+	return c.manifest.ComponentsOf(apk.ComponentTypes[c.tab])
+}
+
+func (c *Components) selected() *apk.Component {
+	items := c.items()
+	if c.cursor >= len(items) {
+		return nil
+	}
+	return &items[c.cursor]
+}
+
+func (c *Components) InputActive() bool {
+	return c.form.Visible
+}
+
+func (c *Components) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	c.toast.Update(msg)
+	serial := c.state.DeviceSerial()
+
+	if c.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			c.form.Hide()
+			if comp := c.selected(); comp != nil && c.manifest != nil {
+				action := ""
+				if len(msg.Values) > 0 {
+					action = strings.TrimSpace(msg.Values[0])
+				}
+				return c, c.run(adb.BroadcastCmd(serial, c.state.SelectedUser, c.manifest.ComponentName(*comp), action), "Broadcasting")
+			}
+			return c, nil
+		case components.FormCancelMsg:
+			c.form.Hide()
+			return c, nil
+		}
+		return c, c.form.Update(msg)
+	}
+
+	switch msg := msg.(type) {
+	case adb.PackageManifestMsg:
+		if msg.Package != c.pkg {
+			return c, nil
+		}
+		c.loading = false
+		c.err = msg.Error
+		if msg.Error == nil {
+			c.manifest = &msg.Manifest
+		}
+		c.clampCursor()
+
+	case adb.ComponentResultMsg:
+		c.running = ""
+		c.result = &msg
+		if msg.Error != nil {
+			var cmd tea.Cmd
+			c.toast, cmd = components.ShowToast(msg.Action+" failed", true, 3*time.Second)
+			return c, cmd
+		}
+		var cmd tea.Cmd
+		c.toast, cmd = components.ShowToast(msg.Action+" successful", false, 2*time.Second)
+		return c, cmd
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if c.cursor > 0 {
+				c.cursor--
+				ensureViewportLineVisible(&c.viewport, 1+c.cursor)
+			}
+		case "down", "j":
+			if c.cursor < len(c.items())-1 {
+				c.cursor++
+				ensureViewportLineVisible(&c.viewport, 1+c.cursor)
+			}
+		case "right":
+			c.tab = (c.tab + 1) % len(apk.ComponentTypes)
+			c.cursor = 0
+			c.viewport.GotoTop()
+		case "left":
+			c.tab = (c.tab + len(apk.ComponentTypes) - 1) % len(apk.ComponentTypes)
+			c.cursor = 0
+			c.viewport.GotoTop()
+		case "enter":
+			return c, c.activate()
+		case "x":
+			if comp := c.selected(); comp != nil && comp.Type == apk.Service && c.running == "" {
+				return c, c.run(adb.StopServiceCmd(serial, c.state.SelectedUser, c.manifest.ComponentName(*comp)), "Stopping")
+			}
+		case "r":
+			if c.state.HasDevice() && c.pkg != "" {
+				return c, c.load()
+			}
+		case "esc":
+			return c, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "apps"}
+			}
+		default:
+			return c, c.updateViewport(msg)
+		}
+	}
+
+	return c, nil
+}
+
+// activate runs the natural action for the selected component's type.
+func (c *Components) activate() tea.Cmd {
+	comp := c.selected()
+	if comp == nil || c.running != "" {
+		return nil
+	}
+	serial := c.state.DeviceSerial()
+	name := c.manifest.ComponentName(*comp)
+
+	switch comp.Type {
+	case apk.Activity:
+		return c.run(adb.StartActivityCmd(serial, c.state.SelectedUser, name), "Starting")
+	case apk.Service:
+		return c.run(adb.StartServiceCmd(serial, c.state.SelectedUser, name), "Starting")
+	case apk.Receiver:
+		var actions []string
+		for _, f := range comp.Filters {
+			actions = append(actions, f.Actions...)
+		}
+		value := ""
+		if len(actions) > 0 {
+			value = actions[0]
+		}
+		c.form.Show("Broadcast to "+name, []components.FormField{
+			{Label: "Action", Value: value, Placeholder: "optional", Suggestions: actions},
+		})
+	case apk.Provider:
+		if len(comp.Authorities) == 0 {
+			var cmd tea.Cmd
+			c.toast, cmd = components.ShowToast("Provider declares no authority", true, 2*time.Second)
+			return cmd
+		}
+		return c.run(adb.QueryProviderCmd(serial, c.state.SelectedUser, comp.Authorities[0]), "Querying")
+	}
+	return nil
+}
+
+func (c *Components) run(cmd tea.Cmd, verb string) tea.Cmd {
+	if comp := c.selected(); comp != nil {
+		c.running = verb + " " + shortClass(comp.Name)
+	}
+	return cmd
+}
+
+func (c *Components) clampCursor() {
+	if n := len(c.items()); c.cursor >= n {
+		c.cursor = n - 1
+	}
+	if c.cursor < 0 {
+		c.cursor = 0
+	}
+}
+
+func (c *Components) View() string {
+	if !c.state.HasDevice() {
+		return components.RenderNoDevice(c.state, "Components")
+	}
+
+	maxWidth := c.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	static.WriteString(components.TitleStyle.Render(c.pkg) + "\n")
+
+	static.WriteString("  ")
+	for i, name := range componentTabNames {
+		label := name
+		if c.manifest != nil {
+			label = fmt.Sprintf("%s (%d)", name, len(c.manifest.ComponentsOf(apk.ComponentTypes[i])))
+		}
+		if i == c.tab {
+			static.WriteString(components.HelpKeyStyle.Render(label))
+		} else {
+			static.WriteString(components.StatusMuted.Render(label))
+		}
+		if i < len(componentTabNames)-1 {
+			static.WriteString(components.StatusMuted.Render(" / "))
+		}
+	}
+	static.WriteString("\n")
+
+	if c.running != "" {
+		static.WriteString(components.WarningStyle.Render("  ● "+c.running+"...") + "\n")
+	} else if c.result != nil {
+		static.WriteString(c.renderResult(truncStyle))
+	}
+
+	var body strings.Builder
+
+	switch {
+	case c.loading && c.manifest == nil:
+		body.WriteString(components.StatusMuted.Render("Reading the manifest from the APK..."))
+	case c.err != nil:
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render(c.err.Error())))
+	case c.manifest != nil:
+		body.WriteString(c.renderList(truncStyle))
+	}
+
+	footer := components.Help("↑/↓", "navigate") + "  " +
+		components.Help("←/→", "type") + "  " +
+		components.Help("enter", c.actionHint())
+	if comp := c.selected(); comp != nil && comp.Type == apk.Service {
+		footer += "  " + components.Help("x", "stop")
+	}
+	footer += "  " + components.Help("r", "reload") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(c.state, components.LayoutWithScrollProps{
+		Title:             "Components",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &c.viewport,
+	})
+
+	if c.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, c.form, c.state)
+	}
+
+	if c.toast.Visible {
+		rendered = components.RenderOverlay(rendered, c.toast.View(), c.state)
+	}
+
+	return rendered
+}
+
+func (c *Components) actionHint() string {
+	switch apk.ComponentTypes[c.tab] {
+	case apk.Service:
+		return "start"
+	case apk.Receiver:
+		return "broadcast"
+	case apk.Provider:
+		return "query"
+	}
+	return "launch"
+}
+
+func (c *Components) renderResult(truncStyle lipgloss.Style) string {
+	var out strings.Builder
+
+	header := "  " + c.result.Action + " " + c.result.Component
+	if c.result.Error != nil {
+		out.WriteString(truncStyle.Render(components.ErrorStyle.Render(header)) + "\n")
+	} else {
+		out.WriteString(truncStyle.Render(components.StatusConnected.Render(header)) + "\n")
+	}
+
+	output := c.result.Output
+	if output == "" && c.result.Error != nil {
+		output = c.result.Error.Error()
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) > resultLines {
+		more := len(lines) - resultLines
+		lines = append(lines[:resultLines], fmt.Sprintf("... %d more lines", more))
+	}
+	for _, line := range lines {
+		if line = strings.TrimRight(line, "\r "); line != "" {
+			out.WriteString(truncStyle.Render("    "+components.StatusMuted.Render(line)) + "\n")
+		}
+	}
+	return out.String()
+}
+
+func (c *Components) renderList(truncStyle lipgloss.Style) string {
+	var out strings.Builder
+	items := c.items()
+
+	out.WriteString(components.TitleStyle.Render(componentTabNames[c.tab]) + "\n")
+	if len(items) == 0 {
+		out.WriteString(components.StatusMuted.Render("  None declared") + "\n")
+	}
+
+	for i, comp := range items {
+		prefix, style := "  ", components.ListItemStyle
+		if i == c.cursor {
+			prefix, style = "› ", components.ListItemSelectedStyle
+		}
+
+		exported := components.StatusMuted.Render("private ")
+		if comp.Exported {
+			exported = components.StatusConnected.Render("exported")
+		}
+
+		line := prefix + exported + " " + style.Render(shortClass(comp.Name))
+		if !comp.Enabled {
+			line += " " + components.WarningStyle.Render("disabled")
+		}
+		if comp.Permission != "" {
+			line += " " + components.StatusMuted.Render("["+comp.Permission+"]")
+		}
+		out.WriteString(truncStyle.Render(line) + "\n")
+
+		if i == c.cursor {
+			out.WriteString(c.renderComponentDetail(comp, truncStyle))
+		}
+	}
+
+	return out.String()
+}
+
+// renderComponentDetail lists the selected component's alias target,
+// authorities and intent filters beneath its row.
+func (c *Components) renderComponentDetail(comp apk.Component, truncStyle lipgloss.Style) string {
+	var out strings.Builder
+	indent := "      "
+
+	out.WriteString(truncStyle.Render(indent+components.StatusMuted.Render(c.manifest.ComponentName(comp))) + "\n")
+	if comp.TargetActivity != "" {
+		out.WriteString(truncStyle.Render(indent+components.StatusMuted.Render("alias of ")+shortClass(comp.TargetActivity)) + "\n")
+	}
+	if len(comp.Authorities) > 0 {
+		out.WriteString(truncStyle.Render(indent+components.StatusMuted.Render("authorities: ")+strings.Join(comp.Authorities, ", ")) + "\n")
+	}
+
+	for _, f := range comp.Filters {
+		out.WriteString(truncStyle.Render(indent+components.StatusMuted.Render("intent-filter")) + "\n")
+		for _, a := range f.Actions {
+			out.WriteString(truncStyle.Render(indent+"  "+components.StatusMuted.Render("action   ")+a) + "\n")
+		}
+		for _, cat := range f.Categories {
+			out.WriteString(truncStyle.Render(indent+"  "+components.StatusMuted.Render("category ")+cat) + "\n")
+		}
+		for _, d := range f.Data {
+			out.WriteString(truncStyle.Render(indent+"  "+components.StatusMuted.Render("data     ")+d) + "\n")
+		}
+	}
+
+	return out.String()
+}
+
+// shortClass drops the package part of a fully qualified class name.
+func shortClass(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func (c *Components) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	c.viewport, cmd = c.viewport.Update(msg)
+	return cmd
+}
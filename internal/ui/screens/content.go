@@ -0,0 +1,512 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/content"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// contentSource is a preset or a URI from the history in the list.
+type contentSource struct {
+	name  string
+	query adb.ContentQuery
+}
+
+// Content queries and edits content providers with the `content` tool.
+type Content struct {
+	state *state.AppState
+
+	// browsing shows presets and history instead of the result.
+	browsing bool
+	cursor   int
+	history  []string
+	err      error
+
+	query     adb.ContentQuery
+	ran       bool
+	loading   bool
+	result    adb.ContentResult
+	resultErr error
+	colOffset int
+
+	queryForm   components.FormModal
+	writeForm   components.FormModal
+	writeAction string
+	pending     *adb.ContentWrite
+	confirm     components.ConfirmPrompt
+	writing     bool
+
+	toast    components.Toast
+	viewport viewport.Model
+}
+
+func NewContent(state *state.AppState) *Content {
+	return &Content{
+		state:    state,
+		browsing: true,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (c *Content) Init() tea.Cmd {
+	c.history, c.err = content.History()
+	return nil
+}
+
+// sources lists the history, newest first, then the presets.
+func (c *Content) sources() []contentSource {
+	var sources []contentSource
+	for _, uri := range c.history {
+		sources = append(sources, contentSource{query: adb.ContentQuery{URI: uri}})
+	}
+	for _, p := range adb.ContentPresets {
+		sources = append(sources, contentSource{name: p.Name, query: p.Query})
+	}
+	return sources
+}
+
+func (c *Content) uriSuggestions() []string {
+	suggestions := append([]string{}, c.history...)
+	for _, p := range adb.ContentPresets {
+		suggestions = append(suggestions, p.Query.URI)
+	}
+	return suggestions
+}
+
+func (c *Content) showQueryForm(q adb.ContentQuery) {
+	if q.URI == "" {
+		q.URI = "content://"
+	}
+	c.queryForm.Show("Query Provider", []components.FormField{
+		{Label: "URI", Value: q.URI, Type: components.FormFieldAutocomplete, Suggestions: c.uriSuggestions()},
+		{Label: "Projection", Value: q.Projection, Placeholder: "columns, e.g. _id,name (all when empty)"},
+		{Label: "Where", Value: q.Where, Placeholder: "e.g. name='adb_enabled'"},
+		{Label: "Sort", Value: q.Sort, Placeholder: "e.g. name DESC"},
+	})
+}
+
+// showWriteForm asks for the values of an insert, update or delete,
+// filled with values when a previous attempt was rejected.
+func (c *Content) showWriteForm(action string, values []string) {
+	c.writeAction = action
+
+	uri := c.query.URI
+	if uri == "" {
+		uri = "content://"
+	}
+	fields := []components.FormField{
+		{Label: "URI", Value: uri, Type: components.FormFieldAutocomplete, Suggestions: c.uriSuggestions()},
+	}
+	if action != "delete" {
+		fields = append(fields, components.FormField{Label: "Bind Values", Placeholder: adb.BindsSyntax})
+	}
+	if action != "insert" {
+		fields = append(fields, components.FormField{Label: "Where", Placeholder: "e.g. _id=3 (all rows when empty)"})
+	}
+	for idx := range fields {
+		if idx < len(values) {
+			fields[idx].Value = values[idx]
+		}
+	}
+
+	titles := map[string]string{"insert": "Insert Row", "update": "Update Rows", "delete": "Delete Rows"}
+	c.writeForm.Show(titles[action], fields)
+}
+
+// buildWrite reads the write form in the order showWriteForm lays it out.
+func (c *Content) buildWrite(values []string) (adb.ContentWrite, error) {
+	next := func() string {
+		if len(values) == 0 {
+			return ""
+		}
+		v := strings.TrimSpace(values[0])
+		values = values[1:]
+		return v
+	}
+
+	w := adb.ContentWrite{Action: c.writeAction, URI: next(), User: c.state.SelectedUser}
+	if w.Action != "delete" {
+		binds, err := adb.ParseBinds(next())
+		if err != nil {
+			return w, err
+		}
+		w.Binds = binds
+	}
+	if w.Action != "insert" {
+		w.Where = next()
+	}
+	return w, w.Validate()
+}
+
+func (c *Content) run(q adb.ContentQuery) tea.Cmd {
+	if !c.state.HasDevice() {
+		return nil
+	}
+	if err := q.Validate(); err != nil {
+		c.showQueryForm(q)
+		return c.showError(err)
+	}
+	q.User = c.state.SelectedUser
+	c.query = q
+	c.ran = true
+	c.loading = true
+	c.browsing = false
+	c.colOffset = 0
+	c.viewport.GotoTop()
+
+	var err error
+	if c.history, err = content.Remember(c.history, q.URI); err != nil {
+		c.err = err
+	}
+	return adb.QueryContentCmd(c.state.DeviceSerial(), q)
+}
+
+func (c *Content) InputActive() bool {
+	return c.queryForm.Visible || c.writeForm.Visible
+}
+
+func (c *Content) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	c.toast.Update(msg)
+
+	if c.queryForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			c.queryForm.Hide()
+			value := func(idx int) string {
+				if idx < len(msg.Values) {
+					return strings.TrimSpace(msg.Values[idx])
+				}
+				return ""
+			}
+			return c, c.run(adb.ContentQuery{URI: value(0), Projection: value(1), Where: value(2), Sort: value(3)})
+
+		case components.FormCancelMsg:
+			c.queryForm.Hide()
+			return c, nil
+		}
+
+		return c, c.queryForm.Update(msg)
+	}
+
+	if c.writeForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			c.writeForm.Hide()
+			w, err := c.buildWrite(msg.Values)
+			if err != nil {
+				c.showWriteForm(c.writeAction, msg.Values)
+				return c, c.showError(err)
+			}
+			if w.Action == "insert" {
+				return c, c.write(w)
+			}
+			c.pending = &w
+			c.confirm.Show(writePrompt(w))
+			return c, nil
+
+		case components.FormCancelMsg:
+			c.writeForm.Hide()
+			return c, nil
+		}
+
+		return c, c.writeForm.Update(msg)
+	}
+
+	if c.confirm.Visible {
+		switch msg.(type) {
+		case components.ConfirmYesMsg:
+			c.confirm.Hide()
+			if c.pending != nil {
+				w := *c.pending
+				c.pending = nil
+				return c, c.write(w)
+			}
+			return c, nil
+
+		case components.ConfirmNoMsg:
+			c.confirm.Hide()
+			c.pending = nil
+			return c, nil
+		}
+
+		return c, c.confirm.Update(msg)
+	}
+
+	switch msg := msg.(type) {
+	case adb.ContentQueryMsg:
+		if msg.Query.URI != c.query.URI {
+			return c, nil
+		}
+		c.loading = false
+		c.result = msg.Result
+		c.resultErr = msg.Error
+
+	case adb.ContentWriteMsg:
+		c.writing = false
+		action := strings.ToUpper(msg.Write.Action[:1]) + msg.Write.Action[1:]
+		var cmd tea.Cmd
+		if msg.Error != nil {
+			c.toast, cmd = components.ShowToast(action+" failed: "+msg.Error.Error(), true, 3*time.Second)
+			return c, cmd
+		}
+
+		text := action + " successful"
+		if msg.Output != "" {
+			text += ": " + msg.Output
+		}
+		c.toast, cmd = components.ShowToast(text, false, 2*time.Second)
+		if c.ran && msg.Write.URI == c.query.URI {
+			return c, tea.Batch(cmd, c.run(c.query))
+		}
+		return c, cmd
+
+	case tea.KeyMsg:
+		if c.browsing {
+			return c, c.updateBrowsing(msg)
+		}
+		return c, c.updateResult(msg)
+	}
+
+	return c, nil
+}
+
+func (c *Content) updateBrowsing(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if c.cursor > 0 {
+			c.cursor--
+			ensureViewportLineVisible(&c.viewport, c.cursorLine())
+		}
+	case "down", "j":
+		if c.cursor < len(c.sources())-1 {
+			c.cursor++
+			ensureViewportLineVisible(&c.viewport, c.cursorLine())
+		}
+	case "enter":
+		if sources := c.sources(); c.cursor < len(sources) {
+			return c.run(sources[c.cursor].query)
+		}
+	case "e":
+		if sources := c.sources(); c.cursor < len(sources) {
+			c.showQueryForm(sources[c.cursor].query)
+		}
+	case "n":
+		c.showQueryForm(adb.ContentQuery{})
+	case "i":
+		c.showWriteForm("insert", nil)
+	case "tab":
+		if c.ran {
+			c.browsing = false
+			c.viewport.GotoTop()
+		}
+	default:
+		var cmd tea.Cmd
+		c.viewport, cmd = c.viewport.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (c *Content) updateResult(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "tab":
+		c.browsing = true
+		c.viewport.GotoTop()
+		return consumeKeyCmd()
+	case "n":
+		c.showQueryForm(adb.ContentQuery{})
+	case "e":
+		c.showQueryForm(c.query)
+	case "r":
+		if !c.loading {
+			return c.run(c.query)
+		}
+	case "i", "u", "d":
+		if c.writing {
+			return nil
+		}
+		action := map[string]string{"i": "insert", "u": "update", "d": "delete"}[msg.String()]
+		c.showWriteForm(action, nil)
+	case "l", "right":
+		if c.colOffset < len(c.result.Columns)-1 {
+			c.colOffset++
+		}
+	case "h", "left":
+		if c.colOffset > 0 {
+			c.colOffset--
+		}
+	default:
+		var cmd tea.Cmd
+		c.viewport, cmd = c.viewport.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (c *Content) write(w adb.ContentWrite) tea.Cmd {
+	if c.writing || !c.state.HasDevice() {
+		return nil
+	}
+	c.writing = true
+	return adb.WriteContentCmd(c.state.DeviceSerial(), w)
+}
+
+// writePrompt spells out which rows an update or delete touches.
+func writePrompt(w adb.ContentWrite) string {
+	rows := "ALL rows"
+	if w.Where != "" {
+		rows = "rows where " + w.Where
+	}
+	verb := "Delete"
+	if w.Action == "update" {
+		verb = "Update"
+	}
+	return verb + " " + rows + " in:\n" + w.URI
+}
+
+func (c *Content) showError(err error) tea.Cmd {
+	var cmd tea.Cmd
+	c.toast, cmd = components.ShowToast(err.Error(), true, 3*time.Second)
+	return cmd
+}
+
+func (c *Content) View() string {
+	if !c.state.HasDevice() {
+		return components.RenderNoDevice(c.state, "Content Providers")
+	}
+
+	maxWidth := c.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	static.WriteString(components.TitleStyle.Render("Content Providers") + "\n")
+
+	if c.ran {
+		static.WriteString(truncStyle.Render("  "+components.HelpKeyStyle.Render(c.query.URI)) + "\n")
+		switch {
+		case c.loading:
+			static.WriteString(components.WarningStyle.Render("  ● Querying...") + "\n")
+		case c.resultErr == nil:
+			static.WriteString(components.StatusMuted.Render(fmt.Sprintf("  %d row(s)", len(c.result.Rows))) + "\n")
+		}
+		if !c.browsing {
+			static.WriteString(truncStyle.Render("  "+components.StatusMuted.Render(c.query.Command())) + "\n")
+		}
+	}
+	if c.writing {
+		static.WriteString(components.WarningStyle.Render("  ● Writing...") + "\n")
+	}
+	if c.err != nil {
+		static.WriteString(truncStyle.Render(components.ErrorStyle.Render("  "+c.err.Error())) + "\n")
+	}
+
+	var body strings.Builder
+	switch {
+	case c.browsing:
+		body.WriteString(c.renderSources(truncStyle))
+	case c.resultErr != nil:
+		body.WriteString(lipgloss.NewStyle().Width(maxWidth).Render(components.ErrorStyle.Render(c.resultErr.Error())))
+	case c.loading:
+		body.WriteString(components.StatusMuted.Render("Loading..."))
+	case len(c.result.Columns) == 0:
+		body.WriteString(components.StatusMuted.Render("No rows"))
+	default:
+		body.WriteString(renderTable(c.result.Columns, c.result.Rows, c.colOffset, truncStyle))
+	}
+
+	var footer string
+	if c.browsing {
+		footer = components.Help("↑/↓", "navigate") + "  " +
+			components.Help("enter", "query") + "  " +
+			components.Help("e", "edit") + "  " +
+			components.Help("n", "new query") + "  " +
+			components.Help("i", "insert") + "  "
+		if c.ran {
+			footer += components.Help("tab", "result") + "  "
+		}
+		footer += components.Help("esc", "back")
+	} else {
+		footer = components.Help("h/l", "columns") + "  " +
+			components.Help("e", "edit query") + "  " +
+			components.Help("n", "new") + "  " +
+			components.Help("r", "re-run") + "  " +
+			components.Help("i/u/d", "insert/update/delete") + "  " +
+			components.Help("esc", "uris")
+	}
+
+	rendered := components.RenderLayoutWithScrollableSection(c.state, components.LayoutWithScrollProps{
+		Title:             "Content Providers",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &c.viewport,
+	})
+
+	if c.queryForm.Visible {
+		rendered = components.RenderFormOverlay(rendered, c.queryForm, c.state)
+	}
+
+	if c.writeForm.Visible {
+		rendered = components.RenderFormOverlay(rendered, c.writeForm, c.state)
+	}
+
+	if c.confirm.Visible {
+		rendered = components.RenderOverlay(rendered, c.confirm.View(), c.state)
+	}
+
+	if c.toast.Visible {
+		rendered = components.RenderOverlay(rendered, c.toast.View(), c.state)
+	}
+
+	return rendered
+}
+
+// cursorLine is the cursor's line in renderSources, below the section
+// headings.
+func (c *Content) cursorLine() int {
+	line := c.cursor + 1
+	if len(c.history) > 0 && c.cursor >= len(c.history) {
+		line += 2
+	}
+	return line
+}
+
+// renderSources lists recent URIs, then the presets.
+func (c *Content) renderSources(truncStyle lipgloss.Style) string {
+	var out strings.Builder
+	for idx, s := range c.sources() {
+		if idx == 0 && len(c.history) > 0 {
+			out.WriteString(components.StatusMuted.Render("Recent") + "\n")
+		}
+		if idx == len(c.history) {
+			if idx > 0 {
+				out.WriteString("\n")
+			}
+			out.WriteString(components.StatusMuted.Render("Presets") + "\n")
+		}
+
+		prefix, style := "  ", components.ListItemStyle
+		if idx == c.cursor {
+			prefix, style = "› ", components.ListItemSelectedStyle
+		}
+
+		line := prefix + style.Render(s.query.URI)
+		if s.name != "" {
+			line = prefix + style.Render(s.name) + "  " + components.StatusMuted.Render(s.query.URI)
+		}
+		out.WriteString(truncStyle.Render(line) + "\n")
+	}
+	return out.String()
+}
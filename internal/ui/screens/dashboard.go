@@ -27,7 +27,7 @@ type Dashboard struct {
 }
 
 func NewDashboard(appState *state.AppState) *Dashboard {
-	return &Dashboard{
+	d := &Dashboard{
 		state: appState,
 		menuItems: []menuItem{
 			{"d", "Devices", "View and select connected devices", navigation.ActionDevices, false},
@@ -37,9 +37,22 @@ func NewDashboard(appState *state.AppState) *Dashboard {
 			{"f", "Files", "Browse device file system", navigation.ActionFiles, true},
 			{"m", "Monitor", "Performance stats (CPU, RAM, Net)", navigation.ActionPerfMonitor, true},
 			{"t", "Intent Tester", "Test deep links and intents", navigation.ActionIntents, true},
+			{"c", "Content Providers", "Query and edit content providers", navigation.ActionContent, true},
 			{"p", "Port Forwarding", "Manage adb port forwarding", navigation.ActionPorts, true},
+			{"r", "System Trace", "Capture Perfetto traces", navigation.ActionTrace, true},
+			{"!", "Alerts", "Threshold alerts on device metrics", navigation.ActionAlerts, false},
 		},
 	}
+
+	if p := appState.Project; p != nil {
+		description := "Install and launch builds of this Android project"
+		if id := p.ApplicationID(); id != "" {
+			description = "Install and launch " + id + " from build outputs"
+		}
+		d.menuItems = append(d.menuItems, menuItem{"b", "Project", description, navigation.ActionProject, false})
+	}
+
+	return d
 }
 
 func (d *Dashboard) Init() tea.Cmd {
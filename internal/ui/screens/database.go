@@ -0,0 +1,410 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/sqlite"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const dbPageSize = 50
+
+type dbMode int
+
+const (
+	dbTables dbMode = iota
+	dbRows
+	dbQuery
+)
+
+type dbTablesMsg struct {
+	tables []sqlite.Table
+	err    error
+}
+
+type dbResultMsg struct {
+	result sqlite.Result
+	err    error
+}
+
+type databaseClosedMsg struct{}
+
+// DatabaseView browses a database pulled from the device. It is opened
+// from the Files screen and keeps its copy in a temp dir until closed.
+type DatabaseView struct {
+	state   *state.AppState
+	remote  string
+	sandbox *adb.Sandbox
+
+	tmpDir  string
+	db      *sqlite.DB
+	pulling bool
+	pulled  time.Time
+	err     error
+
+	mode      dbMode
+	tables    []sqlite.Table
+	cursor    int
+	table     string
+	page      int
+	colOffset int
+	query     string
+	result    sqlite.Result
+	resultErr error
+
+	form     components.FormModal
+	toast    components.Toast
+	viewport viewport.Model
+}
+
+func NewDatabaseView(state *state.AppState, remote string, sandbox *adb.Sandbox) *DatabaseView {
+	return &DatabaseView{
+		state:    state,
+		remote:   remote,
+		sandbox:  sandbox,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (d *DatabaseView) Init() tea.Cmd {
+	return d.pull()
+}
+
+// Cleanup removes the local copy.
+func (d *DatabaseView) Cleanup() {
+	if d.tmpDir != "" {
+		os.RemoveAll(d.tmpDir)
+		d.tmpDir = ""
+	}
+}
+
+func (d *DatabaseView) pull() tea.Cmd {
+	if d.tmpDir == "" {
+		dir, err := os.MkdirTemp("", "adbt-db-")
+		if err != nil {
+			d.err = err
+			return nil
+		}
+		d.tmpDir = dir
+	}
+	d.pulling = true
+	return adb.PullDatabaseCmd(d.state.DeviceSerial(), d.sandbox, d.remote, d.tmpDir)
+}
+
+func (d *DatabaseView) loadTables() tea.Cmd {
+	db := d.db
+	return func() tea.Msg {
+		tables, err := db.Tables()
+		return dbTablesMsg{tables: tables, err: err}
+	}
+}
+
+// loadResult re-runs whatever the current mode shows.
+func (d *DatabaseView) loadResult() tea.Cmd {
+	db := d.db
+	switch d.mode {
+	case dbRows:
+		table, offset := d.table, d.page*dbPageSize
+		return func() tea.Msg {
+			res, err := db.Page(table, dbPageSize, offset)
+			return dbResultMsg{result: res, err: err}
+		}
+	case dbQuery:
+		query := d.query
+		return func() tea.Msg {
+			res, err := db.Query(query)
+			return dbResultMsg{result: res, err: err}
+		}
+	}
+	return nil
+}
+
+func (d *DatabaseView) selectedTable() *sqlite.Table {
+	if d.cursor < len(d.tables) {
+		return &d.tables[d.cursor]
+	}
+	return nil
+}
+
+func (d *DatabaseView) InputActive() bool {
+	return d.form.Visible
+}
+
+func (d *DatabaseView) Update(msg tea.Msg) tea.Cmd {
+	d.toast.Update(msg)
+
+	if d.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			d.form.Hide()
+			if len(msg.Values) == 0 || strings.TrimSpace(msg.Values[0]) == "" || d.db == nil {
+				return nil
+			}
+			d.query = strings.TrimSpace(msg.Values[0])
+			d.mode = dbQuery
+			d.colOffset = 0
+			d.viewport.GotoTop()
+			return d.loadResult()
+		case components.FormCancelMsg:
+			d.form.Hide()
+			return nil
+		}
+		return d.form.Update(msg)
+	}
+
+	switch msg := msg.(type) {
+	case adb.DatabasePulledMsg:
+		if msg.Remote != d.remote {
+			return nil
+		}
+		d.pulling = false
+		d.err = msg.Error
+		if msg.Error != nil {
+			return nil
+		}
+		d.pulled = time.Now()
+		d.db, d.err = sqlite.Open(msg.Local)
+		if d.err != nil {
+			return nil
+		}
+		return tea.Batch(d.loadTables(), d.loadResult())
+
+	case dbTablesMsg:
+		d.err = msg.err
+		d.tables = msg.tables
+		if d.cursor >= len(d.tables) {
+			d.cursor = max(len(d.tables)-1, 0)
+		}
+
+	case dbResultMsg:
+		d.result = msg.result
+		d.resultErr = msg.err
+
+	case tea.KeyMsg:
+		return d.handleKey(msg)
+	}
+
+	return nil
+}
+
+func (d *DatabaseView) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		if d.mode != dbTables {
+			d.mode = dbTables
+			d.viewport.GotoTop()
+			return consumeKeyCmd()
+		}
+		return func() tea.Msg { return databaseClosedMsg{} }
+
+	case "r":
+		if d.pulling {
+			return nil
+		}
+		var cmd tea.Cmd
+		d.toast, cmd = components.ShowToast("Pulling "+path.Base(d.remote)+"...", false, time.Second)
+		return tea.Batch(cmd, d.pull())
+
+	case "s":
+		if d.db == nil {
+			return nil
+		}
+		query := d.query
+		if query == "" {
+			if t := d.selectedTable(); t != nil {
+				query = fmt.Sprintf("SELECT * FROM %s LIMIT %d;", sqlite.Ident(t.Name), dbPageSize)
+			}
+		}
+		d.form.Show("SQL Query (read-only)", []components.FormField{
+			{Label: "SQL", Value: query},
+		})
+		return nil
+	}
+
+	if d.mode == dbTables {
+		switch msg.String() {
+		case "up", "k":
+			if d.cursor > 0 {
+				d.cursor--
+				ensureViewportLineVisible(&d.viewport, d.cursor)
+			}
+		case "down", "j":
+			if d.cursor < len(d.tables)-1 {
+				d.cursor++
+				ensureViewportLineVisible(&d.viewport, d.cursor)
+			}
+		case "enter":
+			if t := d.selectedTable(); t != nil && d.db != nil {
+				d.mode = dbRows
+				d.table = t.Name
+				d.page = 0
+				d.colOffset = 0
+				d.result = sqlite.Result{}
+				d.viewport.GotoTop()
+				return d.loadResult()
+			}
+		default:
+			return d.updateViewport(msg)
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "right", "n":
+		if d.mode == dbRows && len(d.result.Rows) == dbPageSize {
+			d.page++
+			d.viewport.GotoTop()
+			return d.loadResult()
+		}
+	case "left", "p":
+		if d.mode == dbRows && d.page > 0 {
+			d.page--
+			d.viewport.GotoTop()
+			return d.loadResult()
+		}
+	case "l":
+		if d.colOffset < len(d.result.Columns)-1 {
+			d.colOffset++
+		}
+	case "h":
+		if d.colOffset > 0 {
+			d.colOffset--
+		}
+	default:
+		return d.updateViewport(msg)
+	}
+	return nil
+}
+
+func (d *DatabaseView) View() string {
+	maxWidth := d.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	source := d.remote
+	if d.sandbox != nil {
+		source = "run-as " + d.sandbox.Package + ": " + source
+	}
+	static.WriteString(truncStyle.Render(components.TitleStyle.Render(path.Base(d.remote))+"  "+components.StatusMuted.Render(source)) + "\n")
+
+	switch {
+	case d.pulling:
+		static.WriteString(components.WarningStyle.Render("  ● Pulling database...") + "\n")
+	case !d.pulled.IsZero():
+		static.WriteString(components.StatusMuted.Render("  Pulled at "+d.pulled.Format("15:04:05")) + "\n")
+	}
+
+	switch d.mode {
+	case dbRows:
+		rows := "no rows"
+		if n := len(d.result.Rows); n > 0 {
+			rows = fmt.Sprintf("rows %d-%d", d.page*dbPageSize+1, d.page*dbPageSize+n)
+		}
+		static.WriteString("  " + components.HelpKeyStyle.Render(d.table) + " " + components.StatusMuted.Render(rows) + "\n")
+	case dbQuery:
+		static.WriteString(truncStyle.Render("  "+components.HelpKeyStyle.Render("query: ")+strings.Join(strings.Fields(d.query), " ")) + "\n")
+	}
+
+	var body strings.Builder
+	switch {
+	case d.err != nil:
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render(d.err.Error())))
+	case d.mode == dbTables:
+		body.WriteString(d.renderTables(truncStyle))
+	case d.resultErr != nil:
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render(d.resultErr.Error())))
+	default:
+		body.WriteString(d.renderResult(truncStyle))
+	}
+
+	var footer string
+	if d.mode == dbTables {
+		footer = components.Help("↑/↓", "navigate") + "  " +
+			components.Help("enter", "rows") + "  "
+	} else {
+		if d.mode == dbRows {
+			footer = components.Help("←/→", "page") + "  "
+		}
+		footer += components.Help("h/l", "columns") + "  "
+	}
+	footer += components.Help("s", "sql") + "  " +
+		components.Help("r", "re-pull") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(d.state, components.LayoutWithScrollProps{
+		Title:             "Database",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &d.viewport,
+	})
+
+	if d.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, d.form, d.state)
+	}
+
+	if d.toast.Visible {
+		rendered = components.RenderOverlay(rendered, d.toast.View(), d.state)
+	}
+
+	return rendered
+}
+
+// renderTables lists tables and views, with the selected one's schema
+// beneath it.
+func (d *DatabaseView) renderTables(truncStyle lipgloss.Style) string {
+	if len(d.tables) == 0 {
+		if d.pulling {
+			return components.StatusMuted.Render("Loading...")
+		}
+		return components.StatusMuted.Render("No tables")
+	}
+
+	var out strings.Builder
+	for i, t := range d.tables {
+		prefix, style := "  ", components.ListItemStyle
+		if i == d.cursor {
+			prefix, style = "› ", components.ListItemSelectedStyle
+		}
+
+		line := prefix + style.Render(t.Name) + " " + components.StatusMuted.Render(fmt.Sprintf("%d rows", t.Rows))
+		if t.Type == "view" {
+			line += " " + components.WarningStyle.Render("view")
+		}
+		out.WriteString(truncStyle.Render(line) + "\n")
+
+		if i == d.cursor && t.Schema != "" {
+			for _, schemaLine := range strings.Split(t.Schema, "\n") {
+				out.WriteString(truncStyle.Render("      "+components.StatusMuted.Render(strings.TrimSpace(schemaLine))) + "\n")
+			}
+		}
+	}
+	return out.String()
+}
+
+// renderResult draws rows as fixed-width columns starting at colOffset.
+func (d *DatabaseView) renderResult(truncStyle lipgloss.Style) string {
+	if len(d.result.Columns) == 0 {
+		return components.StatusMuted.Render("No rows")
+	}
+	return renderTable(d.result.Columns, d.result.Rows, d.colOffset, truncStyle)
+}
+
+func (d *DatabaseView) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return cmd
+}
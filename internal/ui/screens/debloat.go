@@ -0,0 +1,440 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/debloat"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxJournalRows is how many recent journal entries are listed.
+const maxJournalRows = 30
+
+type debloatStep struct {
+	pkg    string
+	action debloat.Action
+}
+
+type Debloat struct {
+	state  *state.AppState
+	cursor int
+
+	profiles []debloat.Profile
+	journal  []debloat.Entry
+	loadErr  error
+
+	// Steps still to run, current first.
+	steps    []debloatStep
+	running  string
+	total    int
+	failures int
+
+	form    components.FormModal
+	confirm components.ConfirmPrompt
+	pending string
+	toast   components.Toast
+
+	viewport viewport.Model
+}
+
+func NewDebloat(state *state.AppState) *Debloat {
+	return &Debloat{
+		state:    state,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (d *Debloat) Init() tea.Cmd {
+	d.profiles, d.loadErr = debloat.LoadProfiles()
+	d.loadJournal()
+	return nil
+}
+
+func (d *Debloat) Cleanup() tea.Cmd {
+	d.steps = nil
+	d.running = ""
+	return nil
+}
+
+func (d *Debloat) loadJournal() {
+	if !d.state.HasDevice() {
+		d.journal = nil
+		return
+	}
+	journal, err := debloat.Journal(d.state.DeviceSerial())
+	d.journal = journal
+	if err != nil && d.loadErr == nil {
+		d.loadErr = err
+	}
+}
+
+func (d *Debloat) InputActive() bool {
+	return d.form.Visible
+}
+
+func (d *Debloat) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	d.toast.Update(msg)
+
+	if d.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			d.form.Hide()
+			return d, d.addProfile(msg.Values)
+		case components.FormCancelMsg:
+			d.form.Hide()
+			return d, nil
+		}
+		return d, d.form.Update(msg)
+	}
+
+	if d.confirm.Visible {
+		switch msg.(type) {
+		case components.ConfirmYesMsg:
+			d.confirm.Hide()
+			return d, d.runPending()
+		case components.ConfirmNoMsg:
+			d.confirm.Hide()
+			d.pending = ""
+			return d, nil
+		}
+		return d, d.confirm.Update(msg)
+	}
+
+	switch msg := msg.(type) {
+	case adb.DebloatMsg:
+		if d.running == "" || msg.Profile != d.running {
+			return d, nil
+		}
+
+		if msg.Error != nil {
+			d.failures++
+		}
+		d.journal = append(d.journal, msg.Entry())
+
+		if len(d.steps) > 0 {
+			d.steps = d.steps[1:]
+		}
+		if len(d.steps) > 0 {
+			return d, d.runStep()
+		}
+		return d, d.finish()
+
+	case tea.KeyMsg:
+		if d.running != "" {
+			// Leaving now would stop the profile halfway, since the
+			// next step is only started from here.
+			if msg.String() == "esc" {
+				var cmd tea.Cmd
+				d.toast, cmd = components.ShowToast(
+					"Wait for the profile to finish",
+					true,
+					2*time.Second,
+				)
+				return d, cmd
+			}
+			return d, d.updateViewport(msg)
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if d.cursor > 0 {
+				d.cursor--
+			}
+		case "down", "j":
+			if d.cursor < len(d.profiles)-1 {
+				d.cursor++
+			}
+		case "a":
+			d.showForm()
+		case "enter":
+			if p := d.selected(); p != nil && d.state.HasDevice() {
+				d.pending = "apply"
+				d.confirm.Show(fmt.Sprintf(
+					"Apply profile %q:\n%s %d package(s) for user 0",
+					p.Name, p.Action, len(p.Packages),
+				))
+			}
+		case "R":
+			if p := d.selected(); p != nil && d.state.HasDevice() {
+				d.pending = "rollback"
+				d.confirm.Show(fmt.Sprintf("Roll back profile %q", p.Name))
+			}
+		case "d":
+			if p := d.selected(); p != nil {
+				d.pending = "delete"
+				d.confirm.Show(fmt.Sprintf("Delete profile %q", p.Name))
+			}
+		case "r":
+			d.loadJournal()
+		case "esc":
+			return d, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "apps"}
+			}
+		default:
+			return d, d.updateViewport(msg)
+		}
+	}
+
+	return d, nil
+}
+
+func (d *Debloat) selected() *debloat.Profile {
+	if d.cursor < len(d.profiles) {
+		return &d.profiles[d.cursor]
+	}
+	return nil
+}
+
+func (d *Debloat) showForm() {
+	pkg := d.state.SelectedPackage
+	d.form.Show("Add Debloat Profile", []components.FormField{
+		{Label: "Name", Placeholder: "e.g. carrier bloat"},
+		{Label: "Action", Type: components.FormFieldSelect, Options: []string{string(debloat.Disable), string(debloat.Uninstall)}, Value: string(debloat.Disable)},
+		{Label: "Packages", Value: pkg, Placeholder: "comma separated package names"},
+	})
+}
+
+func (d *Debloat) addProfile(values []string) tea.Cmd {
+	if len(values) < 3 {
+		return nil
+	}
+
+	profile := debloat.Profile{
+		Name:   strings.TrimSpace(values[0]),
+		Action: debloat.Action(values[1]),
+	}
+	for _, pkg := range strings.Split(values[2], ",") {
+		if pkg = strings.TrimSpace(pkg); pkg != "" {
+			profile.Packages = append(profile.Packages, pkg)
+		}
+	}
+
+	err := profile.Validate()
+	for _, p := range d.profiles {
+		if err == nil && p.Name == profile.Name {
+			err = fmt.Errorf("profile %q already exists", p.Name)
+		}
+	}
+	if err == nil {
+		err = debloat.SaveProfiles(append(d.profiles, profile))
+	}
+	if err != nil {
+		var cmd tea.Cmd
+		d.toast, cmd = components.ShowToast(
+			"Invalid profile: "+err.Error(),
+			true,
+			3*time.Second,
+		)
+		return cmd
+	}
+
+	d.profiles = append(d.profiles, profile)
+	d.cursor = len(d.profiles) - 1
+	return nil
+}
+
+func (d *Debloat) runPending() tea.Cmd {
+	p := d.selected()
+	pending := d.pending
+	d.pending = ""
+	if p == nil {
+		return nil
+	}
+
+	switch pending {
+	case "apply":
+		d.steps = nil
+		for _, pkg := range p.Packages {
+			d.steps = append(d.steps, debloatStep{pkg: pkg, action: p.Action})
+		}
+		return d.start(p.Name)
+
+	case "rollback":
+		d.loadJournal()
+		undo := debloat.Rollback(d.journal, p.Name, d.state.SelectedUser)
+		d.steps = nil
+		for pkg, action := range undo {
+			d.steps = append(d.steps, debloatStep{pkg: pkg, action: action})
+		}
+		sort.Slice(d.steps, func(i, j int) bool {
+			return d.steps[i].pkg < d.steps[j].pkg
+		})
+		if len(d.steps) == 0 {
+			var cmd tea.Cmd
+			d.toast, cmd = components.ShowToast(
+				"Nothing to roll back for this user",
+				false,
+				2*time.Second,
+			)
+			return cmd
+		}
+		return d.start(p.Name)
+
+	case "delete":
+		profiles := append([]debloat.Profile{}, d.profiles[:d.cursor]...)
+		profiles = append(profiles, d.profiles[d.cursor+1:]...)
+		if err := debloat.SaveProfiles(profiles); err != nil {
+			var cmd tea.Cmd
+			d.toast, cmd = components.ShowToast(
+				"Failed to save profiles: "+err.Error(),
+				true,
+				3*time.Second,
+			)
+			return cmd
+		}
+		d.profiles = profiles
+		if d.cursor >= len(d.profiles) && d.cursor > 0 {
+			d.cursor--
+		}
+	}
+
+	return nil
+}
+
+func (d *Debloat) start(profile string) tea.Cmd {
+	d.running = profile
+	d.total = len(d.steps)
+	d.failures = 0
+	return d.runStep()
+}
+
+func (d *Debloat) runStep() tea.Cmd {
+	step := d.steps[0]
+	return adb.DebloatCmd(d.state.DeviceSerial(), d.state.SelectedUser, step.pkg, step.action, d.running)
+}
+
+func (d *Debloat) finish() tea.Cmd {
+	name := d.running
+	d.running = ""
+	d.steps = nil
+
+	var cmd tea.Cmd
+	d.toast, cmd = components.ShowToast(
+		fmt.Sprintf("%s: %d changed, %d failed", name, d.total-d.failures, d.failures),
+		d.failures > 0,
+		3*time.Second,
+	)
+	return cmd
+}
+
+func (d *Debloat) View() string {
+	maxWidth := d.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	if d.running != "" {
+		static.WriteString(components.WarningStyle.Render(fmt.Sprintf(
+			"  ● %s: %d/%d", d.running, d.total-len(d.steps)+1, d.total,
+		)) + "\n")
+	}
+
+	var body strings.Builder
+	body.WriteString(components.TitleStyle.Render("Profiles") + "\n")
+
+	if d.loadErr != nil {
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render("  "+d.loadErr.Error())) + "\n")
+	}
+	if len(d.profiles) == 0 {
+		body.WriteString(components.StatusMuted.Render("  No profiles yet, press [a] to add one") + "\n")
+	}
+
+	for i, p := range d.profiles {
+		prefix := "  "
+		style := components.ListItemStyle
+		if i == d.cursor {
+			prefix = "› "
+			style = components.ListItemSelectedStyle
+		}
+
+		line := prefix + style.Render(p.Name) + " " + components.StatusMuted.Render(fmt.Sprintf(
+			"%s %d: %s", p.Action, len(p.Packages), strings.Join(p.Packages, ", "),
+		))
+		body.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	body.WriteString("\n" + components.TitleStyle.Render("Journal") + "\n")
+
+	if !d.state.HasDevice() {
+		body.WriteString(components.StatusMuted.Render("  Select a device to see its journal") + "\n")
+	} else if len(d.journal) == 0 {
+		body.WriteString(components.StatusMuted.Render("  No changes recorded for this device") + "\n")
+	}
+
+	journal := d.journal
+	if len(journal) > maxJournalRows {
+		journal = journal[len(journal)-maxJournalRows:]
+	}
+	for i := len(journal) - 1; i >= 0; i-- {
+		e := journal[i]
+
+		action := components.StatusConnected.Render(fmt.Sprintf("%-9s", e.Action))
+		if e.Error != "" {
+			action = components.ErrorStyle.Render(fmt.Sprintf("%-9s", e.Action))
+		}
+
+		line := fmt.Sprintf(
+			"  %s  %s %s",
+			components.StatusMuted.Render(e.Time.Format("2006-01-02 15:04")),
+			action,
+			e.Package,
+		)
+		if e.User != 0 {
+			line += " " + components.StatusMuted.Render(fmt.Sprintf("user %d", e.User))
+		}
+		if e.Profile != "" {
+			line += " " + components.StatusMuted.Render("("+e.Profile+")")
+		}
+		if e.Error != "" {
+			line += " " + components.ErrorStyle.Render(e.Error)
+		}
+		body.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	footer := components.Help("↑/↓", "navigate") + "  " +
+		components.Help("enter", "apply") + "  " +
+		components.Help("R", "roll back") + "  " +
+		components.Help("a", "add") + "  " +
+		components.Help("d", "delete") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(d.state, components.LayoutWithScrollProps{
+		Title:             "Debloat",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &d.viewport,
+	})
+
+	if d.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, d.form, d.state)
+	}
+
+	if d.confirm.Visible {
+		rendered = components.RenderOverlay(rendered, d.confirm.View(), d.state)
+	}
+
+	if d.toast.Visible {
+		rendered = components.RenderOverlay(rendered, d.toast.View(), d.state)
+	}
+
+	return rendered
+}
+
+func (d *Debloat) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return cmd
+}
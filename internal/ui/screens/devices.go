@@ -31,6 +31,10 @@ func (d *Devices) Init() tea.Cmd {
 	return adb.ListDevicesCmd()
 }
 
+func (d *Devices) InputActive() bool {
+	return d.form.Visible
+}
+
 func (d *Devices) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	d.toast.Update(msg)
 
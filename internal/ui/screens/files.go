@@ -3,11 +3,13 @@ package screens
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/SakshhamTheCoder/adbt/internal/adb"
 	"github.com/SakshhamTheCoder/adbt/internal/state"
 	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,9 +24,19 @@ type Files struct {
 
 	viewport viewport.Model
 
-	confirm  components.ConfirmPrompt
-	toast    components.Toast
-	pushForm components.FormModal
+	confirm     components.ConfirmPrompt
+	toast       components.Toast
+	pushForm    components.FormModal
+	sandboxForm components.FormModal
+
+	// sandbox is set while browsing an app's private directory through
+	// run-as. fromApps sends esc back to the app list.
+	sandbox  *adb.Sandbox
+	fromApps bool
+	loadErr  error
+
+	// db is the open database viewer, shown in place of the file list.
+	db *DatabaseView
 }
 
 func NewFiles(state *state.AppState) *Files {
@@ -35,14 +47,66 @@ func NewFiles(state *state.AppState) *Files {
 	}
 }
 
+// NewAppFiles opens the Files screen in the selected app's sandbox.
+func NewAppFiles(state *state.AppState) *Files {
+	f := NewFiles(state)
+	f.fromApps = true
+	if state.SelectedPackage != "" {
+		f.enterSandbox(state.SelectedPackage)
+	}
+	return f
+}
+
 func (f *Files) Init() tea.Cmd {
 	if !f.state.HasDevice() {
 		return nil
 	}
+	return f.list()
+}
+
+func (f *Files) enterSandbox(pkg string) {
+	f.sandbox = &adb.Sandbox{Package: pkg, User: f.state.SelectedUser}
+	f.path = f.sandbox.Root()
+	f.files = nil
+	f.cursor = 0
+}
+
+func (f *Files) leaveSandbox() {
+	f.sandbox = nil
+	f.path = "/sdcard"
+	f.files = nil
+	f.cursor = 0
+}
+
+func (f *Files) list() tea.Cmd {
+	if f.sandbox != nil {
+		return adb.ListSandboxFilesCmd(f.state.DeviceSerial(), *f.sandbox, f.path)
+	}
 	return adb.ListFilesCmd(f.state.DeviceSerial(), f.path)
 }
 
+// Cleanup removes the database viewer's local copy.
+func (f *Files) Cleanup() tea.Cmd {
+	if f.db != nil {
+		f.db.Cleanup()
+	}
+	return nil
+}
+
+func (f *Files) InputActive() bool {
+	return f.pushForm.Visible || f.sandboxForm.Visible || (f.db != nil && f.db.InputActive())
+}
+
 func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if f.db != nil {
+		if _, ok := msg.(databaseClosedMsg); ok {
+			f.db.Cleanup()
+			f.db = nil
+			return f, nil
+		}
+		return f, f.db.Update(msg)
+	}
+
 	f.toast.Update(msg)
 
 	if f.pushForm.Visible {
@@ -57,6 +121,17 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					false,
 					2*time.Second,
 				)
+				if f.sandbox != nil {
+					return f, tea.Batch(
+						toastCmd,
+						adb.PushSandboxFileCmd(
+							f.state.DeviceSerial(),
+							*f.sandbox,
+							expandHome(values[0]),
+							f.path,
+						),
+					)
+				}
 				return f, tea.Batch(
 					toastCmd,
 					adb.PushFileCmd(
@@ -74,12 +149,36 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return f, f.pushForm.Update(msg)
 	}
 
+	if f.sandboxForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			f.sandboxForm.Hide()
+			if len(msg.Values) > 0 && strings.TrimSpace(msg.Values[0]) != "" {
+				f.enterSandbox(strings.TrimSpace(msg.Values[0]))
+				f.gotoTop()
+				return f, f.list()
+			}
+			return f, nil
+		case components.FormCancelMsg:
+			f.sandboxForm.Hide()
+			return f, nil
+		}
+		return f, f.sandboxForm.Update(msg)
+	}
+
 	if f.confirm.Visible {
 		switch msg.(type) {
 
 		case components.ConfirmYesMsg:
 			entry := f.files[f.cursor]
 			f.confirm.Hide()
+			if f.sandbox != nil {
+				return f, adb.DeleteSandboxFileCmd(
+					f.state.DeviceSerial(),
+					*f.sandbox,
+					entry.Path,
+				)
+			}
 			return f, adb.DeleteFileCmd(
 				f.state.DeviceSerial(),
 				entry.Path,
@@ -120,22 +219,24 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				f.path = entry.Path
 				f.cursor = 0
 				f.gotoTop()
-				return f, adb.ListFilesCmd(
-					f.state.DeviceSerial(),
-					f.path,
-				)
+				return f, f.list()
+			}
+			if isDatabaseFile(entry) {
+				f.db = NewDatabaseView(f.state, entry.Path, f.sandbox)
+				return f, f.db.Init()
 			}
 
 		case "backspace":
+			// run-as cannot list anything above the app's data dir.
+			if f.sandbox != nil && f.path == f.sandbox.Root() {
+				return f, nil
+			}
 			parent := filepath.Dir(f.path)
 			if parent != f.path {
 				f.path = parent
 				f.cursor = 0
 				f.gotoTop()
-				return f, adb.ListFilesCmd(
-					f.state.DeviceSerial(),
-					f.path,
-				)
+				return f, f.list()
 			}
 
 		case "d":
@@ -173,6 +274,17 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				false,
 				2*time.Second,
 			)
+			if f.sandbox != nil {
+				return f, tea.Batch(
+					toastCmd,
+					adb.PullSandboxFileCmd(
+						f.state.DeviceSerial(),
+						*f.sandbox,
+						entry.Path,
+						localPath,
+					),
+				)
+			}
 			return f, tea.Batch(
 				toastCmd,
 				adb.PullFileCmd(
@@ -185,10 +297,26 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r":
 			f.cursor = 0
 			f.gotoTop()
-			return f, adb.ListFilesCmd(
-				f.state.DeviceSerial(),
-				f.path,
-			)
+			return f, f.list()
+
+		case "a":
+			if f.sandbox == nil {
+				f.sandboxForm.Show("App Sandbox (run-as)", []components.FormField{
+					{Label: "Package", Value: f.state.SelectedPackage, Placeholder: "debuggable app"},
+				})
+			}
+
+		case "esc":
+			if f.fromApps {
+				return f, func() tea.Msg {
+					return navigation.SwitchScreenMsg{Screen: "apps"}
+				}
+			}
+			if f.sandbox != nil {
+				f.leaveSandbox()
+				f.gotoTop()
+				return f, f.list()
+			}
 
 		case "u":
 			f.pushForm.Show("Push File", []components.FormField{
@@ -200,7 +328,12 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case adb.FilesLoadedMsg:
+		if msg.Path != f.path {
+			return f, nil
+		}
+		f.loadErr = msg.Error
 		if msg.Error != nil {
+			f.files = nil
 			var cmd tea.Cmd
 			f.toast, cmd = components.ShowToast(
 				"Failed to load files",
@@ -235,7 +368,7 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		)
 		return f, tea.Batch(
 			cmd,
-			adb.ListFilesCmd(f.state.DeviceSerial(), f.path),
+			f.list(),
 		)
 	}
 
@@ -246,8 +379,14 @@ func (f *Files) View() string {
 	if !f.state.HasDevice() {
 		return components.RenderNoDevice(f.state, "Files")
 	}
+	if f.db != nil {
+		return f.db.View()
+	}
 
 	var staticContent string
+	if f.sandbox != nil {
+		staticContent += components.WarningStyle.Render("run-as "+f.sandbox.Package) + "  "
+	}
 	staticContent += components.StatusMuted.Render("Path: "+f.path) + "\n"
 
 	if len(f.files) > 0 && f.cursor < len(f.files) {
@@ -260,6 +399,23 @@ func (f *Files) View() string {
 	}
 
 	body := components.FileList(f.files, f.cursor)
+	if f.loadErr != nil {
+		body = components.ErrorStyle.Render(f.loadErr.Error())
+		if f.sandbox != nil {
+			body += "\n" + components.StatusMuted.Render("run-as only works for debuggable apps")
+		}
+	}
+
+	footer := components.Help("enter", "open / view db") + "  " +
+		components.Help("backspace", "up") + "  " +
+		components.Help("p", "pull") + "  " +
+		components.Help("u", "push") + "  " +
+		components.Help("d", "delete") + "  " +
+		components.Help("r", "refresh") + "  "
+	if f.sandbox == nil {
+		footer += components.Help("a", "app sandbox") + "  "
+	}
+	footer += components.Help("esc", "back")
 
 	rendered := components.RenderLayoutWithScrollableSection(
 		f.state,
@@ -267,14 +423,8 @@ func (f *Files) View() string {
 			Title:             "Files",
 			StaticContent:     staticContent,
 			ScrollableContent: body,
-			Footer: components.Help("enter", "open") + "  " +
-				components.Help("backspace", "up") + "  " +
-				components.Help("p", "pull") + "  " +
-				components.Help("u", "push") + "  " +
-				components.Help("d", "delete") + "  " +
-				components.Help("r", "refresh") + "  " +
-				components.Help("esc", "back"),
-			Viewport: &f.viewport,
+			Footer:            footer,
+			Viewport:          &f.viewport,
 		},
 	)
 
@@ -282,6 +432,10 @@ func (f *Files) View() string {
 		rendered = components.RenderFormOverlay(rendered, f.pushForm, f.state)
 	}
 
+	if f.sandboxForm.Visible {
+		rendered = components.RenderFormOverlay(rendered, f.sandboxForm, f.state)
+	}
+
 	if f.confirm.Visible {
 		rendered = components.RenderOverlay(rendered, f.confirm.View(), f.state)
 	}
@@ -293,6 +447,18 @@ func (f *Files) View() string {
 	return rendered
 }
 
+// isDatabaseFile matches SQLite files by extension, and extensionless
+// files in an app's databases dir.
+func isDatabaseFile(entry adb.FileEntry) bool {
+	switch filepath.Ext(entry.Name) {
+	case ".db", ".sqlite", ".sqlite3", ".db3":
+		return true
+	case "":
+		return filepath.Base(filepath.Dir(entry.Path)) == "databases"
+	}
+	return false
+}
+
 func (f *Files) updateViewport(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 	f.viewport, cmd = f.viewport.Update(msg)
@@ -1,24 +1,28 @@
 package screens
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/intents"
 	"github.com/SakshhamTheCoder/adbt/internal/state"
 	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-type intentMode int
+var intentModeNames = []string{"Activity", "Broadcast", "Service", "Foreground Service"}
 
-const (
-	intentModeActivity intentMode = iota
-	intentModeBroadcast
-)
-
-var intentModeNames = []string{"Activity", "Broadcast"}
+var intentFormTitles = []string{"Start Activity", "Send Broadcast", "Start Service", "Start Foreground Service"}
 
 var intentActionSuggestions = []string{
 	"android.intent.action.VIEW",
@@ -35,118 +39,351 @@ var intentActionSuggestions = []string{
 	"android.settings.APPLICATION_DETAILS_SETTINGS",
 }
 
+const (
+	intentViewBuilder = iota
+	intentViewHistory
+	intentViewFavorites
+)
+
+var intentViewNames = []string{"Builder", "History", "Favorites"}
+
 type Intents struct {
 	state *state.AppState
 
 	form components.FormModal
-	mode intentMode
-
-	lastOutput string
-	toast      components.Toast
+	// mode indexes adb.IntentModes.
+	mode int
+	// values are the last submitted form values, kept for editing.
+	values []string
+	// preview is the intent waiting to be sent.
+	preview *adb.Intent
+	sending bool
+	// sent is the intent in flight, recorded with its result.
+	sent *intents.Spec
+
+	view      int
+	history   []intents.Entry
+	favorites []intents.Favorite
+	loadErr   error
+	cursor    int
+	search    components.SearchState
+
+	// replay is the saved intent waiting for its placeholder values.
+	replay          *intents.Spec
+	placeholderForm components.FormModal
+	saveForm        components.FormModal
+	saveSpec        intents.Spec
+	fileForm        components.FormModal
+	fileAction      string
+	confirm         components.ConfirmPrompt
+
+	lastCommand string
+	lastMode    adb.IntentMode
+	lastOutput  string
+	lastResult  adb.StartResult
+	lastFailed  bool
+	toast       components.Toast
+	viewport    viewport.Model
 }
 
 func NewIntents(state *state.AppState) *Intents {
 	i := &Intents{
-		state: state,
+		state:    state,
+		viewport: viewport.New(0, 0),
 	}
-	i.showForm()
+	i.showForm(nil)
 	return i
 }
 
-func (i *Intents) showForm() {
-	title := "Start Activity"
-	if i.mode == intentModeBroadcast {
-		title = "Send Broadcast"
+// showForm opens the intent form, filled with values when editing a
+// previous intent.
+func (i *Intents) showForm(values []string) {
+	user := ""
+	if i.state.SelectedUser != 0 {
+		user = strconv.Itoa(i.state.SelectedUser)
 	}
-	i.form.Show(title, []components.FormField{
+
+	fields := []components.FormField{
 		{
-			Label:       "Action",
+			Label:       "Action (-a)",
 			Value:       "android.intent.action.VIEW",
 			Type:        components.FormFieldAutocomplete,
 			Suggestions: intentActionSuggestions,
 		},
 		{
-			Label:       "Data URI",
+			Label:       "Data URI (-d)",
 			Placeholder: "https://...  geo:...  tel:...  mailto:...",
 		},
-		{Label: "Extras (k=v;k2=v2)"},
-	})
+		{Label: "MIME Type (-t)", Placeholder: "e.g. text/plain"},
+		{Label: "Component (-n)", Placeholder: "com.example/.MainActivity"},
+		{Label: "Package (-p)", Placeholder: "limit to one app"},
+		{Label: "Categories (-c)", Placeholder: "comma separated, e.g. android.intent.category.BROWSABLE"},
+		{Label: "Flags", Placeholder: "0x10000000, clear-top, single-top, receiver-foreground"},
+		{Label: "Extras", Placeholder: adb.ExtrasSyntax},
+		{Label: "User", Value: user, Placeholder: "current user, an id, or all for broadcasts"},
+	}
+	if values != nil {
+		for idx := range fields {
+			fields[idx].Value = ""
+			if idx < len(values) {
+				fields[idx].Value = values[idx]
+			}
+		}
+	}
+	i.form.Show(intentFormTitles[i.mode], fields)
+}
+
+// builderSpec is the form's intent in the current mode.
+func (i *Intents) builderSpec() intents.Spec {
+	return intents.SpecFromValues(string(adb.IntentModes[i.mode]), i.values)
+}
+
+// buildIntent parses a spec's categories, flags and extras into an
+// intent. Unfilled placeholders are an error.
+func buildIntent(spec intents.Spec) (adb.Intent, error) {
+	if names := spec.Placeholders(); len(names) > 0 {
+		return adb.Intent{}, fmt.Errorf("fill in {{%s}} first", names[0])
+	}
+
+	intent := adb.Intent{
+		Mode:      adb.IntentMode(spec.Mode),
+		Action:    spec.Action,
+		Data:      spec.Data,
+		MimeType:  spec.MimeType,
+		Component: spec.Component,
+		Package:   spec.Package,
+		User:      spec.User,
+	}
+	for _, c := range strings.Split(spec.Categories, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			intent.Categories = append(intent.Categories, c)
+		}
+	}
+
+	var err error
+	if intent.Flags, intent.NamedFlags, err = adb.ParseIntentFlags(spec.Flags); err != nil {
+		return adb.Intent{}, err
+	}
+	if intent.Extras, err = adb.ParseExtras(spec.Extras); err != nil {
+		return adb.Intent{}, err
+	}
+	return intent, intent.Validate()
+}
+
+// updatePreview rebuilds the preview from the form values, e.g. after a
+// mode switch. An intent the new mode rejects is dropped with a toast.
+// Intents with placeholders have no preview but can still be saved.
+func (i *Intents) updatePreview() tea.Cmd {
+	spec := i.builderSpec()
+	if len(spec.Placeholders()) > 0 {
+		i.preview = nil
+		return nil
+	}
+	intent, err := buildIntent(spec)
+	if err != nil {
+		i.preview = nil
+		return i.showError(err)
+	}
+	i.preview = &intent
+	return nil
 }
 
 func (i *Intents) Init() tea.Cmd {
+	i.loadLibrary()
 	return nil
 }
 
+func (i *Intents) loadLibrary() {
+	i.favorites, i.loadErr = intents.LoadFavorites()
+	if i.loadErr == nil {
+		i.history, i.loadErr = intents.History()
+	}
+}
+
+// projectName scopes favorites to the Android project adbt runs in.
+func (i *Intents) projectName() string {
+	if i.state.Project == nil {
+		return ""
+	}
+	return filepath.Base(i.state.Project.Root)
+}
+
+// libraryItem is a history entry or a favorite.
+type libraryItem struct {
+	title string
+	spec  intents.Spec
+	entry *intents.Entry
+	fav   *intents.Favorite
+}
+
+// items lists the current view's entries, best fuzzy matches first while
+// searching.
+func (i *Intents) items() []libraryItem {
+	var items []libraryItem
+	switch i.view {
+	case intentViewHistory:
+		for idx := range i.history {
+			e := &i.history[idx]
+			items = append(items, libraryItem{title: strings.TrimPrefix(e.Command, "adb shell "), spec: e.Spec, entry: e})
+		}
+	case intentViewFavorites:
+		project := i.projectName()
+		for idx := range i.favorites {
+			f := &i.favorites[idx]
+			if f.Project != "" && f.Project != project {
+				continue
+			}
+			items = append(items, libraryItem{title: f.Name, spec: f.Spec, fav: f})
+		}
+	}
+
+	if i.search.Query == "" {
+		return items
+	}
+
+	type scored struct {
+		item  libraryItem
+		score int
+	}
+	var matches []scored
+	for _, item := range items {
+		if score, ok := intents.Match(i.search.Query, item.title+" "+item.spec.Summary()); ok {
+			matches = append(matches, scored{item, score})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool { return matches[a].score > matches[b].score })
+
+	items = items[:0]
+	for _, m := range matches {
+		items = append(items, m.item)
+	}
+	return items
+}
+
+func (i *Intents) selectedItem() *libraryItem {
+	items := i.items()
+	if i.cursor >= len(items) {
+		return nil
+	}
+	return &items[i.cursor]
+}
+
+func (i *Intents) InputActive() bool {
+	return i.search.Active || i.form.Visible || i.placeholderForm.Visible ||
+		i.saveForm.Visible || i.fileForm.Visible
+}
+
 func (i *Intents) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	i.toast.Update(msg)
 
 	if i.form.Visible {
 		switch msg := msg.(type) {
 		case components.FormSubmitMsg:
-			values := msg.Values
 			i.form.Hide()
-
-			action := ""
-			dataURI := ""
-			extras := ""
-			if len(values) > 0 {
-				action = values[0]
+			i.values = msg.Values
+			cmd := i.updatePreview()
+			if cmd != nil {
+				i.showForm(i.values)
 			}
-			if len(values) > 1 {
-				dataURI = values[1]
+			return i, cmd
+
+		case components.FormCancelMsg:
+			i.form.Hide()
+			return i, nil
+		}
+
+		return i, i.form.Update(msg)
+	}
+
+	if i.placeholderForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			i.placeholderForm.Hide()
+			if i.replay == nil {
+				return i, nil
 			}
-			if len(values) > 2 {
-				extras = values[2]
+			values := map[string]string{}
+			for idx, name := range i.replay.Placeholders() {
+				if idx < len(msg.Values) {
+					values[name] = strings.TrimSpace(msg.Values[idx])
+				}
 			}
+			spec := i.replay.Fill(values)
+			i.replay = nil
+			return i, i.send(spec)
 
-			if action == "" {
-				var cmd tea.Cmd
-				i.toast, cmd = components.ShowToast(
-					"Action is required",
-					true,
-					2*time.Second,
-				)
-				return i, cmd
-			}
+		case components.FormCancelMsg:
+			i.placeholderForm.Hide()
+			i.replay = nil
+			return i, nil
+		}
 
-			serial := i.state.DeviceSerial()
-			var toastCmd tea.Cmd
-			i.toast, toastCmd = components.ShowToast(
-				"Sending intent...",
-				false,
-				2*time.Second,
-			)
-
-			var intentCmd tea.Cmd
-			if i.mode == intentModeBroadcast {
-				intentCmd = adb.SendBroadcastCmd(serial, action, extras)
-			} else {
-				intentCmd = adb.SendIntentCmd(serial, action, dataURI, extras)
-			}
+		return i, i.placeholderForm.Update(msg)
+	}
 
-			return i, tea.Batch(toastCmd, intentCmd)
+	if i.saveForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			i.saveForm.Hide()
+			return i, i.saveFavorite(msg.Values)
 
 		case components.FormCancelMsg:
-			i.form.Hide()
+			i.saveForm.Hide()
 			return i, nil
 		}
 
-		return i, i.form.Update(msg)
+		return i, i.saveForm.Update(msg)
+	}
+
+	if i.fileForm.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			i.fileForm.Hide()
+			return i, i.runFileAction(msg.Values)
+
+		case components.FormCancelMsg:
+			i.fileForm.Hide()
+			return i, nil
+		}
+
+		return i, i.fileForm.Update(msg)
+	}
+
+	if i.confirm.Visible {
+		switch msg.(type) {
+		case components.ConfirmYesMsg:
+			i.confirm.Hide()
+			return i, i.deleteFavorite()
+
+		case components.ConfirmNoMsg:
+			i.confirm.Hide()
+			return i, nil
+		}
+
+		return i, i.confirm.Update(msg)
 	}
 
 	switch msg := msg.(type) {
 	case adb.IntentResultMsg:
-		i.lastOutput = msg.Output
+		var err error
+		if msg.Result.Failed() {
+			err = errors.New(msg.Result.Error)
+		}
+		i.finishSend(msg.Output, msg.Result, err)
+
 		var cmd tea.Cmd
-		i.toast, cmd = components.ShowToast(
-			"Intent sent successfully",
-			false,
-			2*time.Second,
-		)
+		switch {
+		case err != nil:
+			i.toast, cmd = components.ShowToast("Intent failed: "+msg.Result.Error, true, 3*time.Second)
+		case msg.Result.Warning != "":
+			i.toast, cmd = components.ShowToast("Intent sent with a warning", false, 3*time.Second)
+		default:
+			i.toast, cmd = components.ShowToast("Intent sent successfully", false, 2*time.Second)
+		}
 		return i, cmd
 
 	case adb.IntentErrorMsg:
-		i.lastOutput = msg.Error.Error()
+		i.finishSend(msg.Error.Error(), msg.Result, msg.Error)
 		var cmd tea.Cmd
 		i.toast, cmd = components.ShowToast(
 			"Intent failed",
@@ -156,60 +393,421 @@ func (i *Intents) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return i, cmd
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "n":
-			i.showForm()
-		case "left", "right":
-			i.mode = (i.mode + 1) % 2
-			i.showForm()
+		if i.search.Active {
+			before := i.search.Query
+			i.search.HandleKey(msg)
+			if i.search.Query != before {
+				i.cursor = 0
+				i.viewport.GotoTop()
+			}
+			return i, consumeKeyCmd()
+		}
+
+		if msg.String() == "tab" {
+			i.view = (i.view + 1) % len(intentViewNames)
+			i.cursor = 0
+			i.search.Clear()
+			i.viewport.GotoTop()
+			return i, nil
 		}
+
+		if i.view == intentViewBuilder {
+			return i, i.updateBuilder(msg)
+		}
+		return i, i.updateLibrary(msg)
 	}
 
 	return i, nil
 }
 
+func (i *Intents) updateBuilder(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter", "y":
+		if i.preview != nil {
+			return i.send(i.builderSpec())
+		}
+		if spec := i.builderSpec(); i.values != nil && len(spec.Placeholders()) > 0 {
+			return i.replaySpec(spec)
+		}
+	case "n":
+		i.preview = nil
+		i.showForm(nil)
+	case "e":
+		i.preview = nil
+		i.showForm(i.values)
+	case "s":
+		if i.values != nil {
+			i.showSaveForm(i.builderSpec())
+		}
+	case "left", "right":
+		if msg.String() == "right" {
+			i.mode = (i.mode + 1) % len(adb.IntentModes)
+		} else {
+			i.mode = (i.mode + len(adb.IntentModes) - 1) % len(adb.IntentModes)
+		}
+		if i.preview != nil {
+			return i.updatePreview()
+		}
+	case "esc":
+		if i.preview != nil {
+			i.preview = nil
+			return consumeKeyCmd()
+		}
+	default:
+		var cmd tea.Cmd
+		i.viewport, cmd = i.viewport.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (i *Intents) updateLibrary(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if i.cursor > 0 {
+			i.cursor--
+			ensureViewportLineVisible(&i.viewport, i.cursor)
+		}
+	case "down", "j":
+		if i.cursor < len(i.items())-1 {
+			i.cursor++
+			ensureViewportLineVisible(&i.viewport, i.cursor)
+		}
+	case "enter":
+		if item := i.selectedItem(); item != nil {
+			return i.replaySpec(item.spec)
+		}
+	case "e":
+		if item := i.selectedItem(); item != nil {
+			i.edit(item.spec)
+		}
+	case "s":
+		if item := i.selectedItem(); item != nil && item.entry != nil {
+			i.showSaveForm(item.spec)
+		}
+	case "d":
+		if item := i.selectedItem(); item != nil && item.fav != nil {
+			i.confirm.Show("Delete favorite:\n" + item.fav.Name)
+		}
+	case "x":
+		if i.view == intentViewFavorites {
+			i.fileAction = "export"
+			i.fileForm.Show("Export Favorites", []components.FormField{
+				{Label: "File", Value: defaultFavoritesFile(), Placeholder: ".yaml/.yml for YAML, JSON otherwise"},
+			})
+		}
+	case "i":
+		if i.view == intentViewFavorites {
+			i.fileAction = "import"
+			i.fileForm.Show("Import Favorites", []components.FormField{
+				{Label: "File", Value: defaultFavoritesFile(), Placeholder: "favorites .yaml or .json"},
+			})
+		}
+	case "/":
+		i.search.Start()
+		i.cursor = 0
+	case "r":
+		i.loadLibrary()
+		i.cursor = 0
+	case "esc":
+		if i.search.Query != "" {
+			i.search.Clear()
+			i.cursor = 0
+			return consumeKeyCmd()
+		}
+		i.view = intentViewBuilder
+		return consumeKeyCmd()
+	default:
+		var cmd tea.Cmd
+		i.viewport, cmd = i.viewport.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// replaySpec sends a saved intent to the current device, asking for its
+// placeholder values first.
+func (i *Intents) replaySpec(spec intents.Spec) tea.Cmd {
+	names := spec.Placeholders()
+	if len(names) == 0 {
+		return i.send(spec)
+	}
+
+	i.replay = &spec
+	fields := make([]components.FormField, len(names))
+	for idx, name := range names {
+		fields[idx] = components.FormField{Label: name}
+	}
+	i.placeholderForm.Show("Replay Intent", fields)
+	return nil
+}
+
+// edit loads a saved intent into the builder form.
+func (i *Intents) edit(spec intents.Spec) {
+	for idx, mode := range adb.IntentModes {
+		if string(mode) == spec.Mode {
+			i.mode = idx
+		}
+	}
+	i.values = spec.Values()
+	i.preview = nil
+	i.view = intentViewBuilder
+	i.showForm(i.values)
+}
+
+func (i *Intents) send(spec intents.Spec) tea.Cmd {
+	if i.sending || !i.state.HasDevice() {
+		return nil
+	}
+	intent, err := buildIntent(spec)
+	if err != nil {
+		return i.showError(err)
+	}
+
+	i.sending = true
+	i.sent = &spec
+	i.preview = nil
+	i.lastCommand = intent.Command()
+	i.lastMode = intent.Mode
+	i.lastOutput = ""
+	return adb.SendCmd(i.state.DeviceSerial(), intent)
+}
+
+// finishSend shows the result and records the intent in the history.
+func (i *Intents) finishSend(output string, result adb.StartResult, err error) {
+	i.sending = false
+	i.lastOutput = output
+	i.lastResult = result
+	i.lastFailed = err != nil
+	if i.sent == nil {
+		return
+	}
+
+	entry := intents.Entry{
+		Time:    time.Now(),
+		Serial:  i.state.DeviceSerial(),
+		Spec:    *i.sent,
+		Command: i.lastCommand,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	i.sent = nil
+	if recordErr := intents.Record(entry); recordErr != nil {
+		i.loadErr = recordErr
+	}
+	i.history = append([]intents.Entry{entry}, i.history...)
+	if len(i.history) > intents.HistoryLimit {
+		i.history = i.history[:intents.HistoryLimit]
+	}
+}
+
+// showSaveForm asks for a favorite's name and, inside a project, whether
+// it belongs to that project only.
+func (i *Intents) showSaveForm(spec intents.Spec) {
+	i.saveSpec = spec
+	fields := []components.FormField{
+		{Label: "Name", Placeholder: "e.g. Open order"},
+	}
+	if project := i.projectName(); project != "" {
+		fields = append(fields, components.FormField{
+			Label:   "Scope",
+			Type:    components.FormFieldSelect,
+			Options: []string{project, "All projects"},
+			Value:   project,
+		})
+	}
+	i.saveForm.Show("Save Favorite", fields)
+}
+
+func (i *Intents) saveFavorite(values []string) tea.Cmd {
+	if len(values) == 0 {
+		return nil
+	}
+	fav := intents.Favorite{Name: strings.TrimSpace(values[0]), Spec: i.saveSpec}
+	if len(values) > 1 && values[1] == i.projectName() {
+		fav.Project = values[1]
+	}
+	if err := fav.Validate(); err != nil {
+		return i.showError(err)
+	}
+
+	favorites, _, replaced := intents.Merge(i.favorites, []intents.Favorite{fav})
+	if err := intents.SaveFavorites(favorites); err != nil {
+		return i.showError(err)
+	}
+	i.favorites = favorites
+
+	text := "Saved " + fav.Name
+	if replaced > 0 {
+		text = "Replaced " + fav.Name
+	}
+	var cmd tea.Cmd
+	i.toast, cmd = components.ShowToast(text, false, 2*time.Second)
+	return cmd
+}
+
+func (i *Intents) deleteFavorite() tea.Cmd {
+	item := i.selectedItem()
+	if item == nil || item.fav == nil {
+		return nil
+	}
+
+	var kept []intents.Favorite
+	for _, f := range i.favorites {
+		if f.Name != item.fav.Name || f.Project != item.fav.Project {
+			kept = append(kept, f)
+		}
+	}
+	if err := intents.SaveFavorites(kept); err != nil {
+		return i.showError(err)
+	}
+	i.favorites = kept
+	if n := len(i.items()); i.cursor >= n {
+		i.cursor = max(n-1, 0)
+	}
+	return nil
+}
+
+func defaultFavoritesFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "adbt-intents.yaml"
+	}
+	return filepath.Join(home, "Downloads", "adbt-intents.yaml")
+}
+
+// runFileAction exports the listed favorites, or imports a file and
+// replaces favorites with the same name and project.
+func (i *Intents) runFileAction(values []string) tea.Cmd {
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return nil
+	}
+	path := expandHome(strings.TrimSpace(values[0]))
+
+	var text string
+	switch i.fileAction {
+	case "export":
+		var listed []intents.Favorite
+		for _, item := range i.items() {
+			listed = append(listed, *item.fav)
+		}
+		if err := intents.Export(path, listed); err != nil {
+			return i.showError(err)
+		}
+		text = fmt.Sprintf("Exported %d favorite(s) to %s", len(listed), path)
+
+	case "import":
+		imported, err := intents.Import(path)
+		if err != nil {
+			return i.showError(err)
+		}
+		favorites, added, replaced := intents.Merge(i.favorites, imported)
+		if err := intents.SaveFavorites(favorites); err != nil {
+			return i.showError(err)
+		}
+		i.favorites = favorites
+		text = fmt.Sprintf("Imported %d favorite(s), replaced %d", added, replaced)
+
+	default:
+		return nil
+	}
+
+	var cmd tea.Cmd
+	i.toast, cmd = components.ShowToast(text, false, 3*time.Second)
+	return cmd
+}
+
+func (i *Intents) showError(err error) tea.Cmd {
+	var cmd tea.Cmd
+	i.toast, cmd = components.ShowToast(err.Error(), true, 3*time.Second)
+	return cmd
+}
+
 func (i *Intents) View() string {
 	if !i.state.HasDevice() {
 		return components.RenderNoDevice(i.state, "Intents")
 	}
 
+	maxWidth := i.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	wrapStyle := lipgloss.NewStyle().Width(maxWidth)
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
 	var staticContent strings.Builder
 	staticContent.WriteString(components.TitleStyle.Render("Intent Tester") + "\n")
 
-	staticContent.WriteString("  ")
-	for idx, name := range intentModeNames {
-		if intentMode(idx) == i.mode {
-			staticContent.WriteString(components.HelpKeyStyle.Render(name))
-		} else {
-			staticContent.WriteString(components.StatusMuted.Render(name))
+	views := make([]string, len(intentViewNames))
+	copy(views, intentViewNames)
+	views[intentViewHistory] += fmt.Sprintf(" (%d)", len(i.history))
+	views[intentViewFavorites] += fmt.Sprintf(" (%d)", len(intents.ForProject(i.favorites, i.projectName())))
+	staticContent.WriteString(renderChoiceBar(views, i.view) + "\n")
+	if i.view == intentViewBuilder {
+		staticContent.WriteString(renderChoiceBar(intentModeNames, i.mode) + "\n")
+	}
+	staticContent.WriteString("\n")
+
+	if i.loadErr != nil {
+		staticContent.WriteString(truncStyle.Render(components.ErrorStyle.Render(i.loadErr.Error())) + "\n\n")
+	}
+
+	if i.sending {
+		staticContent.WriteString(components.WarningStyle.Render("● Sending...") + "\n\n")
+	} else if i.view == intentViewBuilder && i.preview != nil {
+		staticContent.WriteString(components.HelpKeyStyle.Render("Preview") + "\n")
+		staticContent.WriteString(wrapStyle.Render(i.preview.Command()) + "\n\n")
+	} else if i.view != intentViewBuilder && i.lastCommand != "" {
+		line := components.StatusConnected.Render("✓ " + i.lastCommand)
+		if i.lastFailed {
+			line = components.ErrorStyle.Render("✗ " + i.lastCommand)
 		}
-		if idx < len(intentModeNames)-1 {
-			staticContent.WriteString(components.StatusMuted.Render(" / "))
+		staticContent.WriteString(truncStyle.Render(line) + "\n\n")
+	}
+
+	if i.view != intentViewBuilder {
+		if i.search.Active {
+			staticContent.WriteString(components.HelpKeyStyle.Render("search: ") + i.search.Query + "▌\n")
+		} else if i.search.Query != "" {
+			staticContent.WriteString(components.StatusMuted.Render("filter: \""+i.search.Query+"\"") + "\n")
 		}
 	}
-	staticContent.WriteString("\n\n")
 
 	var scrollableContent strings.Builder
-	if i.lastOutput != "" {
-		scrollableContent.WriteString(components.StatusMuted.Render("Last Result:") + "\n")
-		scrollableContent.WriteString(i.lastOutput + "\n")
+	if i.view == intentViewBuilder {
+		if i.lastCommand != "" {
+			scrollableContent.WriteString(components.StatusMuted.Render("Last Command:") + "\n")
+			scrollableContent.WriteString(wrapStyle.Render(i.lastCommand) + "\n\n")
+		}
+		if i.lastOutput != "" {
+			scrollableContent.WriteString(components.StatusMuted.Render("Last Result:") + "\n")
+			scrollableContent.WriteString(i.renderLastResult(wrapStyle))
+			scrollableContent.WriteString("\n" + components.StatusMuted.Render("Output:") + "\n")
+			scrollableContent.WriteString(i.lastOutput + "\n")
+		} else if i.lastCommand == "" && i.preview == nil {
+			scrollableContent.WriteString(components.StatusMuted.Render("Press [n] to build an intent") + "\n")
+		}
 	} else {
-		scrollableContent.WriteString(components.StatusMuted.Render("Press [n] to send an intent") + "\n")
+		scrollableContent.WriteString(i.renderLibrary(truncStyle))
 	}
 
-	footer := components.Help("n", "new intent") + "  " +
-		components.Help("←/→", "mode") + "  " +
-		components.Help("esc", "back")
-
 	rendered := components.RenderLayoutWithScrollableSection(i.state, components.LayoutWithScrollProps{
 		Title:             "Intents",
 		StaticContent:     staticContent.String(),
 		ScrollableContent: scrollableContent.String(),
-		Footer:            footer,
+		Footer:            i.footer(),
+		Viewport:          &i.viewport,
 	})
 
-	if i.form.Visible {
-		rendered = components.RenderFormOverlay(rendered, i.form, i.state)
+	for _, form := range []*components.FormModal{&i.form, &i.placeholderForm, &i.saveForm, &i.fileForm} {
+		if form.Visible {
+			rendered = components.RenderFormOverlay(rendered, *form, i.state)
+		}
+	}
+
+	if i.confirm.Visible {
+		rendered = components.RenderOverlay(rendered, i.confirm.View(), i.state)
 	}
 
 	if i.toast.Visible {
@@ -218,3 +816,141 @@ func (i *Intents) View() string {
 
 	return rendered
 }
+
+// renderLastResult shows whether the last intent went through and, for
+// activities, where and how it launched.
+func (i *Intents) renderLastResult(wrapStyle lipgloss.Style) string {
+	r := i.lastResult
+
+	var b strings.Builder
+	switch {
+	case i.lastFailed && r.Error != "":
+		b.WriteString(wrapStyle.Render(components.ErrorStyle.Render("✗ " + r.Error)))
+	case i.lastFailed:
+		b.WriteString(components.ErrorStyle.Render("✗ Failed"))
+	case r.Warning != "":
+		b.WriteString(wrapStyle.Render(components.WarningStyle.Render("! " + r.Warning)))
+	case i.lastMode == adb.IntentBroadcast:
+		b.WriteString(components.StatusConnected.Render("✓ Broadcast sent"))
+	case i.lastMode == adb.IntentStart:
+		b.WriteString(components.StatusConnected.Render("✓ Activity started"))
+	default:
+		b.WriteString(components.StatusConnected.Render("✓ Service started"))
+	}
+	b.WriteString("\n")
+
+	var rows []components.KeyValueRow
+	if r.Status != "" {
+		rows = append(rows, components.KeyValueRow{Key: "  Status:     ", Value: r.Status})
+	}
+	if r.Activity != "" {
+		rows = append(rows, components.KeyValueRow{Key: "  Activity:   ", Value: r.Activity})
+	}
+	if r.LaunchState != "" {
+		rows = append(rows, components.KeyValueRow{Key: "  Launch:     ", Value: r.LaunchState})
+	}
+	if r.TotalTime > 0 {
+		rows = append(rows, components.KeyValueRow{Key: "  Total Time: ", Value: fmt.Sprintf("%d ms", r.TotalTime)})
+	}
+	if r.WaitTime > 0 {
+		rows = append(rows, components.KeyValueRow{Key: "  Wait Time:  ", Value: fmt.Sprintf("%d ms", r.WaitTime)})
+	}
+	b.WriteString(components.KeyValueList(rows))
+	return b.String()
+}
+
+// renderChoiceBar renders names as "a / b / c" with the selected one
+// highlighted.
+func renderChoiceBar(names []string, selected int) string {
+	var b strings.Builder
+	b.WriteString("  ")
+	for idx, name := range names {
+		if idx == selected {
+			b.WriteString(components.HelpKeyStyle.Render(name))
+		} else {
+			b.WriteString(components.StatusMuted.Render(name))
+		}
+		if idx < len(names)-1 {
+			b.WriteString(components.StatusMuted.Render(" / "))
+		}
+	}
+	return b.String()
+}
+
+func (i *Intents) footer() string {
+	switch {
+	case i.search.Active:
+		return components.Help("enter", "apply") + "  " +
+			components.Help("esc", "cancel")
+
+	case i.view == intentViewBuilder && i.preview != nil:
+		return components.Help("enter", "send") + "  " +
+			components.Help("e", "edit") + "  " +
+			components.Help("s", "save") + "  " +
+			components.Help("←/→", "mode") + "  " +
+			components.Help("tab", "library") + "  " +
+			components.Help("esc", "discard")
+
+	case i.view == intentViewBuilder:
+		return components.Help("n", "new intent") + "  " +
+			components.Help("e", "edit last") + "  " +
+			components.Help("s", "save") + "  " +
+			components.Help("←/→", "mode") + "  " +
+			components.Help("tab", "library") + "  " +
+			components.Help("esc", "back")
+	}
+
+	footer := components.Help("↑/↓", "navigate") + "  " +
+		components.Help("enter", "replay") + "  " +
+		components.Help("e", "edit") + "  "
+	if i.view == intentViewHistory {
+		footer += components.Help("s", "save") + "  "
+	} else {
+		footer += components.Help("d", "delete") + "  " +
+			components.Help("x/i", "export/import") + "  "
+	}
+	return footer + components.Help("/", "search") + "  " +
+		components.Help("tab", "view") + "  " +
+		components.Help("esc", "builder")
+}
+
+func (i *Intents) renderLibrary(truncStyle lipgloss.Style) string {
+	items := i.items()
+	if len(items) == 0 {
+		switch {
+		case i.search.Query != "":
+			return components.StatusMuted.Render("No matches") + "\n"
+		case i.view == intentViewHistory:
+			return components.StatusMuted.Render("Sent intents show up here") + "\n"
+		}
+		return components.StatusMuted.Render("No favorites yet. Press [s] on an intent to save one.") + "\n"
+	}
+
+	var out strings.Builder
+	for idx, item := range items {
+		prefix, style := "  ", components.ListItemStyle
+		if idx == i.cursor {
+			prefix, style = "› ", components.ListItemSelectedStyle
+		}
+
+		var line string
+		if e := item.entry; e != nil {
+			status := components.StatusConnected.Render("✓")
+			if e.Error != "" {
+				status = components.ErrorStyle.Render("✗")
+			}
+			line = prefix + status + " " + components.StatusMuted.Render(e.Time.Format("01-02 15:04")) + "  " + style.Render(item.title)
+		} else {
+			line = prefix + style.Render(item.title)
+			if item.fav.Project != "" {
+				line += " " + components.StatusMuted.Render("["+item.fav.Project+"]")
+			}
+			if names := item.spec.Placeholders(); len(names) > 0 {
+				line += " " + components.WarningStyle.Render("{{"+strings.Join(names, "}} {{")+"}}")
+			}
+			line += "  " + components.StatusMuted.Render(item.spec.Summary())
+		}
+		out.WriteString(truncStyle.Render(line) + "\n")
+	}
+	return out.String()
+}
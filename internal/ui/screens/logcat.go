@@ -2,6 +2,7 @@ package screens
 
 import (
 	"strings"
+	"time"
 
 	"github.com/SakshhamTheCoder/adbt/internal/adb"
 	"github.com/SakshhamTheCoder/adbt/internal/state"
@@ -23,6 +24,28 @@ type Logcat struct {
 	filterLevel int
 	search      components.SearchState
 	viewport    viewport.Model
+
+	// pkg limits the log to one app's processes. pids keeps every process
+	// the app has had, so earlier lines stay visible after a restart.
+	pkg        string
+	pids       map[string]bool
+	pidPolling bool
+	form       components.FormModal
+}
+
+// logcatPIDInterval is how often the filtered app's processes are listed.
+const logcatPIDInterval = 2 * time.Second
+
+// PID messages carry their screen so a poll left over from a closed
+// Logcat does not start a second loop in a new one.
+type logcatPIDsMsg struct {
+	logcat *Logcat
+	pkg    string
+	pids   []string
+}
+
+type logcatPIDTickMsg struct {
+	logcat *Logcat
 }
 
 func NewLogcat(state *state.AppState) *Logcat {
@@ -37,11 +60,84 @@ func (l *Logcat) Init() tea.Cmd {
 		return nil
 	}
 	l.running = true
-	return tea.Batch(tea.SetWindowTitle(components.ShellTitle(l.state, "Logcat")), adb.StartLogcatCmd(l.state.DeviceSerial()))
+	// Inside an Android project the log starts filtered to its app, the
+	// selected variant's when coming from the Project screen.
+	pkg := l.state.Project.ApplicationID()
+	if l.state.Project.Owns(l.state.SelectedPackage) {
+		pkg = l.state.SelectedPackage
+	}
+	l.setPackage(pkg)
+	return tea.Batch(
+		tea.SetWindowTitle(components.ShellTitle(l.state, "Logcat")),
+		adb.StartLogcatCmd(l.state.DeviceSerial()),
+		l.pollPIDs(),
+	)
+}
+
+func (l *Logcat) setPackage(pkg string) {
+	l.pkg = pkg
+	l.pids = map[string]bool{}
+}
+
+// pollPIDs starts listing the filtered app's processes unless a poll is
+// already scheduled.
+func (l *Logcat) pollPIDs() tea.Cmd {
+	if l.pkg == "" || l.pidPolling {
+		return nil
+	}
+	l.pidPolling = true
+	serial, pkg := l.state.DeviceSerial(), l.pkg
+	return func() tea.Msg {
+		return logcatPIDsMsg{logcat: l, pkg: pkg, pids: adb.PackagePIDs(serial, pkg)}
+	}
+}
+
+func (l *Logcat) InputActive() bool {
+	return l.search.Active || l.form.Visible
 }
 
 func (l *Logcat) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if l.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			l.form.Hide()
+			pkg := ""
+			if len(msg.Values) > 0 {
+				pkg = strings.TrimSpace(msg.Values[0])
+			}
+			l.setPackage(pkg)
+			return l, l.pollPIDs()
+		case components.FormCancelMsg:
+			l.form.Hide()
+			return l, nil
+		case tea.KeyMsg:
+			return l, l.form.Update(msg)
+		}
+	}
+
 	switch msg := msg.(type) {
+	case logcatPIDsMsg:
+		if msg.logcat != l {
+			return l, nil
+		}
+		l.pidPolling = false
+		if msg.pkg != l.pkg {
+			return l, l.pollPIDs()
+		}
+		for _, pid := range msg.pids {
+			l.pids[pid] = true
+		}
+		l.pidPolling = true
+		return l, tea.Tick(logcatPIDInterval, func(time.Time) tea.Msg {
+			return logcatPIDTickMsg{logcat: l}
+		})
+
+	case logcatPIDTickMsg:
+		if msg.logcat != l {
+			return l, nil
+		}
+		l.pidPolling = false
+		return l, l.pollPIDs()
 
 	case adb.LogcatStartedMsg:
 		l.session = msg.Session
@@ -84,6 +180,16 @@ func (l *Logcat) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			l.filterLevel = (l.filterLevel + len(logLevels) - 1) % len(logLevels)
 		case "/":
 			l.search.Start()
+		case "p":
+			var suggestions []string
+			for _, pkg := range []string{l.state.Project.ApplicationID(), l.state.SelectedPackage} {
+				if pkg != "" && pkg != l.pkg {
+					suggestions = append(suggestions, pkg)
+				}
+			}
+			l.form.Show("Package Filter", []components.FormField{
+				{Label: "Package", Value: l.pkg, Placeholder: "empty shows every app", Suggestions: suggestions},
+			})
 		case "esc":
 			if l.search.Query != "" {
 				l.search.Clear()
@@ -144,6 +250,14 @@ func (l *Logcat) View() string {
 		}
 	}
 
+	if l.pkg != "" {
+		statusLine.WriteString("  ")
+		statusLine.WriteString(components.HelpKeyStyle.Render("package: ") + l.pkg)
+		if len(l.pids) == 0 {
+			statusLine.WriteString(components.StatusMuted.Render(" (not running)"))
+		}
+	}
+
 	if l.search.Active {
 		statusLine.WriteString("  ")
 		statusLine.WriteString(components.HelpKeyStyle.Render("search: ") + l.search.Query + "▌")
@@ -154,29 +268,39 @@ func (l *Logcat) View() string {
 
 	statusLine.WriteString("\n")
 
-	return components.RenderLayoutWithScrollableSection(l.state, components.LayoutWithScrollProps{
+	rendered := components.RenderLayoutWithScrollableSection(l.state, components.LayoutWithScrollProps{
 		Title:             "Logcat",
 		StaticContent:     statusLine.String(),
 		ScrollableContent: body.String(),
 		Footer: components.Help("c", "clear") + "  " +
 			components.Help("s", "start/stop") + "  " +
 			components.Help("←/→", "filter") + "  " +
+			components.Help("p", "package") + "  " +
 			components.Help("/", "search") + "  " +
 			components.Help("esc", "back"),
 		Viewport: &l.viewport,
 	})
+
+	if l.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, l.form, l.state)
+	}
+
+	return rendered
 }
 
 /* ---------- helpers ---------- */
 
 func (l *Logcat) filteredLines() []string {
 	minLevel := logLevels[l.filterLevel]
-	if minLevel == "" && l.search.Query == "" {
+	if minLevel == "" && l.search.Query == "" && l.pkg == "" {
 		return l.lines
 	}
 
 	result := make([]string, 0, len(l.lines))
 	for _, line := range l.lines {
+		if l.pkg != "" && !l.pids[extractPID(line)] {
+			continue
+		}
 		if minLevel != "" && !lineMatchesLevel(line, minLevel) {
 			continue
 		}
@@ -212,6 +336,16 @@ func extractPriority(line string) string {
 	return ""
 }
 
+// extractPID reads the process id from a threadtime line:
+// "MM-DD HH:MM:SS.mmm  PID  TID L Tag: message".
+func extractPID(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return ""
+	}
+	return fields[2]
+}
+
 func priorityRank(level string) int {
 	switch level {
 	case "V":
@@ -2,29 +2,82 @@ package screens
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/perf"
 	"github.com/SakshhamTheCoder/adbt/internal/state"
 	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// uidTrafficInterval is how many ticks pass between per-app traffic
+// samples; dumpsys netstats is too slow to run every second.
+const uidTrafficInterval = 5
+
+const maxUIDRows = 10
+
+type interfaceRate struct {
+	stats  adb.InterfaceStats
+	rxRate uint64
+	txRate uint64
+}
+
+type uidRate struct {
+	traffic adb.UIDTraffic
+	rxRate  uint64
+	txRate  uint64
+}
+
+type perfView int
+
+const (
+	perfViewBars perfView = iota
+	perfViewGraph
+)
+
+// maxPerfHistory bounds the live graph history (10 minutes at 1 sample/s).
+const maxPerfHistory = 600
+
 type PerfMonitor struct {
 	state *state.AppState
 
 	// Stats
-	lastStats    adb.SystemStats
 	currentStats adb.SystemStats
 	hasHistory   bool
 
 	// Calculated values
-	cpuPercent float64
-	rxRate     uint64 // bytes per second
-	txRate     uint64 // bytes per second
+	sample  perf.Sample
+	history []perf.Sample
+	ifaces  []interfaceRate
+
+	// Per-app traffic
+	uidTraffic    map[int]adb.UIDTraffic
+	uidSampledAt  time.Time
+	uidRates      []uidRate
+	uidSource     string
+	uidError      error
+	uidLoading    bool
+	ticksSinceUID int
+
+	view perfView
+
+	// Recording and review
+	recorder   *perf.Recorder
+	summary    *perf.Summary
+	review     []perf.Sample
+	reviewPath string
+
+	form        components.FormModal
+	formPurpose string
+	toast       components.Toast
+
+	viewport viewport.Model
 
 	active bool
 }
@@ -33,7 +86,8 @@ type TickMsg time.Time
 
 func NewPerfMonitor(state *state.AppState) *PerfMonitor {
 	return &PerfMonitor{
-		state: state,
+		state:    state,
+		viewport: viewport.New(0, 0),
 	}
 }
 
@@ -42,8 +96,10 @@ func (m *PerfMonitor) Init() tea.Cmd {
 		return nil
 	}
 	m.active = true
+	m.uidLoading = true
 	return tea.Batch(
 		adb.GetSystemStatsCmd(m.state.DeviceSerial()),
+		adb.GetUIDTrafficCmd(m.state.DeviceSerial()),
 		m.tickCmd(),
 	)
 }
@@ -54,22 +110,104 @@ func (m *PerfMonitor) tickCmd() tea.Cmd {
 	})
 }
 
+func (m *PerfMonitor) InputActive() bool {
+	return m.form.Visible
+}
+
 func (m *PerfMonitor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.toast.Update(msg)
+
+	if m.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			m.form.Hide()
+			path := ""
+			if len(msg.Values) > 0 {
+				path = strings.TrimSpace(msg.Values[0])
+			}
+			if path == "" {
+				return m, nil
+			}
+			if m.formPurpose == "open" {
+				return m, m.openRecording(path)
+			}
+			return m, m.startRecording(path)
+		case components.FormCancelMsg:
+			m.form.Hide()
+			return m, nil
+		case tea.KeyMsg:
+			return m, m.form.Update(msg)
+		}
+		// Ticks and stats fall through, so sampling and recording carry on
+		// while the form is open.
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "q", "esc":
+		case "q":
 			return m, nil // handled by parent or just stop? Parent handles navigation.
+		case "esc":
+			if m.review != nil {
+				m.review = nil
+				m.reviewPath = ""
+				m.summary = nil
+				m.gotoTop()
+				return m, consumeKeyCmd()
+			}
+			return m, nil
+		case "g":
+			if m.view == perfViewBars {
+				m.view = perfViewGraph
+			} else {
+				m.view = perfViewBars
+			}
+			m.gotoTop()
+		case "r":
+			if m.recorder != nil {
+				return m, m.stopRecording()
+			}
+			m.formPurpose = "record"
+			m.form.Show("Record Performance", []components.FormField{
+				{
+					Label:       "Output (.csv/.jsonl)",
+					Value:       perf.DefaultRecordingPath(m.state.DeviceSerial(), time.Now()),
+					Placeholder: "~/Downloads/adbt-perf.csv",
+				},
+			})
+		case "o":
+			m.formPurpose = "open"
+			m.form.Show("Open Recording", []components.FormField{
+				{Label: "Recording Path", Placeholder: "~/Downloads/adbt-perf.csv"},
+			})
+		default:
+			return m, m.updateViewport(msg)
 		}
 
 	case TickMsg:
 		if !m.active || !m.state.HasDevice() {
 			return m, nil
 		}
-		return m, tea.Batch(
+		cmds := []tea.Cmd{
 			adb.GetSystemStatsCmd(m.state.DeviceSerial()),
 			m.tickCmd(),
-		)
+		}
+		m.ticksSinceUID++
+		if m.ticksSinceUID >= uidTrafficInterval && !m.uidLoading {
+			m.ticksSinceUID = 0
+			m.uidLoading = true
+			cmds = append(cmds, adb.GetUIDTrafficCmd(m.state.DeviceSerial()))
+		}
+		return m, tea.Batch(cmds...)
+
+	case adb.UIDTrafficMsg:
+		m.uidLoading = false
+		m.uidError = msg.Error
+		if msg.Error != nil {
+			return m, nil
+		}
+		m.updateUIDRates(msg.Traffic, time.Now())
+		m.uidSource = msg.Source
 
 	case adb.SystemStatsMsg:
 		if msg.Error != nil {
@@ -80,26 +218,36 @@ func (m *PerfMonitor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		newStats := msg.Stats
 
 		if m.hasHistory {
-			// Calculate CPU
-			deltaTotal := newStats.CPUTotal - m.currentStats.CPUTotal
-			deltaIdle := newStats.CPUIdle - m.currentStats.CPUIdle
-
-			if deltaTotal > 0 {
-				used := deltaTotal - deltaIdle
-				m.cpuPercent = float64(used) / float64(deltaTotal) * 100
+			var ok bool
+			m.sample, ok = perf.Derive(m.currentStats, newStats)
+			if ok {
+				m.history = append(m.history, m.sample)
+				if len(m.history) > maxPerfHistory {
+					m.history = m.history[len(m.history)-maxPerfHistory:]
+				}
 			}
 
-			// Calculate Net Rate (assuming 1 second tick)
-			// Handle wrap around roughly
-			if newStats.NetRxBytes >= m.currentStats.NetRxBytes {
-				m.rxRate = newStats.NetRxBytes - m.currentStats.NetRxBytes
-			}
-			if newStats.NetTxBytes >= m.currentStats.NetTxBytes {
-				m.txRate = newStats.NetTxBytes - m.currentStats.NetTxBytes
+			if m.recorder != nil && ok {
+				if err := m.recorder.Write(m.sample); err != nil {
+					cmd := m.stopRecording()
+					var toastCmd tea.Cmd
+					m.toast, toastCmd = components.ShowToast(
+						"Recording failed: "+err.Error(),
+						true,
+						3*time.Second,
+					)
+					return m, tea.Batch(cmd, toastCmd)
+				}
 			}
 		}
 
-		m.lastStats = m.currentStats
+		if newStats.HasNet {
+			m.updateInterfaceRates(newStats.Interfaces, newStats.SampledAt.Sub(m.currentStats.SampledAt).Seconds())
+		} else {
+			// Rates against the reading before the gap would span two ticks.
+			m.ifaces = nil
+		}
+
 		m.currentStats = newStats
 		m.hasHistory = true
 	}
@@ -109,26 +257,175 @@ func (m *PerfMonitor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *PerfMonitor) Cleanup() tea.Cmd {
 	m.active = false
+	if m.recorder != nil {
+		_, _ = m.recorder.Close()
+		m.recorder = nil
+	}
+	return nil
+}
+
+func (m *PerfMonitor) startRecording(path string) tea.Cmd {
+	recorder, err := perf.NewRecorder(expandHome(path))
+	if err != nil {
+		var cmd tea.Cmd
+		m.toast, cmd = components.ShowToast(
+			"Cannot record: "+err.Error(),
+			true,
+			3*time.Second,
+		)
+		return cmd
+	}
+
+	m.recorder = recorder
+	m.summary = nil
+	var cmd tea.Cmd
+	m.toast, cmd = components.ShowToast(
+		"Recording to "+recorder.Path,
+		false,
+		2*time.Second,
+	)
+	return cmd
+}
+
+func (m *PerfMonitor) stopRecording() tea.Cmd {
+	summary, err := m.recorder.Close()
+	path := m.recorder.Path
+	m.recorder = nil
+	m.summary = &summary
+
+	var cmd tea.Cmd
+	if err != nil {
+		m.toast, cmd = components.ShowToast(
+			"Failed to write summary: "+err.Error(),
+			true,
+			3*time.Second,
+		)
+		return cmd
+	}
+	m.toast, cmd = components.ShowToast(
+		fmt.Sprintf("Saved %d samples to %s", summary.Samples, path),
+		false,
+		3*time.Second,
+	)
+	return cmd
+}
+
+func (m *PerfMonitor) openRecording(path string) tea.Cmd {
+	path = expandHome(path)
+	samples, err := perf.Load(path)
+	if err == nil && len(samples) == 0 {
+		err = fmt.Errorf("no samples")
+	}
+	if err != nil {
+		var cmd tea.Cmd
+		m.toast, cmd = components.ShowToast(
+			"Cannot open recording: "+err.Error(),
+			true,
+			3*time.Second,
+		)
+		return cmd
+	}
+
+	summary := perf.Summarize(samples)
+	m.review = samples
+	m.reviewPath = path
+	m.summary = &summary
+	m.view = perfViewGraph
+	m.gotoTop()
 	return nil
 }
 
 func (m *PerfMonitor) View() string {
-	if !m.state.HasDevice() {
+	if !m.state.HasDevice() && m.review == nil {
 		return components.RenderNoDevice(m.state, "Performance Monitor")
 	}
 
-	// Styles
+	var status string
+	switch {
+	case m.review != nil:
+		status = components.WarningStyle.Render("● reviewing ") +
+			components.StatusMuted.Render(m.reviewPath)
+	case m.recorder != nil:
+		status = components.ErrorStyle.Render("● recording ") +
+			components.StatusMuted.Render(fmt.Sprintf("%s (%d samples)", m.recorder.Path, len(m.recorder.Samples)))
+	default:
+		status = components.StatusConnected.Render("● live")
+	}
+
+	var content string
+	switch {
+	case m.review != nil:
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			"",
+			m.renderGraphs(m.review, true),
+			components.TitleStyle.Render("Summary"),
+			m.renderSummary(),
+		)
+	case m.view == perfViewGraph:
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			"",
+			m.renderGraphs(m.history, false),
+		)
+	default:
+		content = m.renderBars()
+	}
+
+	if m.review == nil && m.summary != nil {
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			content,
+			components.TitleStyle.Render("Last Recording"),
+			m.renderSummary(),
+		)
+	}
+
+	var footer string
+	if m.review != nil {
+		footer = components.Help("↑/↓", "scroll") + "  " +
+			components.Help("o", "open") + "  " +
+			components.Help("esc", "close review")
+	} else {
+		recordHelp := "record"
+		if m.recorder != nil {
+			recordHelp = "stop recording"
+		}
+		footer = components.Help("↑/↓", "scroll") + "  " +
+			components.Help("g", "graph/bars") + "  " +
+			components.Help("r", recordHelp) + "  " +
+			components.Help("o", "open recording") + "  " +
+			components.Help("esc", "back")
+	}
+
+	rendered := components.RenderLayoutWithScrollableSection(m.state, components.LayoutWithScrollProps{
+		Title:             "Performance Monitor",
+		StaticContent:     status + "\n",
+		ScrollableContent: content,
+		Footer:            footer,
+		Viewport:          &m.viewport,
+	})
+
+	if m.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, m.form, m.state)
+	}
+
+	if m.toast.Visible {
+		rendered = components.RenderOverlay(rendered, m.toast.View(), m.state)
+	}
+
+	return rendered
+}
+
+func (m *PerfMonitor) renderBars() string {
 	// Styles
 	labelStyle := components.StatusMuted.Copy().Width(12)
 	barStyle := lipgloss.NewStyle().Background(components.Primary)
 	barEmptyStyle := lipgloss.NewStyle().Background(lipgloss.Color("#1f2937")) // dark gray (Tailwind gray-800)
 
 	// 1. CPU
-	cpuBar := renderProgressBar(m.cpuPercent, 40, barStyle, barEmptyStyle)
+	cpuBar := renderProgressBar(m.sample.CPUPercent, 40, barStyle, barEmptyStyle)
 	cpuRow := lipgloss.JoinHorizontal(lipgloss.Center,
 		labelStyle.Render("CPU Use"),
 		cpuBar,
-		fmt.Sprintf(" %.1f%%", m.cpuPercent),
+		fmt.Sprintf(" %.1f%%", m.sample.CPUPercent),
 	)
 
 	// 2. Memory
@@ -149,28 +446,244 @@ func (m *PerfMonitor) View() string {
 	)
 
 	// 3. Network
-	rxStr := adb.FormatFileSize(fmt.Sprintf("%d", m.rxRate)) + "/s"
-	txStr := adb.FormatFileSize(fmt.Sprintf("%d", m.txRate)) + "/s"
+	rxStr := formatRate(m.sample.NetRxRate)
+	txStr := formatRate(m.sample.NetTxRate)
 	netRow := lipgloss.JoinHorizontal(lipgloss.Center,
 		labelStyle.Render("Network"),
 		fmt.Sprintf("↓ %s   ↑ %s", rxStr, txStr),
 	)
 
-	content := lipgloss.JoinVertical(lipgloss.Left,
+	return lipgloss.JoinVertical(lipgloss.Left,
 		"",
 		cpuRow,
 		"",
 		memRow,
 		"",
 		netRow,
+		"",
+		components.TitleStyle.Render("Interfaces"),
+		m.renderInterfaces(),
+		components.TitleStyle.Render("Traffic by App"),
+		m.renderUIDTraffic(),
 	)
+}
 
-	return components.RenderLayout(
-		m.state,
-		"Performance Monitor",
-		content,
-		components.Help("esc", "back"),
-	)
+func (m *PerfMonitor) renderGraphs(samples []perf.Sample, fit bool) string {
+	width := m.state.Width - 24
+	if width < 20 {
+		width = 20
+	}
+
+	if len(samples) == 0 {
+		return components.StatusMuted.Render("  Waiting for samples...") + "\n"
+	}
+
+	series := func(value func(perf.Sample) float64) []float64 {
+		values := make([]float64, len(samples))
+		for i, s := range samples {
+			values[i] = value(s)
+		}
+		if fit {
+			return downsampleMax(values, width)
+		}
+		if len(values) > width {
+			values = values[len(values)-width:]
+		}
+		return values
+	}
+
+	cpu := series(func(s perf.Sample) float64 { return s.CPUPercent })
+	mem := series(func(s perf.Sample) float64 { return s.MemPercent })
+	rx := series(func(s perf.Sample) float64 { return float64(s.NetRxRate) })
+	tx := series(func(s perf.Sample) float64 { return float64(s.NetTxRate) })
+
+	last := samples[len(samples)-1]
+	var b strings.Builder
+	writeChart := func(title, current string, values []float64, max float64) {
+		b.WriteString(components.StatusMuted.Render(title) + " " + current + "\n")
+		b.WriteString(renderChart(values, max, 4) + "\n\n")
+	}
+
+	writeChart("CPU", fmt.Sprintf("%.1f%%", last.CPUPercent), cpu, 100)
+	writeChart("Memory", fmt.Sprintf("%.1f%%", last.MemPercent), mem, 100)
+	writeChart("Net ↓", formatRate(last.NetRxRate), rx, maxValue(rx))
+	writeChart("Net ↑", formatRate(last.NetTxRate), tx, maxValue(tx))
+
+	if fit {
+		span := samples[len(samples)-1].Time.Sub(samples[0].Time).Round(time.Second)
+		b.WriteString(components.StatusMuted.Render(fmt.Sprintf(
+			"  %d samples over %s, %s → %s",
+			len(samples),
+			span,
+			samples[0].Time.Format("15:04:05"),
+			samples[len(samples)-1].Time.Format("15:04:05"),
+		)) + "\n")
+	}
+
+	return b.String()
+}
+
+func (m *PerfMonitor) renderSummary() string {
+	if m.summary == nil || m.summary.Samples == 0 {
+		return components.StatusMuted.Render("  No samples recorded") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(components.StatusMuted.Render(fmt.Sprintf(
+		"  %-14s %12s %12s %12s %12s",
+		"Metric", "Min", "Avg", "P95", "Max",
+	)) + "\n")
+	for _, row := range m.summary.Rows() {
+		b.WriteString(fmt.Sprintf(
+			"  %-14s %12s %12s %12s %12s\n",
+			row[0], row[1], row[2], row[3], row[4],
+		))
+	}
+	b.WriteString(components.StatusMuted.Render(fmt.Sprintf(
+		"  %d samples, %s",
+		m.summary.Samples,
+		m.summary.Duration().Round(time.Second),
+	)) + "\n")
+	return b.String()
+}
+
+// updateInterfaceRates divides by the elapsed seconds between the two
+// snapshots, like perf.Derive does for the Network total, since slow adb
+// calls stretch the tick.
+func (m *PerfMonitor) updateInterfaceRates(ifaces []adb.InterfaceStats, elapsed float64) {
+	previous := make(map[string]adb.InterfaceStats, len(m.ifaces))
+	for _, r := range m.ifaces {
+		previous[r.stats.Name] = r.stats
+	}
+
+	rates := make([]interfaceRate, 0, len(ifaces))
+	for _, iface := range ifaces {
+		r := interfaceRate{stats: iface}
+		if prev, ok := previous[iface.Name]; ok && elapsed > 0 {
+			if iface.RxBytes >= prev.RxBytes {
+				r.rxRate = uint64(float64(iface.RxBytes-prev.RxBytes) / elapsed)
+			}
+			if iface.TxBytes >= prev.TxBytes {
+				r.txRate = uint64(float64(iface.TxBytes-prev.TxBytes) / elapsed)
+			}
+		}
+		rates = append(rates, r)
+	}
+	m.ifaces = rates
+}
+
+func (m *PerfMonitor) updateUIDRates(traffic []adb.UIDTraffic, now time.Time) {
+	elapsed := now.Sub(m.uidSampledAt).Seconds()
+
+	current := make(map[int]adb.UIDTraffic, len(traffic))
+	rates := make([]uidRate, 0, len(traffic))
+	for _, t := range traffic {
+		current[t.UID] = t
+		r := uidRate{traffic: t}
+		if prev, ok := m.uidTraffic[t.UID]; ok && elapsed > 0 {
+			if t.RxBytes >= prev.RxBytes {
+				r.rxRate = uint64(float64(t.RxBytes-prev.RxBytes) / elapsed)
+			}
+			if t.TxBytes >= prev.TxBytes {
+				r.txRate = uint64(float64(t.TxBytes-prev.TxBytes) / elapsed)
+			}
+		}
+		rates = append(rates, r)
+	}
+
+	// Busiest apps right now first, cumulative totals as a tiebreaker.
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].rxRate+rates[i].txRate > rates[j].rxRate+rates[j].txRate
+	})
+
+	m.uidTraffic = current
+	m.uidSampledAt = now
+	m.uidRates = rates
+}
+
+func (m *PerfMonitor) renderInterfaces() string {
+	var active []interfaceRate
+	for _, r := range m.ifaces {
+		if r.stats.RxBytes > 0 || r.stats.TxBytes > 0 {
+			active = append(active, r)
+		}
+	}
+
+	if len(active) == 0 {
+		return components.StatusMuted.Render("  No active interfaces") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(components.StatusMuted.Render(fmt.Sprintf(
+		"  %-14s %12s %12s %15s %11s %11s",
+		"Interface", "↓ Rate", "↑ Rate", "Packets ↓/↑", "Errors", "Drops",
+	)) + "\n")
+
+	for _, r := range active {
+		s := r.stats
+		b.WriteString(fmt.Sprintf(
+			"  %-14s %12s %12s %15s %11s %11s\n",
+			s.Name,
+			formatRate(r.rxRate),
+			formatRate(r.txRate),
+			fmt.Sprintf("%d/%d", s.RxPackets, s.TxPackets),
+			fmt.Sprintf("%d/%d", s.RxErrors, s.TxErrors),
+			fmt.Sprintf("%d/%d", s.RxDropped, s.TxDropped),
+		))
+	}
+	return b.String()
+}
+
+func (m *PerfMonitor) renderUIDTraffic() string {
+	if m.uidError != nil {
+		return components.ErrorStyle.Render("  Per-app traffic unavailable: "+m.uidError.Error()) + "\n"
+	}
+	if len(m.uidRates) == 0 {
+		if m.uidLoading {
+			return components.StatusMuted.Render("  Loading per-app traffic...") + "\n"
+		}
+		return components.StatusMuted.Render("  No per-app traffic reported") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(components.StatusMuted.Render(fmt.Sprintf(
+		"  %-40s %12s %12s %12s  (%s)",
+		"App", "↓ Rate", "↑ Rate", "Total", m.uidSource,
+	)) + "\n")
+
+	rows := m.uidRates
+	if len(rows) > maxUIDRows {
+		rows = rows[:maxUIDRows]
+	}
+
+	for _, r := range rows {
+		name := r.traffic.DisplayName()
+		if len(name) > 40 {
+			name = name[:39] + "…"
+		}
+		b.WriteString(fmt.Sprintf(
+			"  %-40s %12s %12s %12s\n",
+			name,
+			formatRate(r.rxRate),
+			formatRate(r.txRate),
+			adb.FormatFileSize(fmt.Sprintf("%d", r.traffic.RxBytes+r.traffic.TxBytes)),
+		))
+	}
+	return b.String()
+}
+
+func (m *PerfMonitor) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return cmd
+}
+
+func (m *PerfMonitor) gotoTop() {
+	m.viewport.GotoTop()
+}
+
+func formatRate(bytesPerSecond uint64) string {
+	return adb.FormatFileSize(fmt.Sprintf("%d", bytesPerSecond)) + "/s"
 }
 
 func renderProgressBar(percent float64, width int, filled, empty lipgloss.Style) string {
@@ -193,3 +706,75 @@ func renderProgressBar(percent float64, width int, filled, empty lipgloss.Style)
 	}
 	return bar
 }
+
+var chartLevels = []rune(" ▁▂▃▄▅▆▇█")
+
+// renderChart draws values as a block chart height rows tall, scaled so
+// that max fills the top row.
+func renderChart(values []float64, max float64, height int) string {
+	if max <= 0 {
+		max = 1
+	}
+
+	steps := len(chartLevels) - 1
+	rows := make([]strings.Builder, height)
+	for _, v := range values {
+		filled := int(v / max * float64(height*steps))
+		if filled > height*steps {
+			filled = height * steps
+		}
+		if filled < 0 {
+			filled = 0
+		}
+
+		for row := 0; row < height; row++ {
+			// row 0 is the top of the chart
+			level := filled - (height-1-row)*steps
+			if level < 0 {
+				level = 0
+			}
+			if level > steps {
+				level = steps
+			}
+			rows[row].WriteRune(chartLevels[level])
+		}
+	}
+
+	lines := make([]string, height)
+	for i := range rows {
+		lines[i] = "  " + components.HelpKeyStyle.Render(rows[i].String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// downsampleMax shrinks values to at most width buckets, keeping the peak
+// of each bucket so short spikes survive in long recordings.
+func downsampleMax(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+
+	result := make([]float64, width)
+	for i := range result {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+		peak := values[start]
+		for _, v := range values[start:end] {
+			if v > peak {
+				peak = v
+			}
+		}
+		result[i] = peak
+	}
+	return result
+}
+
+func maxValue(values []float64) float64 {
+	var max float64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
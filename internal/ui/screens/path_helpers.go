@@ -0,0 +1,21 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// expandHome resolves a leading ~ in paths typed into forms, since they are
+// not passed through a shell.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
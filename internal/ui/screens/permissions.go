@@ -0,0 +1,359 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Permissions lists a package's runtime permissions followed by its AppOps
+// under a single cursor.
+type Permissions struct {
+	state *state.AppState
+	pkg   string
+
+	perms      []adb.PermissionState
+	ops        []adb.AppOp
+	loading    bool
+	loadErr    error
+	opsLoading bool
+	opsErr     error
+	cursor     int
+
+	confirm components.ConfirmPrompt
+	pending string
+	form    components.FormModal
+	toast   components.Toast
+
+	viewport viewport.Model
+}
+
+func NewPermissions(state *state.AppState) *Permissions {
+	return &Permissions{
+		state:    state,
+		pkg:      state.SelectedPackage,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (p *Permissions) Init() tea.Cmd {
+	if !p.state.HasDevice() || p.pkg == "" {
+		return nil
+	}
+	return p.reload()
+}
+
+func (p *Permissions) reload() tea.Cmd {
+	serial := p.state.DeviceSerial()
+	p.loading = true
+	p.opsLoading = true
+	return tea.Batch(
+		adb.GetPackageDetailsCmd(serial, p.pkg),
+		adb.GetAppOpsCmd(serial, p.pkg, p.state.SelectedUser),
+	)
+}
+
+func (p *Permissions) rowCount() int {
+	return len(p.perms) + len(p.ops)
+}
+
+// selected returns the permission or AppOp under the cursor.
+func (p *Permissions) selected() (*adb.PermissionState, *adb.AppOp) {
+	if p.cursor < len(p.perms) {
+		return &p.perms[p.cursor], nil
+	}
+	if i := p.cursor - len(p.perms); i < len(p.ops) {
+		return nil, &p.ops[i]
+	}
+	return nil, nil
+}
+
+func (p *Permissions) InputActive() bool {
+	return p.form.Visible
+}
+
+func (p *Permissions) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p.toast.Update(msg)
+	serial := p.state.DeviceSerial()
+
+	if p.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			p.form.Hide()
+			if _, op := p.selected(); op != nil && len(msg.Values) > 0 {
+				return p, adb.SetAppOpCmd(serial, p.pkg, p.state.SelectedUser, op.Name, msg.Values[0])
+			}
+			return p, nil
+		case components.FormCancelMsg:
+			p.form.Hide()
+			return p, nil
+		}
+		return p, p.form.Update(msg)
+	}
+
+	if p.confirm.Visible {
+		switch msg.(type) {
+		case components.ConfirmYesMsg:
+			p.confirm.Hide()
+			switch p.pending {
+			case "revoke":
+				if perm, _ := p.selected(); perm != nil {
+					return p, adb.SetPermissionCmd(serial, p.pkg, p.state.SelectedUser, perm.Name, false)
+				}
+			case "reset":
+				return p, adb.ResetPermissionsCmd(serial, p.pkg)
+			}
+			return p, nil
+		case components.ConfirmNoMsg:
+			p.confirm.Hide()
+			p.pending = ""
+			return p, nil
+		}
+		return p, p.confirm.Update(msg)
+	}
+
+	switch msg := msg.(type) {
+	case adb.PackageDetailsMsg:
+		if msg.Package != p.pkg {
+			return p, nil
+		}
+		p.loading = false
+		p.loadErr = msg.Error
+		p.perms = msg.Details.RuntimePermissions[p.state.SelectedUser]
+		p.clampCursor()
+
+	case adb.AppOpsMsg:
+		if msg.Package != p.pkg {
+			return p, nil
+		}
+		p.opsLoading = false
+		p.opsErr = msg.Error
+		p.ops = msg.Ops
+		p.clampCursor()
+
+	case adb.AppActionResultMsg:
+		var cmd tea.Cmd
+		p.toast, cmd = components.ShowToast(
+			msg.Action+" successful",
+			false,
+			2*time.Second,
+		)
+		return p, tea.Batch(cmd, p.reload())
+
+	case adb.AppActionErrorMsg:
+		var cmd tea.Cmd
+		p.toast, cmd = components.ShowToast(
+			msg.Action+" failed: "+msg.Error.Error(),
+			true,
+			3*time.Second,
+		)
+		return p, cmd
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if p.cursor > 0 {
+				p.cursor--
+				ensureViewportLineVisible(&p.viewport, p.cursorLine())
+			}
+		case "down", "j":
+			if p.cursor < p.rowCount()-1 {
+				p.cursor++
+				ensureViewportLineVisible(&p.viewport, p.cursorLine())
+			}
+		case "enter", " ":
+			perm, op := p.selected()
+			switch {
+			case perm != nil && perm.Granted:
+				// Revoking kills the app's process, so ask first.
+				p.pending = "revoke"
+				p.confirm.Show("Revoke permission:\n" + perm.Name)
+			case perm != nil:
+				return p, adb.SetPermissionCmd(serial, p.pkg, p.state.SelectedUser, perm.Name, true)
+			case op != nil:
+				p.form.Show("Set "+op.Name, []components.FormField{
+					{Label: "Mode", Type: components.FormFieldSelect, Options: adb.AppOpModes, Value: op.Mode},
+				})
+			}
+		case "R":
+			if p.pkg != "" {
+				p.pending = "reset"
+				p.confirm.Show("Reset all runtime permissions:\n" + p.pkg)
+			}
+		case "r":
+			if p.state.HasDevice() && p.pkg != "" {
+				return p, p.reload()
+			}
+		case "esc":
+			return p, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "apps"}
+			}
+		default:
+			return p, p.updateViewport(msg)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Permissions) clampCursor() {
+	if p.cursor >= p.rowCount() {
+		p.cursor = p.rowCount() - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+// cursorLine maps the cursor to its line in the scrollable content, which
+// has a title line per section and a blank line between them.
+func (p *Permissions) cursorLine() int {
+	if p.cursor < len(p.perms) {
+		return 1 + p.cursor
+	}
+	permLines := len(p.perms)
+	if permLines == 0 {
+		permLines = 1
+	}
+	return 1 + permLines + 2 + (p.cursor - len(p.perms))
+}
+
+func (p *Permissions) View() string {
+	if !p.state.HasDevice() {
+		return components.RenderNoDevice(p.state, "Permissions")
+	}
+
+	maxWidth := p.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	static.WriteString(components.TitleStyle.Render(p.pkg) + "\n")
+
+	var body strings.Builder
+
+	granted := 0
+	for _, perm := range p.perms {
+		if perm.Granted {
+			granted++
+		}
+	}
+	body.WriteString(components.TitleStyle.Render(fmt.Sprintf(
+		"Runtime Permissions (%d/%d granted)", granted, len(p.perms),
+	)) + "\n")
+
+	switch {
+	case p.loading && len(p.perms) == 0:
+		body.WriteString(components.StatusMuted.Render("  Loading permissions...") + "\n")
+	case p.loadErr != nil:
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render("  "+p.loadErr.Error())) + "\n")
+	case len(p.perms) == 0:
+		body.WriteString(components.StatusMuted.Render("  No runtime permissions") + "\n")
+	}
+
+	for i, perm := range p.perms {
+		mark := components.ErrorStyle.Render("✗")
+		if perm.Granted {
+			mark = components.StatusConnected.Render("✓")
+		}
+
+		line := p.rowPrefix(i) + mark + " " + p.rowStyle(i).Render(perm.Name)
+		if perm.Flags != "" {
+			line += " " + components.StatusMuted.Render(perm.Flags)
+		}
+		body.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	body.WriteString("\n" + components.TitleStyle.Render("AppOps") + "\n")
+
+	switch {
+	case p.opsLoading && len(p.ops) == 0:
+		body.WriteString(components.StatusMuted.Render("  Loading AppOps...") + "\n")
+	case p.opsErr != nil:
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render("  "+p.opsErr.Error())) + "\n")
+	case len(p.ops) == 0:
+		body.WriteString(components.StatusMuted.Render("  No operations") + "\n")
+	}
+
+	for i, op := range p.ops {
+		row := len(p.perms) + i
+
+		mode := fmt.Sprintf("%-10s", op.Mode)
+		switch op.Mode {
+		case "allow":
+			mode = components.StatusConnected.Render(mode)
+		case "ignore", "deny":
+			mode = components.ErrorStyle.Render(mode)
+		default:
+			mode = components.WarningStyle.Render(mode)
+		}
+
+		line := p.rowPrefix(row) + mode + " " + p.rowStyle(row).Render(op.Name)
+		if op.UIDMode {
+			line += " " + components.StatusMuted.Render("(uid)")
+		}
+		if op.Detail != "" {
+			line += " " + components.StatusMuted.Render(op.Detail)
+		}
+		body.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	footer := components.Help("↑/↓", "navigate") + "  " +
+		components.Help("enter", "toggle / set mode") + "  " +
+		components.Help("R", "reset all") + "  " +
+		components.Help("r", "reload") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(p.state, components.LayoutWithScrollProps{
+		Title:             "Permissions",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &p.viewport,
+	})
+
+	if p.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, p.form, p.state)
+	}
+
+	if p.confirm.Visible {
+		rendered = components.RenderOverlay(rendered, p.confirm.View(), p.state)
+	}
+
+	if p.toast.Visible {
+		rendered = components.RenderOverlay(rendered, p.toast.View(), p.state)
+	}
+
+	return rendered
+}
+
+func (p *Permissions) rowPrefix(row int) string {
+	if row == p.cursor {
+		return "› "
+	}
+	return "  "
+}
+
+func (p *Permissions) rowStyle(row int) lipgloss.Style {
+	if row == p.cursor {
+		return components.ListItemSelectedStyle
+	}
+	return components.ListItemStyle
+}
+
+func (p *Permissions) updateViewport(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	p.viewport, cmd = p.viewport.Update(msg)
+	return cmd
+}
@@ -58,6 +58,10 @@ func (p *Ports) Init() tea.Cmd {
 	return adb.ListPortForwardsCmd(p.state.DeviceSerial())
 }
 
+func (p *Ports) InputActive() bool {
+	return p.form.Visible
+}
+
 func (p *Ports) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	p.toast.Update(msg)
 
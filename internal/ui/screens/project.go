@@ -0,0 +1,259 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/project"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Project shows the Android project adbt was started in and installs or
+// launches its latest build outputs. Gradle itself is never run.
+type Project struct {
+	state  *state.AppState
+	cursor int
+
+	installing string
+	installErr error
+
+	toast    components.Toast
+	viewport viewport.Model
+}
+
+func NewProject(state *state.AppState) *Project {
+	return &Project{
+		state:    state,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (p *Project) Init() tea.Cmd {
+	p.rescan()
+	return nil
+}
+
+// rescan reads the build outputs again, as builds finish while adbt runs.
+func (p *Project) rescan() {
+	root := ""
+	if p.state.Project != nil {
+		root = p.state.Project.Root
+	} else if cwd, err := os.Getwd(); err == nil {
+		root = cwd
+	}
+	if root == "" {
+		return
+	}
+	if detected, err := project.Detect(root); err == nil && detected != nil {
+		p.state.Project = detected
+	}
+	if n := len(p.variants()); p.cursor >= n {
+		p.cursor = max(n-1, 0)
+	}
+}
+
+func (p *Project) variants() []project.Variant {
+	if p.state.Project == nil {
+		return nil
+	}
+	return p.state.Project.Variants
+}
+
+func (p *Project) selected() *project.Variant {
+	variants := p.variants()
+	if p.cursor >= len(variants) {
+		return nil
+	}
+	return &variants[p.cursor]
+}
+
+func (p *Project) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p.toast.Update(msg)
+
+	switch msg := msg.(type) {
+	case adb.InstallPreparedMsg:
+		if msg.Error != nil {
+			return p, p.finishInstall(msg.Error)
+		}
+		return p, adb.InstallCmd(p.state.DeviceSerial(), msg.Plan)
+
+	case adb.InstallResultMsg:
+		return p, p.finishInstall(msg.Error)
+
+	case adb.AppActionResultMsg:
+		var cmd tea.Cmd
+		p.toast, cmd = components.ShowToast(msg.Action+" successful", false, 2*time.Second)
+		return p, cmd
+
+	case adb.AppActionErrorMsg:
+		var cmd tea.Cmd
+		p.toast, cmd = components.ShowToast(msg.Action+" failed: "+msg.Error.Error(), true, 3*time.Second)
+		return p, cmd
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if p.cursor > 0 {
+				p.cursor--
+				ensureViewportLineVisible(&p.viewport, p.cursor)
+			}
+		case "down", "j":
+			if p.cursor < len(p.variants())-1 {
+				p.cursor++
+				ensureViewportLineVisible(&p.viewport, p.cursor)
+			}
+		case "enter", "i":
+			return p, p.install()
+		case "l":
+			if v := p.selected(); v != nil && p.state.HasDevice() {
+				return p, adb.LaunchAppCmd(p.state.DeviceSerial(), v.ApplicationID, p.state.SelectedUser)
+			}
+		case "a":
+			if v := p.selected(); v != nil && p.state.HasDevice() {
+				p.state.SelectPackage(v.ApplicationID)
+				return p, p.switchTo("apps")
+			}
+		case "g":
+			if v := p.selected(); v != nil && p.state.HasDevice() {
+				p.state.SelectPackage(v.ApplicationID)
+				return p, p.switchTo("logcat")
+			}
+		case "w":
+			if p.state.HasDevice() {
+				return p, p.switchTo("watch")
+			}
+		case "r":
+			p.rescan()
+		default:
+			var cmd tea.Cmd
+			p.viewport, cmd = p.viewport.Update(msg)
+			return p, cmd
+		}
+	}
+
+	return p, nil
+}
+
+// install reinstalls the selected variant's APK over the installed app.
+func (p *Project) install() tea.Cmd {
+	v := p.selected()
+	if v == nil || p.installing != "" || !p.state.HasDevice() {
+		return nil
+	}
+
+	opts := adb.InstallOptions{Replace: true}
+	if p.state.SelectedUser != 0 {
+		opts.User = strconv.Itoa(p.state.SelectedUser)
+	}
+	p.installing = filepath.Base(v.APK)
+	p.installErr = nil
+	p.state.SelectPackage(v.ApplicationID)
+	return adb.PrepareInstallCmd(p.state.DeviceSerial(), []string{v.APK}, opts)
+}
+
+func (p *Project) finishInstall(err error) tea.Cmd {
+	p.installing = ""
+	p.installErr = err
+
+	var cmd tea.Cmd
+	if err != nil {
+		p.toast, cmd = components.ShowToast("install failed", true, 3*time.Second)
+	} else {
+		p.toast, cmd = components.ShowToast("install successful", false, 2*time.Second)
+	}
+	return cmd
+}
+
+func (p *Project) switchTo(screen string) tea.Cmd {
+	return func() tea.Msg {
+		return navigation.SwitchScreenMsg{Screen: screen}
+	}
+}
+
+func (p *Project) View() string {
+	maxWidth := p.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	proj := p.state.Project
+
+	var static strings.Builder
+	var body strings.Builder
+
+	if proj == nil {
+		body.WriteString(components.StatusMuted.Render(
+			"No Android project here. Start adbt in a Gradle project's root directory.",
+		))
+	} else {
+		static.WriteString(components.TitleStyle.Render(filepath.Base(proj.Root)) + "\n")
+		static.WriteString(truncStyle.Render(components.KeyValueList([]components.KeyValueRow{
+			{Key: "  Root:           ", Value: proj.Root},
+			{Key: "  Application ID: ", Value: valueOrDash(proj.ApplicationID())},
+		})) + "\n")
+
+		switch {
+		case p.installing != "":
+			static.WriteString(components.WarningStyle.Render("  ● Installing "+p.installing+"...") + "\n")
+		case p.installErr != nil:
+			static.WriteString(renderInstallError(p.installErr, maxWidth))
+		}
+
+		if len(proj.Variants) == 0 {
+			body.WriteString(components.StatusMuted.Render(fmt.Sprintf(
+				"No build outputs in %s yet. Build the app with Gradle, then press r.",
+				project.OutputsDir,
+			)))
+		}
+
+		for i, v := range proj.Variants {
+			line := fmt.Sprintf("%-16s %-18s %s  %s",
+				v.Name,
+				v.Version(),
+				v.Modified.Format("2006-01-02 15:04"),
+				v.ApplicationID,
+			)
+			if i == p.cursor {
+				line = "› " + components.ListItemSelectedStyle.Render(line)
+			} else {
+				line = "  " + components.ListItemStyle.Render(line)
+			}
+			body.WriteString(truncStyle.Render(line) + "\n")
+		}
+	}
+
+	footer := components.Help("↑/↓", "variant") + "  " +
+		components.Help("enter", "install latest build") + "  " +
+		components.Help("l", "launch") + "  " +
+		components.Help("a", "open in apps") + "  " +
+		components.Help("g", "logcat") + "  " +
+		components.Help("w", "watch") + "  " +
+		components.Help("r", "rescan") + "  " +
+		components.Help("esc", "back")
+
+	rendered := components.RenderLayoutWithScrollableSection(p.state, components.LayoutWithScrollProps{
+		Title:             "Project",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &p.viewport,
+	})
+
+	if p.toast.Visible {
+		rendered = components.RenderOverlay(rendered, p.toast.View(), p.state)
+	}
+
+	return rendered
+}
@@ -0,0 +1,78 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/SakshhamTheCoder/adbt/internal/sqlite"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const tableMaxCellWidth = 32
+
+// renderTable draws rows as fixed-width columns starting at colOffset,
+// with NULL cells muted.
+func renderTable(columns []string, rows [][]string, colOffset int, truncStyle lipgloss.Style) string {
+	colOffset = min(colOffset, max(len(columns)-1, 0))
+	cols := columns[colOffset:]
+	cell := func(row []string, i int) string {
+		i += colOffset
+		if i >= len(row) {
+			return ""
+		}
+		return strings.NewReplacer("\r\n", "↵", "\n", "↵", "\t", " ").Replace(row[i])
+	}
+
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = lipgloss.Width(c)
+	}
+	for _, row := range rows {
+		for i := range cols {
+			if w := lipgloss.Width(cell(row, i)); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	for i := range widths {
+		widths[i] = min(widths[i], tableMaxCellWidth)
+	}
+
+	var out strings.Builder
+
+	var header []string
+	for i, c := range cols {
+		header = append(header, padCell(c, widths[i]))
+	}
+	out.WriteString(truncStyle.Render(components.HelpKeyStyle.Render(strings.Join(header, " │ "))) + "\n")
+
+	for _, row := range rows {
+		var cells []string
+		for i := range cols {
+			value := cell(row, i)
+			padded := padCell(value, widths[i])
+			if value == sqlite.NullValue {
+				padded = components.StatusMuted.Render(padded)
+			}
+			cells = append(cells, padded)
+		}
+		out.WriteString(truncStyle.Render(strings.Join(cells, " │ ")) + "\n")
+	}
+
+	if len(rows) == 0 {
+		out.WriteString(components.StatusMuted.Render("No rows") + "\n")
+	}
+	return out.String()
+}
+
+func padCell(s string, width int) string {
+	if lipgloss.Width(s) > width {
+		runes := []rune(s)
+		for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+			runes = runes[:len(runes)-1]
+		}
+		s = string(runes) + "…"
+	}
+	return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+}
@@ -0,0 +1,362 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const customTraceConfig = "Custom config file"
+
+// traceFinalizeTimeout is how long perfetto may take to flush after the
+// configured duration before the trace is stopped forcibly.
+const traceFinalizeTimeout = time.Minute
+
+type tracePhase int
+
+const (
+	traceIdle tracePhase = iota
+	traceStarting
+	traceRecording
+	traceStopping
+	tracePulling
+)
+
+type traceTickMsg time.Time
+
+type Trace struct {
+	state *state.AppState
+
+	form  components.FormModal
+	toast components.Toast
+
+	phase     tracePhase
+	preset    string
+	pkg       string
+	localDir  string
+	pid       int
+	remote    string
+	startedAt time.Time
+	duration  time.Duration
+
+	lastTrace string
+	lastError error
+}
+
+func NewTrace(state *state.AppState) *Trace {
+	return &Trace{state: state}
+}
+
+func (t *Trace) Init() tea.Cmd {
+	if !t.state.HasDevice() {
+		return nil
+	}
+	t.showForm()
+	return nil
+}
+
+func (t *Trace) showForm() {
+	options := make([]string, 0, len(adb.PerfettoPresets)+1)
+	for _, p := range adb.PerfettoPresets {
+		options = append(options, p.Name)
+	}
+	options = append(options, customTraceConfig)
+
+	preset := t.preset
+	if preset == "" {
+		preset = options[0]
+	}
+	pkg := t.pkg
+	if pkg == "" {
+		pkg = t.state.SelectedPackage
+	}
+
+	t.form.Show("System Trace", []components.FormField{
+		{Label: "Preset", Type: components.FormFieldSelect, Options: options, Value: preset},
+		{Label: "Config File", Placeholder: "only for custom config (.pbtxt)"},
+		{Label: "Package", Value: pkg, Placeholder: "optional, used by app startup"},
+		{Label: "Duration (s)", Value: "10"},
+		{Label: "Output Dir", Value: defaultTraceDir()},
+	})
+}
+
+func defaultTraceDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	return filepath.Join(home, "Downloads", "adbt-traces")
+}
+
+func (t *Trace) InputActive() bool {
+	return t.form.Visible
+}
+
+func (t *Trace) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	t.toast.Update(msg)
+
+	if t.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			t.form.Hide()
+			return t, t.start(msg.Values)
+		case components.FormCancelMsg:
+			t.form.Hide()
+			return t, nil
+		}
+		return t, t.form.Update(msg)
+	}
+
+	serial := t.state.DeviceSerial()
+
+	switch msg := msg.(type) {
+	case adb.TraceStartedMsg:
+		if t.phase != traceStarting {
+			return t, nil
+		}
+		if msg.Error != nil {
+			return t, t.fail(msg.Error)
+		}
+		t.phase = traceRecording
+		t.pid = msg.PID
+		t.remote = msg.RemotePath
+		t.duration = msg.Duration
+		t.startedAt = time.Now()
+		return t, traceTickCmd()
+
+	case traceTickMsg:
+		if t.phase != traceRecording && t.phase != traceStopping {
+			return t, nil
+		}
+		elapsed := time.Since(t.startedAt)
+		if elapsed > t.duration+traceFinalizeTimeout && t.phase == traceRecording {
+			t.phase = traceStopping
+			return t, tea.Batch(adb.StopTraceCmd(serial, t.pid), traceTickCmd())
+		}
+		if elapsed >= t.duration || t.phase == traceStopping {
+			return t, tea.Batch(adb.CheckTraceCmd(serial, t.pid), traceTickCmd())
+		}
+		return t, traceTickCmd()
+
+	case adb.TraceStatusMsg:
+		if t.phase != traceRecording && t.phase != traceStopping {
+			return t, nil
+		}
+		if msg.Error != nil || msg.Running {
+			return t, nil
+		}
+		t.phase = tracePulling
+		return t, adb.PullTraceCmd(serial, t.remote, t.localDir)
+
+	case adb.TracePulledMsg:
+		if t.phase != tracePulling {
+			return t, nil
+		}
+		if msg.Error != nil {
+			return t, t.fail(fmt.Errorf("failed to pull trace: %w", msg.Error))
+		}
+		t.phase = traceIdle
+		t.lastTrace = msg.LocalPath
+		var cmd tea.Cmd
+		t.toast, cmd = components.ShowToast(
+			"Trace saved",
+			false,
+			2*time.Second,
+		)
+		return t, cmd
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "n":
+			if t.phase == traceIdle {
+				t.showForm()
+			}
+		case "s":
+			if t.phase == traceRecording {
+				t.phase = traceStopping
+				return t, adb.StopTraceCmd(serial, t.pid)
+			}
+		case "esc":
+			if t.phase != traceIdle {
+				var cmd tea.Cmd
+				t.toast, cmd = components.ShowToast(
+					"Trace in progress, press s to stop it first",
+					true,
+					2*time.Second,
+				)
+				return t, cmd
+			}
+		}
+	}
+
+	return t, nil
+}
+
+func (t *Trace) start(values []string) tea.Cmd {
+	if len(values) < 5 {
+		return nil
+	}
+
+	t.preset = values[0]
+	configPath := expandHome(strings.TrimSpace(values[1]))
+	t.pkg = strings.TrimSpace(values[2])
+	t.localDir = expandHome(strings.TrimSpace(values[4]))
+	if t.localDir == "" {
+		t.localDir = defaultTraceDir()
+	}
+
+	seconds, err := strconv.Atoi(strings.TrimSpace(values[3]))
+	if err != nil || seconds < 1 {
+		return t.fail(fmt.Errorf("duration must be a positive number of seconds"))
+	}
+
+	var config string
+	if t.preset == customTraceConfig {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return t.fail(fmt.Errorf("cannot read config: %w", err))
+		}
+		config = string(data)
+	} else {
+		for _, p := range adb.PerfettoPresets {
+			if p.Name == t.preset {
+				config = p.Config
+			}
+		}
+	}
+
+	config, duration := adb.BuildTraceConfig(config, t.pkg, time.Duration(seconds)*time.Second)
+
+	device := t.state.DeviceSerial()
+	if dev := t.state.SelectedDevice(); dev != nil && dev.Model != "" {
+		device = dev.Model
+	}
+
+	t.phase = traceStarting
+	t.lastError = nil
+	return adb.StartTraceCmd(
+		t.state.DeviceSerial(),
+		config,
+		adb.TraceFileName(device, t.pkg, time.Now()),
+		duration,
+	)
+}
+
+func (t *Trace) fail(err error) tea.Cmd {
+	t.phase = traceIdle
+	t.lastError = err
+	var cmd tea.Cmd
+	t.toast, cmd = components.ShowToast(
+		"Trace failed",
+		true,
+		3*time.Second,
+	)
+	return cmd
+}
+
+func traceTickCmd() tea.Cmd {
+	return tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
+		return traceTickMsg(t)
+	})
+}
+
+func (t *Trace) View() string {
+	if !t.state.HasDevice() {
+		return components.RenderNoDevice(t.state, "Trace")
+	}
+
+	maxWidth := t.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var body strings.Builder
+	body.WriteString(components.TitleStyle.Render("Perfetto Trace") + "\n\n")
+
+	if t.preset != "" {
+		body.WriteString(components.KeyValueList([]components.KeyValueRow{
+			{Key: "Preset: ", Value: t.preset},
+			{Key: "Package:", Value: valueOrDash(t.pkg)},
+			{Key: "Output: ", Value: valueOrDash(t.localDir)},
+		}))
+		body.WriteString("\n")
+	}
+
+	switch t.phase {
+	case traceStarting:
+		body.WriteString(components.StatusMuted.Render("Starting perfetto...") + "\n")
+
+	case traceRecording, traceStopping:
+		elapsed := time.Since(t.startedAt)
+		percent := float64(elapsed) / float64(t.duration) * 100
+		bar := renderProgressBar(
+			percent,
+			40,
+			lipgloss.NewStyle().Background(components.Primary),
+			lipgloss.NewStyle().Background(lipgloss.Color("#1f2937")),
+		)
+
+		status := fmt.Sprintf(" %s / %s", elapsed.Truncate(time.Second), t.duration)
+		switch {
+		case t.phase == traceStopping:
+			status = " stopping, waiting for perfetto to flush..."
+		case elapsed >= t.duration:
+			status = " finalizing..."
+		}
+		body.WriteString(components.ErrorStyle.Render("● recording") + "\n")
+		body.WriteString(bar + status + "\n")
+
+	case tracePulling:
+		body.WriteString(components.StatusMuted.Render("Pulling trace from device...") + "\n")
+
+	default:
+		if t.lastTrace != "" {
+			body.WriteString(components.StatusConnected.Render("✓ Saved ") + t.lastTrace + "\n")
+			body.WriteString(components.StatusMuted.Render("Open it at ui.perfetto.dev or with trace_processor.") + "\n")
+		} else if t.lastError == nil {
+			body.WriteString(components.StatusMuted.Render("Press [n] to record a trace") + "\n")
+		}
+	}
+
+	if t.lastError != nil {
+		body.WriteString(truncStyle.Render(components.ErrorStyle.Render(t.lastError.Error())) + "\n")
+	}
+
+	var footer string
+	if t.phase == traceRecording {
+		footer = components.Help("s", "stop early")
+	} else {
+		footer = components.Help("n", "new trace") + "  " +
+			components.Help("esc", "back")
+	}
+
+	rendered := components.RenderLayout(t.state, "Trace", body.String(), footer)
+
+	if t.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, t.form, t.state)
+	}
+
+	if t.toast.Visible {
+		rendered = components.RenderOverlay(rendered, t.toast.View(), t.state)
+	}
+
+	return rendered
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return components.StatusMuted.Render("—")
+	}
+	return value
+}
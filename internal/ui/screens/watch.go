@@ -0,0 +1,293 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SakshhamTheCoder/adbt/internal/adb"
+	"github.com/SakshhamTheCoder/adbt/internal/state"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/components"
+	"github.com/SakshhamTheCoder/adbt/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Watch reinstalls and relaunches an app whenever a local APK or build
+// output directory changes, keeping a log of each cycle.
+type Watch struct {
+	state *state.AppState
+
+	path     string
+	watcher  adb.Watcher
+	watchErr error
+	active   bool
+
+	// reinstalling is when the running cycle started, zero when idle.
+	reinstalling time.Time
+	cycles       []adb.WatchCycle
+
+	form  components.FormModal
+	toast components.Toast
+
+	viewport viewport.Model
+}
+
+type watchTickMsg struct {
+	path     string
+	snapshot string
+	err      error
+}
+
+func NewWatch(state *state.AppState) *Watch {
+	return &Watch{
+		state:    state,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (w *Watch) Init() tea.Cmd {
+	if !w.state.HasDevice() {
+		return nil
+	}
+	w.active = true
+	w.showPathForm()
+	return w.tickCmd()
+}
+
+func (w *Watch) Cleanup() tea.Cmd {
+	w.active = false
+	return nil
+}
+
+// tickCmd polls the watched path off the UI goroutine, since a build
+// directory can take a moment to walk.
+func (w *Watch) tickCmd() tea.Cmd {
+	path := w.path
+	return tea.Tick(adb.WatchInterval, func(time.Time) tea.Msg {
+		if path == "" {
+			return watchTickMsg{}
+		}
+		snapshot, err := adb.WatchSnapshot(path)
+		return watchTickMsg{path: path, snapshot: snapshot, err: err}
+	})
+}
+
+func (w *Watch) showPathForm() {
+	path := w.path
+	if p := w.state.Project; path == "" && p != nil {
+		path = p.OutputsPath()
+	}
+	w.form.Show("Watch APK", []components.FormField{
+		{Label: "APK Path", Value: path, Placeholder: ".apk or build output dir, e.g. app/build/outputs/apk"},
+	})
+}
+
+func (w *Watch) InputActive() bool {
+	return w.form.Visible
+}
+
+func (w *Watch) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	w.toast.Update(msg)
+
+	// Polling and reinstalls carry on while the path form is open.
+	switch msg := msg.(type) {
+	case watchTickMsg:
+		return w, w.poll(msg)
+	case adb.WatchCycleMsg:
+		return w, w.finishCycle(msg.Cycle)
+	}
+
+	if w.form.Visible {
+		switch msg := msg.(type) {
+		case components.FormSubmitMsg:
+			w.form.Hide()
+			if len(msg.Values) == 0 || strings.TrimSpace(msg.Values[0]) == "" {
+				return w, w.leaveIfIdle()
+			}
+			return w, w.setPath(expandHome(strings.TrimSpace(msg.Values[0])))
+		case components.FormCancelMsg:
+			w.form.Hide()
+			return w, w.leaveIfIdle()
+		}
+		return w, w.form.Update(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter", "i":
+			if w.path != "" && w.reinstalling.IsZero() {
+				return w, w.reinstall()
+			}
+		case "p":
+			w.showPathForm()
+		case "c":
+			w.cycles = nil
+			w.viewport.GotoTop()
+		case "esc":
+			return w, func() tea.Msg {
+				return navigation.SwitchScreenMsg{Screen: "apps"}
+			}
+		default:
+			var cmd tea.Cmd
+			w.viewport, cmd = w.viewport.Update(msg)
+			return w, cmd
+		}
+	}
+
+	return w, nil
+}
+
+func (w *Watch) poll(msg watchTickMsg) tea.Cmd {
+	if !w.active {
+		return nil
+	}
+	if msg.path == "" || msg.path != w.path {
+		return w.tickCmd()
+	}
+
+	w.watchErr = msg.err
+	if msg.err == nil && w.watcher.Changed(msg.snapshot) && w.reinstalling.IsZero() {
+		return tea.Batch(w.reinstall(), w.tickCmd())
+	}
+	return w.tickCmd()
+}
+
+func (w *Watch) finishCycle(cycle adb.WatchCycle) tea.Cmd {
+	w.reinstalling = time.Time{}
+	w.cycles = append(w.cycles, cycle)
+	if cycle.Error != nil {
+		var cmd tea.Cmd
+		w.toast, cmd = components.ShowToast(cycle.Stage+" failed", true, 3*time.Second)
+		return cmd
+	}
+	w.state.SelectPackage(cycle.Package)
+	return nil
+}
+
+// setPath starts watching path. Its current APKs become the baseline, so
+// nothing is installed until the next build.
+func (w *Watch) setPath(path string) tea.Cmd {
+	if _, err := os.Stat(path); err != nil {
+		w.showPathForm()
+		var cmd tea.Cmd
+		w.toast, cmd = components.ShowToast(err.Error(), true, 3*time.Second)
+		return cmd
+	}
+	w.path = path
+	w.watcher = adb.Watcher{}
+	w.watchErr = nil
+	return nil
+}
+
+// leaveIfIdle goes back to the App Manager when the path prompt is
+// dismissed before anything is being watched.
+func (w *Watch) leaveIfIdle() tea.Cmd {
+	if w.path != "" {
+		return nil
+	}
+	return func() tea.Msg {
+		return navigation.SwitchScreenMsg{Screen: "apps"}
+	}
+}
+
+func (w *Watch) reinstall() tea.Cmd {
+	w.reinstalling = time.Now()
+	return adb.ReinstallCmd(w.state.DeviceSerial(), w.path, w.state.SelectedUser)
+}
+
+func (w *Watch) View() string {
+	if !w.state.HasDevice() {
+		return components.RenderNoDevice(w.state, "Watch")
+	}
+
+	maxWidth := w.state.Width - 8
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+	truncStyle := lipgloss.NewStyle().MaxWidth(maxWidth)
+
+	var static strings.Builder
+	static.WriteString(components.TitleStyle.Render("Watch & Reinstall") + "\n")
+	static.WriteString(truncStyle.Render(components.KeyValueList([]components.KeyValueRow{
+		{Key: "  Path:    ", Value: valueOrDash(w.path)},
+		{Key: "  Package: ", Value: valueOrDash(w.lastPackage())},
+		{Key: "  Cycles:  ", Value: w.cycleSummary()},
+	})) + "\n")
+
+	switch {
+	case !w.reinstalling.IsZero():
+		static.WriteString(components.WarningStyle.Render(fmt.Sprintf(
+			"  ● Reinstalling... %s",
+			time.Since(w.reinstalling).Truncate(time.Second),
+		)) + "\n")
+	case w.watchErr != nil:
+		static.WriteString(truncStyle.Render(components.ErrorStyle.Render("  ✗ "+w.watchErr.Error())) + "\n")
+	case w.path != "":
+		static.WriteString(components.StatusMuted.Render("  Waiting for a new build...") + "\n")
+	}
+
+	var body strings.Builder
+	if len(w.cycles) == 0 {
+		body.WriteString(components.StatusMuted.Render("No reinstalls yet. Rebuild the APK or press enter to reinstall now."))
+	}
+	for i := len(w.cycles) - 1; i >= 0; i-- {
+		c := w.cycles[i]
+		line := components.StatusMuted.Render(c.Started.Format("15:04:05") + "  ")
+		if c.Error != nil {
+			line += components.ErrorStyle.Render("✗ " + c.String())
+		} else {
+			line += components.StatusConnected.Render("✓ ") + c.String()
+		}
+		body.WriteString(truncStyle.Render(line) + "\n")
+	}
+
+	footer := components.Help("enter", "reinstall now") + "  " +
+		components.Help("p", "change path") + "  " +
+		components.Help("c", "clear log") + "  " +
+		components.Help("esc", "stop")
+
+	rendered := components.RenderLayoutWithScrollableSection(w.state, components.LayoutWithScrollProps{
+		Title:             "Watch",
+		StaticContent:     static.String(),
+		ScrollableContent: body.String(),
+		Footer:            footer,
+		Viewport:          &w.viewport,
+	})
+
+	if w.form.Visible {
+		rendered = components.RenderFormOverlay(rendered, w.form, w.state)
+	}
+
+	if w.toast.Visible {
+		rendered = components.RenderOverlay(rendered, w.toast.View(), w.state)
+	}
+
+	return rendered
+}
+
+func (w *Watch) lastPackage() string {
+	for i := len(w.cycles) - 1; i >= 0; i-- {
+		if w.cycles[i].Package != "" {
+			return w.cycles[i].Package
+		}
+	}
+	return ""
+}
+
+func (w *Watch) cycleSummary() string {
+	failed := 0
+	for _, c := range w.cycles {
+		if c.Error != nil {
+			failed++
+		}
+	}
+	summary := fmt.Sprintf("%d ok", len(w.cycles)-failed)
+	if failed > 0 {
+		summary += components.ErrorStyle.Render(fmt.Sprintf(", %d failed", failed))
+	}
+	return summary
+}